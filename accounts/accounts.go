@@ -132,6 +132,15 @@ type Wallet interface {
 	IsUnlockedForStaking(account Account) bool
 }
 
+// BlockSigner is an optional wallet extension for signers which refuse to sign
+// arbitrary hashes, but are able to sign Energi PoS block headers. The signer
+// is expected to calculate the signature hash of the header on its own.
+type BlockSigner interface {
+	// SignBlock requests the wallet to sign the given block header. The
+	// returned signature is in the [R || S || V] format where V is 0 or 1.
+	SignBlock(account Account, header *types.Header) ([]byte, error)
+}
+
 // Backend is a "wallet provider" that may contain a batch of accounts they can
 // sign transactions with and upon request, do so.
 type Backend interface {
@@ -0,0 +1,265 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package external implements a wallet backend which delegates all signing
+// to an external signer like clef over its external API.
+package external
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "energi.world/core/gen3"
+	"energi.world/core/gen3/accounts"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rlp"
+	"energi.world/core/gen3/rpc"
+)
+
+const (
+	// ExternalScheme is the URL scheme of external signer wallets.
+	ExternalScheme = "extapi"
+
+	// Accounts are cached to avoid an RPC round trip on every PoS attempt
+	accountCacheTTL = 30 * time.Second
+)
+
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+func (eb *ExternalBackend) Wallets() []accounts.Wallet {
+	return eb.signers
+}
+
+func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// NewExternalBackend creates a wallet backend connected to the external
+// signer listening at the given endpoint. Only the given coinbases are
+// considered for staking, they must match the staking rules of the signer.
+func NewExternalBackend(endpoint string, coinbases []common.Address) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint, coinbases)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{
+		signers: []accounts.Wallet{signer},
+	}, nil
+}
+
+// ExternalSigner is a wallet which asks the external signer for every
+// signature. Approval is entirely up to the rules of the external signer.
+type ExternalSigner struct {
+	client    *rpc.Client
+	endpoint  string
+	coinbases map[common.Address]bool
+
+	cacheMtx    sync.Mutex
+	cache       []accounts.Account
+	cacheExpiry time.Time
+}
+
+func NewExternalSigner(endpoint string, coinbases []common.Address) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newExternalSigner(client, endpoint, coinbases), nil
+}
+
+func newExternalSigner(client *rpc.Client, endpoint string, coinbases []common.Address) *ExternalSigner {
+	es := &ExternalSigner{
+		client:    client,
+		endpoint:  endpoint,
+		coinbases: make(map[common.Address]bool, len(coinbases)),
+	}
+	for _, addr := range coinbases {
+		es.coinbases[addr] = true
+	}
+	return es
+}
+
+func (es *ExternalSigner) URL() accounts.URL {
+	return accounts.URL{
+		Scheme: ExternalScheme,
+		Path:   es.endpoint,
+	}
+}
+
+func (es *ExternalSigner) Status() (string, error) {
+	if _, err := es.listAccounts(); err != nil {
+		return "Unavailable", err
+	}
+	return "Connected", nil
+}
+
+func (es *ExternalSigner) Open(passphrase string) error {
+	return accounts.ErrNotSupported
+}
+
+func (es *ExternalSigner) Close() error {
+	return nil
+}
+
+func (es *ExternalSigner) Accounts() []accounts.Account {
+	es.cacheMtx.Lock()
+	defer es.cacheMtx.Unlock()
+
+	if time.Now().Before(es.cacheExpiry) {
+		return es.cache
+	}
+
+	res, err := es.listAccounts()
+	if err != nil {
+		log.Error("Failed to list external signer accounts", "endpoint", es.endpoint, "err", err)
+		// NOTE: keep the stale cache, the signer may be temporary unavailable
+		return es.cache
+	}
+
+	es.cache = res
+	es.cacheExpiry = time.Now().Add(accountCacheTTL)
+	return es.cache
+}
+
+func (es *ExternalSigner) listAccounts() ([]accounts.Account, error) {
+	var res []common.Address
+	if err := es.client.Call(&res, "account_list"); err != nil {
+		return nil, err
+	}
+
+	accs := make([]accounts.Account, 0, len(res))
+	for _, addr := range res {
+		accs = append(accs, accounts.Account{
+			Address: addr,
+			URL:     es.URL(),
+		})
+	}
+	return accs, nil
+}
+
+func (es *ExternalSigner) Contains(account accounts.Account) bool {
+	for _, a := range es.Accounts() {
+		if a.Address == account.Address && (account.URL == (accounts.URL{}) || account.URL == es.URL()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (es *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (es *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain ethereum.ChainStateReader) {
+	log.Error("Operation not supported on external signers")
+}
+
+// SignHash is not supported: external signers never sign arbitrary hashes.
+func (es *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// sendTxArgs is the transaction format of the external API.
+type sendTxArgs struct {
+	From     common.MixedcaseAddress  `json:"from"`
+	To       *common.MixedcaseAddress `json:"to"`
+	Gas      hexutil.Uint64           `json:"gas"`
+	GasPrice hexutil.Big              `json:"gasPrice"`
+	Value    hexutil.Big              `json:"value"`
+	Nonce    hexutil.Uint64           `json:"nonce"`
+	Data     *hexutil.Bytes           `json:"data"`
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (es *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &sendTxArgs{
+		From:     common.NewMixedcaseAddress(account.Address),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     &data,
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+
+	var res signTransactionResult
+	if err := es.client.Call(&res, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+
+	// The external signer is configured with its own chain ID
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewEIP155Signer(chainID)
+	}
+	sender, err := types.Sender(signer, res.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("external signer returned an invalid transaction: %v", err)
+	}
+	if sender != account.Address {
+		return nil, fmt.Errorf("external signer returned a transaction from %s", sender.Hex())
+	}
+	return res.Tx, nil
+}
+
+func (es *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+func (es *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// IsUnlockedForStaking reports only signer accounts on the staking coinbase
+// list. The signer rejects block signatures for any other coinbase anyway.
+func (es *ExternalSigner) IsUnlockedForStaking(account accounts.Account) bool {
+	return es.coinbases[account.Address] && es.Contains(account)
+}
+
+// SignBlock implements accounts.BlockSigner.
+func (es *ExternalSigner) SignBlock(account accounts.Account, header *types.Header) ([]byte, error) {
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := common.NewMixedcaseAddress(account.Address)
+
+	var res hexutil.Bytes
+	if err := es.client.Call(&res, "account_signEnergiBlock", &signer, hexutil.Bytes(enc)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
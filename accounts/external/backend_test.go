@@ -0,0 +1,114 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"energi.world/core/gen3/accounts"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/rlp"
+	"energi.world/core/gen3/rpc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FakeSigner mimics the external API of clef
+type FakeSigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+func (f *FakeSigner) List(ctx context.Context) ([]common.Address, error) {
+	return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}, nil
+}
+
+// FakeTxArgs and FakeTxResult are exported copies for the RPC server
+type FakeTxArgs sendTxArgs
+type FakeTxResult signTransactionResult
+
+func (f *FakeSigner) SignTransaction(ctx context.Context, args FakeTxArgs, methodSelector *string) (*FakeTxResult, error) {
+	tx := types.NewTransaction(
+		uint64(args.Nonce), args.To.Address(), args.Value.ToInt(),
+		uint64(args.Gas), args.GasPrice.ToInt(), *args.Data)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(f.chainID), f.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := rlp.EncodeToBytes(signed)
+	return &FakeTxResult{raw, signed}, nil
+}
+
+func (f *FakeSigner) SignEnergiBlock(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error) {
+	var h types.Header
+	if err := rlp.DecodeBytes(header, &h); err != nil {
+		return nil, err
+	}
+	return crypto.Sign(h.Hash().Bytes(), f.key)
+}
+
+func newTestSigner(t *testing.T, fake *FakeSigner, coinbases ...common.Address) *ExternalSigner {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", fake); err != nil {
+		t.Fatal(err)
+	}
+
+	return newExternalSigner(rpc.DialInProc(server), "inproc", coinbases)
+}
+
+func TestExternalSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	fake := &FakeSigner{key: key, chainID: big.NewInt(49797)}
+	account := accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}
+	other := accounts.Account{Address: common.HexToAddress("0x1234")}
+	signer := newTestSigner(t, fake, account.Address, other.Address)
+
+	assert.Equal(t, []accounts.Account{{Address: account.Address, URL: signer.URL()}}, signer.Accounts())
+	assert.True(t, signer.Contains(account))
+	assert.True(t, signer.IsUnlockedForStaking(account))
+	assert.False(t, signer.IsUnlockedForStaking(other))
+
+	// Accounts off the staking coinbase list are not used for staking
+	assert.False(t, newTestSigner(t, fake).IsUnlockedForStaking(account))
+
+	_, err := signer.SignHash(account, make([]byte, 32))
+	assert.Equal(t, accounts.ErrNotSupported, err)
+
+	tx := types.NewTransaction(1, common.HexToAddress("0x300"), big.NewInt(10), 21000, big.NewInt(1), []byte{})
+	signed, err := signer.SignTx(account, tx, fake.chainID)
+	assert.Empty(t, err)
+	assert.Equal(t, tx.Nonce(), signed.Nonce())
+
+	// The chain ID must match
+	_, err = signer.SignTx(account, tx, big.NewInt(1))
+	assert.NotEmpty(t, err)
+
+	header := &types.Header{Coinbase: account.Address, Number: big.NewInt(2), Difficulty: big.NewInt(1)}
+	sig, err := signer.SignBlock(account, header)
+	assert.Empty(t, err)
+	pubkey, err := crypto.SigToPub(header.Hash().Bytes(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pubkey))
+}
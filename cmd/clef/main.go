@@ -51,10 +51,10 @@ import (
 )
 
 // ExternalAPIVersion -- see extapi_changelog.md
-const ExternalAPIVersion = "4.0.0"
+const ExternalAPIVersion = "4.1.0"
 
 // InternalAPIVersion -- see intapi_changelog.md
-const InternalAPIVersion = "3.0.0"
+const InternalAPIVersion = "3.1.0"
 
 const legalWarning = `
 WARNING!
@@ -116,6 +116,10 @@ var (
 		Usage: "Enable rule-engine",
 		Value: "rules.json",
 	}
+	stakingFlag = cli.StringFlag{
+		Name:  "staking-coinbases",
+		Usage: "Comma separated list of coinbases for which Energi PoS block signatures are approved automatically",
+	}
 	stdiouiFlag = cli.BoolFlag{
 		Name: "stdio-ui",
 		Usage: "Use STDIN/STDOUT as a channel for an external UI. " +
@@ -196,6 +200,7 @@ func init() {
 		customDBFlag,
 		auditLogFlag,
 		ruleFlag,
+		stakingFlag,
 		stdiouiFlag,
 		testFlag,
 		advancedMode,
@@ -405,6 +410,20 @@ func signer(c *cli.Context) error {
 				log.Info("Rule engine configured", "file", c.String(ruleFlag.Name))
 			}
 		}
+
+		// Staking rules go first, everything else is passed to the other handlers
+		if list := c.GlobalString(stakingFlag.Name); list != "" {
+			var coinbases []common.Address
+			for _, addr := range strings.Split(list, ",") {
+				addr = strings.TrimSpace(addr)
+				if !common.IsHexAddress(addr) {
+					utils.Fatalf("Invalid staking coinbase: %s", addr)
+				}
+				coinbases = append(coinbases, common.HexToAddress(addr))
+			}
+			ui = rules.NewStakingRules(ui, pwStorage, coinbases)
+			log.Info("Staking rules configured", "coinbases", coinbases)
+		}
 	}
 
 	apiImpl := core.NewSignerAPI(
@@ -31,13 +31,13 @@ import (
 	"energi.world/core/gen3/console"
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/state/pruner"
 	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/eth/downloader"
 	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/event"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/trie"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -168,6 +168,26 @@ Remove blockchain and state databases`,
 The arguments are interpreted as block numbers or hashes.
 Use "ethereum dump 0" to dump the genesis block.`,
 	}
+	pruneStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune-state",
+		Usage:     "Delete state data not needed for recent blocks",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.TestnetFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The prune-state command keeps the state of the target block, the states of
+the stake maturity period before it and the newer states. All the other
+state trie nodes and contract codes get deleted.
+
+The target defaults to the latest validated checkpoint. The node must not
+be running.`,
+	}
 )
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -246,15 +266,13 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
+	stats, err := chainDb.Stat("leveldb.stats")
 	if err != nil {
 		utils.Fatalf("Failed to read database stats: %v", err)
 	}
 	fmt.Println(stats)
 
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
+	ioStats, err := chainDb.Stat("leveldb.iostats")
 	if err != nil {
 		utils.Fatalf("Failed to read database iostats: %v", err)
 	}
@@ -279,18 +297,18 @@ func importChain(ctx *cli.Context) error {
 	// Compact the entire database to more accurately measure disk io and print the stats
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
+	if err = chainDb.Compact(nil, nil); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 
-	stats, err = db.LDB().GetProperty("leveldb.stats")
+	stats, err = chainDb.Stat("leveldb.stats")
 	if err != nil {
 		utils.Fatalf("Failed to read database stats: %v", err)
 	}
 	fmt.Println(stats)
 
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
+	ioStats, err = chainDb.Stat("leveldb.iostats")
 	if err != nil {
 		utils.Fatalf("Failed to read database iostats: %v", err)
 	}
@@ -337,7 +355,7 @@ func importPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -353,7 +371,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -403,7 +421,7 @@ func copyDb(ctx *cli.Context) error {
 	// Compact the entire database to remove any sync overhead
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
+	if err = chainDb.Compact(nil, nil); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
@@ -414,7 +432,11 @@ func copyDb(ctx *cli.Context) error {
 func removeDB(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 
-	for _, name := range []string{"chaindata", "lightchaindata"} {
+	names := []string{"chaindata", "lightchaindata"}
+	if ancient := ctx.GlobalString(utils.AncientFlag.Name); ancient != "" {
+		names = append(names, ancient)
+	}
+	for _, name := range names {
 		// Ensure the database exists in the first place
 		logger := log.New("database", name)
 
@@ -466,6 +488,45 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func pruneState(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	var target *types.Header
+	switch arg := ctx.Args().First(); {
+	case arg == "":
+		header, err := pruner.LatestCheckpoint(chain)
+		if err != nil {
+			utils.Fatalf("Failed to find the target block: %v", err)
+		}
+		target = header
+	case hashish(arg):
+		target = chain.GetHeaderByHash(common.HexToHash(arg))
+	default:
+		num, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+		target = chain.GetHeaderByNumber(num)
+	}
+	if target == nil {
+		utils.Fatalf("Target block not found")
+	}
+
+	p, err := pruner.NewPruner(chain, chainDb)
+	if err != nil {
+		utils.Fatalf("Failed to create pruner: %v", err)
+	}
+	if err = p.Prune(target); err != nil {
+		utils.Fatalf("Failed to prune state: %v", err)
+	}
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
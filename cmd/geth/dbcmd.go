@@ -0,0 +1,204 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of Energi Core.
+//
+// Energi Core is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Energi Core is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Energi Core. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"energi.world/core/gen3/cmd/utils"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/log"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dbFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.CacheFlag,
+		utils.TestnetFlag,
+	}
+
+	dbCommand = cli.Command{
+		Name:      "db",
+		Usage:     "Low level database operations",
+		ArgsUsage: "",
+		Category:  "DATABASE COMMANDS",
+		Description: `
+The db commands inspect and repair the chain database directly. The node
+must not be running.
+
+Keys are given either as 0x-prefixed hex or as plain strings, e.g. LastBlock.
+Values are given as 0x-prefixed hex.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Inspect the storage size of each type of data in the database",
+				ArgsUsage: "",
+				Action:    utils.MigrateFlags(inspectDB),
+				Category:  "DATABASE COMMANDS",
+				Flags:     dbFlags,
+				Description: `
+The inspect command iterates the entire database and reports the size and
+the number of entries per data type, including the ancient chain segment.`,
+			},
+			{
+				Name:      "get",
+				Usage:     "Show the value of a database key",
+				ArgsUsage: "<key>",
+				Action:    utils.MigrateFlags(dbGet),
+				Category:  "DATABASE COMMANDS",
+				Flags:     dbFlags,
+			},
+			{
+				Name:      "put",
+				Usage:     "Set the value of a database key (WARNING: may corrupt the database)",
+				ArgsUsage: "<key> <hex-value>",
+				Action:    utils.MigrateFlags(dbPut),
+				Category:  "DATABASE COMMANDS",
+				Flags:     dbFlags,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a database key (WARNING: may corrupt the database)",
+				ArgsUsage: "<key>",
+				Action:    utils.MigrateFlags(dbDelete),
+				Category:  "DATABASE COMMANDS",
+				Flags:     dbFlags,
+			},
+		},
+	}
+)
+
+func inspectDB(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		utils.Fatalf("This command accepts no arguments.")
+	}
+	stack := makeFullNode(ctx)
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	stats, err := rawdb.InspectDatabase(db)
+	if err != nil {
+		utils.Fatalf("Failed to inspect database: %v", err)
+	}
+
+	var (
+		total   common.StorageSize
+		entries uint64
+		table   = tablewriter.NewWriter(os.Stdout)
+	)
+	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
+	for _, stat := range stats {
+		table.Append([]string{stat.Database, stat.Category, stat.Size.String(), fmt.Sprint(stat.Count)})
+		total += stat.Size
+		if stat.Database != "Ancient store" {
+			entries += stat.Count
+		}
+	}
+	table.SetFooter([]string{"", "Total", total.String(), fmt.Sprint(entries)})
+	table.Render()
+	return nil
+}
+
+func dbGet(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires exactly one argument.")
+	}
+	key, err := parseDBKey(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Invalid key: %v", err)
+	}
+
+	stack := makeFullNode(ctx)
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	data, err := db.Get(key)
+	if err != nil {
+		utils.Fatalf("Failed to get key %#x: %v", key, err)
+	}
+	fmt.Printf("key %#x: %#x\n", key, data)
+	return nil
+}
+
+func dbPut(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires exactly two arguments.")
+	}
+	key, err := parseDBKey(ctx.Args().Get(0))
+	if err != nil {
+		utils.Fatalf("Invalid key: %v", err)
+	}
+	value, err := hexutil.Decode(ctx.Args().Get(1))
+	if err != nil {
+		utils.Fatalf("Invalid value: %v", err)
+	}
+
+	stack := makeFullNode(ctx)
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	if old, err := db.Get(key); err == nil {
+		fmt.Printf("Previous value: %#x\n", old)
+	}
+	if err = db.Put(key, value); err != nil {
+		utils.Fatalf("Failed to put key %#x: %v", key, err)
+	}
+	log.Info("Updated database key", "key", hexutil.Bytes(key))
+	return nil
+}
+
+func dbDelete(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires exactly one argument.")
+	}
+	key, err := parseDBKey(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Invalid key: %v", err)
+	}
+
+	stack := makeFullNode(ctx)
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	if old, err := db.Get(key); err == nil {
+		fmt.Printf("Previous value: %#x\n", old)
+	}
+	if err = db.Delete(key); err != nil {
+		utils.Fatalf("Failed to delete key %#x: %v", key, err)
+	}
+	log.Info("Deleted database key", "key", hexutil.Bytes(key))
+	return nil
+}
+
+// parseDBKey decodes a 0x-prefixed hex key or takes the raw string otherwise.
+func parseDBKey(arg string) ([]byte, error) {
+	if strings.HasPrefix(arg, "0x") || strings.HasPrefix(arg, "0X") {
+		return hexutil.Decode(arg)
+	}
+	if arg == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+	return []byte(arg), nil
+}
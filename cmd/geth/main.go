@@ -61,9 +61,12 @@ var (
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
+		utils.AncientFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
 		utils.NoEphemeralFlag,
+		utils.ExternalSignerFlag,
+		utils.ExternalSignerCoinbasesFlag,
 		utils.DashboardEnabledFlag,
 		utils.DashboardAddrFlag,
 		utils.DashboardPortFlag,
@@ -91,6 +94,8 @@ var (
 		utils.LightPeersFlag,
 		utils.LightKDFFlag,
 		utils.WhitelistFlag,
+		utils.CheckpointPolicyFlag,
+		utils.CheckpointQuorumFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
 		utils.CacheTrieFlag,
@@ -197,6 +202,9 @@ func init() {
 		copydbCommand,
 		removedbCommand,
 		dumpCommand,
+		pruneStateCommand,
+		// See dbcmd.go:
+		dbCommand,
 		// See monitorcmd.go:
 		monitorCommand,
 		// See accountcmd.go:
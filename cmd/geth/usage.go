@@ -71,9 +71,12 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			configFileFlag,
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.NoEphemeralFlag,
+			utils.ExternalSignerFlag,
+			utils.ExternalSignerCoinbasesFlag,
 			utils.NetworkIdFlag,
 			utils.TestnetFlag,
 			utils.SyncModeFlag,
@@ -84,6 +87,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.LightPeersFlag,
 			utils.LightKDFFlag,
 			utils.WhitelistFlag,
+			utils.CheckpointPolicyFlag,
+			utils.CheckpointQuorumFlag,
 		},
 	},
 	{
@@ -123,6 +123,10 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	AncientFlag = DirectoryFlag{
+		Name:  "datadir.ancient",
+		Usage: "Data directory for finalized blocks (default = inside the chaindata)",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -135,6 +139,16 @@ var (
 		Name:  "noephemeral",
 		Usage: "Disables Ephemeral transaction signing account support",
 	}
+	ExternalSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer (url or path to ipc file) for staking and autocollateral",
+		Value: "",
+	}
+	ExternalSignerCoinbasesFlag = cli.StringFlag{
+		Name:  "signer.staking-coinbases",
+		Usage: "Comma separated list of external signer accounts to stake with (must match its staking coinbases)",
+		Value: "",
+	}
 	NetworkIdFlag = cli.Uint64Flag{
 		Name:  "networkid",
 		Usage: "Network identifier (integer, 39797=EnergiMain, 49797=EnergiTest)",
@@ -686,6 +700,14 @@ var (
 		Name:  "publicservice",
 		Usage: "Enable security restrictions and tweaks to operate as a public service",
 	}
+	CheckpointPolicyFlag = cli.StringFlag{
+		Name:  "checkpoint.policy",
+		Usage: `Remote checkpoint acceptance rule: "cpp", "cpp+mn" or "mn" (default = chain config)`,
+	}
+	CheckpointQuorumFlag = cli.Uint64Flag{
+		Name:  "checkpoint.quorum",
+		Usage: "Percent of the active masternode collateral required to sign a checkpoint (default = chain config)",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -1064,6 +1086,19 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(NoEphemeralFlag.Name) {
 		cfg.NoEphemeral = ctx.GlobalBool(NoEphemeralFlag.Name)
 	}
+	if ctx.GlobalIsSet(ExternalSignerFlag.Name) {
+		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
+	}
+	if ctx.GlobalIsSet(ExternalSignerCoinbasesFlag.Name) {
+		cfg.ExternalSignerCoinbases = nil
+		for _, addr := range strings.Split(ctx.GlobalString(ExternalSignerCoinbasesFlag.Name), ",") {
+			addr = strings.TrimSpace(addr)
+			if !common.IsHexAddress(addr) {
+				Fatalf("Invalid staking coinbase: %s", addr)
+			}
+			cfg.ExternalSignerCoinbases = append(cfg.ExternalSignerCoinbases, common.HexToAddress(addr))
+		}
+	}
 	if ctx.GlobalIsSet(PublicServiceFlag.Name) && ctx.GlobalBool(PublicServiceFlag.Name) {
 		log.Info("Enforcing NoUSB and enabled Ephemeral account")
 		cfg.NoEphemeral = false
@@ -1266,6 +1301,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 	}
 	cfg.DatabaseHandles = makeDatabaseHandles()
+	if ctx.GlobalIsSet(AncientFlag.Name) {
+		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1341,6 +1379,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(PublicServiceFlag.Name) {
 		cfg.PublicService = ctx.GlobalBool(PublicServiceFlag.Name)
 	}
+	if ctx.GlobalIsSet(CheckpointPolicyFlag.Name) {
+		cfg.CheckpointPolicy = ctx.GlobalString(CheckpointPolicyFlag.Name)
+	}
+	if ctx.GlobalIsSet(CheckpointQuorumFlag.Name) {
+		cfg.CheckpointQuorum = ctx.GlobalUint64(CheckpointQuorumFlag.Name)
+	}
 
 	// Override any default configs for hard coded networks.
 	switch {
@@ -1522,11 +1566,15 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 		cache   = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 		handles = makeDatabaseHandles()
 	)
-	name := "chaindata"
+	var (
+		chainDb ethdb.Database
+		err     error
+	)
 	if ctx.GlobalString(SyncModeFlag.Name) == "light" {
-		name = "lightchaindata"
+		chainDb, err = stack.OpenDatabase("lightchaindata", cache, handles)
+	} else {
+		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.GlobalString(AncientFlag.Name), "")
 	}
-	chainDb, err := stack.OpenDatabase(name, cache, handles)
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
@@ -221,6 +221,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bc.checkpoints.setup(bc)
 	// Take ownership of this particular state
 	go bc.update()
+	// Move finalized blocks out of the key-value store
+	if _, ok := db.(ethdb.AncientStore); ok {
+		bc.wg.Add(1)
+		go bc.freeze()
+	}
 	return bc, nil
 }
 
@@ -307,6 +312,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 		rawdb.DeleteBody(db, hash, num)
 	}
 	bc.hc.SetHead(head, delFn)
+	bc.truncateAncients(head + 1)
 	currentHeader := bc.hc.CurrentHeader()
 
 	// Clear out any stale content from the caches
@@ -1534,6 +1540,9 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	} else {
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number(), "oldhash", oldBlock.Hash(), "newnum", newBlock.Number(), "newhash", newBlock.Hash())
 	}
+	// Frozen blocks of the old chain are not canonical anymore
+	bc.truncateAncients(commonBlock.NumberU64() + 1)
+
 	// Insert the new chain, taking care of the proper incremental order
 	for i := len(newChain) - 1; i >= 0; i-- {
 		// Insert the block in the canonical way, re-writing history
@@ -1592,6 +1601,7 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 			bc.chainFeed.Send(ev)
 
 		case ChainHeadEvent:
+			bc.checkpoints.applyFuture(bc)
 			bc.chainHeadFeed.Send(ev)
 
 		case ChainSideEvent:
@@ -0,0 +1,313 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"energi.world/core/gen3/accounts/abi"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/params"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+var (
+	// ErrCheckpointDeferred is returned if the checkpoint can be verified only
+	// once its block is known.
+	ErrCheckpointDeferred = errors.New("checkpoint verification is deferred until its block is known")
+
+	// ErrCheckpointNoSignatures and ErrCheckpointBadSignature are returned for
+	// remote checkpoints which were not properly signed by their source.
+	ErrCheckpointNoSignatures = errors.New("missing checkpoint signatures")
+	ErrCheckpointBadSignature = errors.New("invalid CPP signature")
+
+	errCheckpointNoQuorum = errors.New("checkpoint masternode quorum is not reached")
+	errCheckpointPolicy   = errors.New("unknown checkpoint policy")
+	errCheckpointQuorum   = errors.New("checkpoint quorum is above 100 percent")
+
+	checkpointMNRegAbi abi.ABI
+)
+
+func init() {
+	var err error
+
+	checkpointMNRegAbi, err = abi.JSON(strings.NewReader(energi_abi.IMasternodeRegistryV2ABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// recoverCheckpointSigner extracts the address which has signed the checkpoint.
+func (cm *checkpointManager) recoverCheckpointSigner(
+	cp *Checkpoint,
+	sig CheckpointSignature,
+) (common.Address, error) {
+	sig = append(CheckpointSignature{}, sig...)
+
+	// Drop Ecrecover workaround of the contract signatures
+	if len(sig) == 65 && sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	pubkey, err := crypto.Ecrecover(cm.hashToSign(cp), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return signer, nil
+}
+
+// verifySignatures checks remote checkpoint signatures against the
+// checkpoint policy of the node, if set, or of the chain config.
+func (cm *checkpointManager) verifySignatures(
+	chain CheckpointChain,
+	cp *Checkpoint,
+	sigs []CheckpointSignature,
+) error {
+	if len(sigs) == 0 {
+		log.Warn("Checkpoint: missing signatures",
+			"num", cp.Number, "hash", cp.Hash)
+		return ErrCheckpointNoSignatures
+	}
+
+	nrgconf := chain.Config().Energi
+	policy := params.CheckpointPolicyCPP
+	if cm.policy != "" {
+		policy = cm.policy
+	} else if nrgconf != nil && nrgconf.CheckpointPolicy != "" {
+		policy = nrgconf.CheckpointPolicy
+	}
+
+	switch policy {
+	case params.CheckpointPolicyCPP, params.CheckpointPolicyCPPAndMN:
+		// The first one must always be CPP_signer
+		signer, err := cm.recoverCheckpointSigner(cp, sigs[0])
+		if err != nil {
+			log.Warn("Checkpoint: failed to extract signature",
+				"num", cp.Number, "hash", cp.Hash, "err", err)
+			return ErrCheckpointBadSignature
+		}
+
+		// Check the primary signature
+		if nrgconf == nil || signer != nrgconf.CPPSigner {
+			log.Warn("Checkpoint: invalid CPP signature", "num", cp.Number, "hash", cp.Hash)
+			return ErrCheckpointBadSignature
+		}
+
+		if policy == params.CheckpointPolicyCPP {
+			return nil
+		}
+
+		return cm.verifyQuorum(chain, cp, sigs[1:])
+
+	case params.CheckpointPolicyMN:
+		return cm.verifyQuorum(chain, cp, sigs)
+
+	default:
+		log.Error("Checkpoint: unknown policy", "policy", policy)
+		return errCheckpointPolicy
+	}
+}
+
+// verifyQuorum checks that the masternodes which have signed the checkpoint
+// own enough of the active collateral.
+func (cm *checkpointManager) verifyQuorum(
+	chain CheckpointChain,
+	cp *Checkpoint,
+	sigs []CheckpointSignature,
+) error {
+	quorum := params.DefaultCheckpointQuorum
+	if cm.quorum != 0 {
+		quorum = cm.quorum
+	} else if nrgconf := chain.Config().Energi; nrgconf != nil && nrgconf.CheckpointQuorum != 0 {
+		quorum = nrgconf.CheckpointQuorum
+	}
+
+	signers := make([]common.Address, 0, len(sigs))
+	known := make(map[common.Address]bool, len(sigs))
+
+	for _, sig := range sigs {
+		signer, err := cm.recoverCheckpointSigner(cp, sig)
+		if err != nil {
+			log.Debug("Checkpoint: skipping invalid MN signature",
+				"num", cp.Number, "hash", cp.Hash, "err", err)
+			continue
+		}
+
+		if !known[signer] {
+			known[signer] = true
+			signers = append(signers, signer)
+		}
+	}
+
+	// The quorum is checked against the state of the checkpoint block.
+	// Its verification gets deferred, if the block is not known yet.
+	if !chain.HasBlockAndState(cp.Hash, cp.Number) {
+		log.Debug("Checkpoint: deferring MN quorum check",
+			"num", cp.Number, "hash", cp.Hash)
+		return ErrCheckpointDeferred
+	}
+	header := chain.GetHeader(cp.Hash, cp.Number)
+
+	signed, total, err := chain.CheckpointCollateral(header, signers)
+	if err != nil {
+		log.Warn("Checkpoint: failed to get MN collateral",
+			"num", cp.Number, "hash", cp.Hash, "err", err)
+		return err
+	}
+
+	required := new(big.Int).Mul(total, new(big.Int).SetUint64(quorum))
+	have := new(big.Int).Mul(signed, big.NewInt(100))
+
+	if total.Sign() <= 0 || have.Cmp(required) < 0 {
+		log.Warn("Checkpoint: MN quorum is not reached",
+			"num", cp.Number, "hash", cp.Hash,
+			"signed", signed, "total", total, "quorum", quorum)
+		return errCheckpointNoQuorum
+	}
+
+	return nil
+}
+
+// CheckpointSignersLimit returns the maximum number of meaningful signatures
+// of a checkpoint: one of CPP and one per registered masternode as of the
+// current block. It is zero, if the registry is not available.
+func (bc *BlockChain) CheckpointSignersLimit() int {
+	header := bc.CurrentHeader()
+
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return 0
+	}
+
+	count := new(struct {
+		Active           *big.Int
+		Total            *big.Int
+		ActiveCollateral *big.Int
+		TotalCollateral  *big.Int
+		MaxOfAllTimes    *big.Int
+	})
+	if err = bc.callMasternodeRegistry(header, statedb, count, "count"); err != nil {
+		log.Debug("Checkpoint: failed to get MN count", "err", err)
+		return 0
+	}
+
+	return int(count.Total.Int64()) + 1
+}
+
+// CheckpointCollateral returns the collateral of the active masternodes among
+// signers and the total active collateral as of the specified block.
+func (bc *BlockChain) CheckpointCollateral(
+	header *types.Header,
+	signers []common.Address,
+) (signed, total *big.Int, err error) {
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count := new(struct {
+		Active           *big.Int
+		Total            *big.Int
+		ActiveCollateral *big.Int
+		TotalCollateral  *big.Int
+		MaxOfAllTimes    *big.Int
+	})
+	if err = bc.callMasternodeRegistry(header, statedb, count, "count"); err != nil {
+		return nil, nil, err
+	}
+
+	signed = new(big.Int)
+	mnlist := energi_params.Energi_MasternodeList
+
+	for _, addr := range signers {
+		// Active masternodes are marked in the list on every block
+		if (statedb.GetState(mnlist, addr.Hash()) == common.Hash{}) {
+			continue
+		}
+
+		info := new(struct {
+			Owner          common.Address
+			Ipv4address    uint32
+			Enode          [2][32]byte
+			Collateral     *big.Int
+			AnnouncedBlock *big.Int
+			SwFeatures     *big.Int
+		})
+		if err = bc.callMasternodeRegistry(header, statedb, info, "info", addr); err != nil {
+			return nil, nil, err
+		}
+
+		signed.Add(signed, info.Collateral)
+	}
+
+	return signed, count.ActiveCollateral, nil
+}
+
+func (bc *BlockChain) callMasternodeRegistry(
+	header *types.Header,
+	statedb *state.StateDB,
+	out interface{},
+	method string,
+	args ...interface{},
+) error {
+	data, err := checkpointMNRegAbi.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+
+	mnregistry := energi_params.Energi_MasternodeRegistry
+	msg := types.NewMessage(
+		mnregistry,
+		&mnregistry,
+		0,
+		common.Big0,
+		energi_params.MasternodeCallGas,
+		common.Big0,
+		data,
+		false,
+	)
+
+	rev_id := statedb.Snapshot()
+	defer statedb.RevertToSnapshot(rev_id)
+
+	ctx := NewEVMContext(msg, header, bc, &header.Coinbase)
+	ctx.GasLimit = energi_params.MasternodeCallGas
+	evm := vm.NewEVM(ctx, statedb, bc.Config(), *bc.GetVMConfig())
+
+	gp := new(GasPool).AddGas(energi_params.MasternodeCallGas)
+	output, _, failed, err := ApplyMessage(evm, msg, gp)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errors.New("MasternodeRegistry call failed")
+	}
+
+	return checkpointMNRegAbi.Unpack(out, method, output)
+}
@@ -17,16 +17,18 @@
 package core
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/rawdb"
 	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/event"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/params"
@@ -42,7 +44,10 @@ type CheckpointValidateChain interface {
 type CheckpointChain interface {
 	CheckpointValidateChain
 
+	GetHeader(hash common.Hash, number uint64) *types.Header
+	HasBlockAndState(hash common.Hash, number uint64) bool
 	EnforceCheckpoint(cp Checkpoint) error
+	CheckpointCollateral(header *types.Header, signers []common.Address) (signed, total *big.Int, err error)
 	Config() *params.ChainConfig
 }
 
@@ -82,41 +87,134 @@ type validCheckpoint struct {
 	signatures []CheckpointSignature
 }
 
+// futureCheckpoint is a validated checkpoint for a block which is not
+// known yet. It gets enforced as soon as the chain reaches its height.
 type futureCheckpoint struct {
 	Checkpoint
-	signatures map[common.Address]CheckpointSignature
 }
 
+// deferredCheckpoint is a remote checkpoint which can be verified only
+// against the state of its own block, which is not known yet.
+type deferredCheckpoint struct {
+	Checkpoint
+	signatures []CheckpointSignature
+	expires    time.Time
+}
+
+const (
+	maxDeferredCheckpoints = 16
+	// Peers send checkpoints again on every sync start, so there is no
+	// point in waiting longer for blocks which may never arrive.
+	deferredCheckpointTTL = 30 * time.Minute
+)
+
 type checkpointManager struct {
+	db        ethdb.Database
 	validated map[uint64]validCheckpoint
 	latest    uint64
 	future    map[uint64]futureCheckpoint
+	deferred  map[common.Hash]deferredCheckpoint
+	policy    string
+	quorum    uint64
 	mtx       sync.RWMutex
 	newCpFeed event.Feed
 }
 
-func newCheckpointManager() *checkpointManager {
+func newCheckpointManager(db ethdb.Database) *checkpointManager {
 	return &checkpointManager{
+		db:        db,
 		validated: make(map[uint64]validCheckpoint),
 		future:    make(map[uint64]futureCheckpoint),
+		deferred:  make(map[common.Hash]deferredCheckpoint),
 	}
 }
 
 func (cm *checkpointManager) setup(chain CheckpointChain) {
-	genesis_hash := chain.GetHeaderByNumber(0).Hash();
-	if checkpoints, ok := energi_params.EnergiCheckpoints[genesis_hash]; ok {
-		for k, v := range checkpoints {
-			cm.addCheckpoint(
-				chain,
-				Checkpoint{
-					Number: k,
-					Hash:   v,
-				},
-				[]CheckpointSignature{},
-				true,
-			)
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	// Hardcoded checkpoints are applied directly. Persisting them here
+	// would overwrite the stored dynamic checkpoints before those get
+	// restored below.
+	genesis_hash := chain.GetHeaderByNumber(0).Hash()
+	for k, v := range energi_params.EnergiCheckpoints[genesis_hash] {
+		cp := Checkpoint{
+			Number: k,
+			Hash:   v,
+		}
+
+		if err := cm.applyCheckpoint(chain, cp, nil); err != nil {
+			log.Error("Failed to enforce hardcoded checkpoint", "checkpoint", cp, "err", err)
 		}
 	}
+
+	// Restore checkpoints received before restart. They were validated
+	// before being stored.
+	for _, scp := range rawdb.ReadCheckpoints(cm.db) {
+		cp := Checkpoint{
+			Since:  scp.Since,
+			Number: scp.Number,
+			Hash:   scp.Hash,
+		}
+
+		if curr, ok := cm.validated[cp.Number]; ok && curr.Since > cp.Since {
+			continue
+		}
+
+		sigs := make([]CheckpointSignature, len(scp.Signatures))
+		for i, sig := range scp.Signatures {
+			sigs[i] = CheckpointSignature(sig)
+		}
+
+		log.Debug("Restored checkpoint", "checkpoint", cp, "future", scp.Future)
+
+		if err := cm.applyCheckpoint(chain, cp, sigs); err != nil {
+			log.Error("Failed to enforce restored checkpoint", "checkpoint", cp, "err", err)
+		}
+	}
+}
+
+// setupLight loads the hardcoded checkpoints of a header-only chain. There
+// is nothing to rewind as headers get checked against them on import.
+func (cm *checkpointManager) setupLight(chain CheckpointValidateChain) {
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	genesis_hash := chain.GetHeaderByNumber(0).Hash()
+	for k, v := range energi_params.EnergiCheckpoints[genesis_hash] {
+		cp := Checkpoint{
+			Number: k,
+			Hash:   v,
+		}
+
+		cm.validated[k] = validCheckpoint{Checkpoint: cp}
+		cm.updateLatest(chain, &cp)
+	}
+}
+
+// anchored returns the index of the last header in the contiguous chain
+// matching a known checkpoint, or -1. All headers up to it are linked to the
+// checkpoint hash.
+func (cm *checkpointManager) anchored(chain []*types.Header) int {
+	cm.mtx.RLock()
+	defer cm.mtx.RUnlock()
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if cp, ok := cm.validated[chain[i].Number.Uint64()]; ok && cp.Hash == chain[i].Hash() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// finalized returns the height of the latest checkpoint reached by the chain.
+// Blocks below it can not get reorganized anymore.
+func (cm *checkpointManager) finalized() uint64 {
+	cm.mtx.RLock()
+	defer cm.mtx.RUnlock()
+
+	return cm.latest
 }
 
 func (cm *checkpointManager) validate(chain CheckpointValidateChain, num uint64, hash common.Hash) error {
@@ -145,13 +243,34 @@ func (cm *checkpointManager) validate(chain CheckpointValidateChain, num uint64,
 		return nil
 	}
 
-	// TODO: proper future checkpoint processing
-	if cp, ok := cm.future[num]; ok {
-		if cp.Hash != hash {
-			return ErrCheckpointMismatch
-		}
+	return nil
+}
 
-		return nil
+// SetCheckpointPolicy overrides the remote checkpoint acceptance rule and
+// the masternode quorum of the chain config. Empty values keep the config ones.
+func (bc *BlockChain) SetCheckpointPolicy(policy string, quorum uint64) error {
+	return bc.checkpoints.setPolicy(policy, quorum)
+}
+
+func (cm *checkpointManager) setPolicy(policy string, quorum uint64) error {
+	switch policy {
+	case "", params.CheckpointPolicyCPP, params.CheckpointPolicyCPPAndMN, params.CheckpointPolicyMN:
+	default:
+		return errCheckpointPolicy
+	}
+
+	if quorum > 100 {
+		return errCheckpointQuorum
+	}
+
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	cm.policy = policy
+	cm.quorum = quorum
+
+	if policy != "" || quorum != 0 {
+		log.Info("Checkpoint policy is overridden", "policy", policy, "quorum", quorum)
 	}
 
 	return nil
@@ -202,46 +321,216 @@ func (cm *checkpointManager) addCheckpoint(
 			return nil
 		}
 
-		// TODO: proper validation and use of future checkpoints
-		if len(sigs) == 0 {
-			log.Warn("Checkpoint: missing signatures",
-				"num", cp.Number, "hash", cp.Hash)
-			return errors.New("missing checkpoint signatures")
-		}
-
-		// The first one must always be CPP_signer
-		pubkey, err := crypto.Ecrecover(cm.hashToSign(&cp), sigs[0][:])
-		if err != nil {
-			log.Warn("Checkpoint: failed to extract signature",
-				"num", cp.Number, "hash", cp.Hash, "err", err)
+		if err := cm.verifySignatures(chain, &cp, sigs); err != nil {
+			if err == ErrCheckpointDeferred {
+				cm.deferCheckpoint(cp, sigs)
+			}
 			return err
 		}
+	}
 
-		// Check the primary signature
-		var signer common.Address
-		copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
-		if nrgconf := chain.Config().Energi; nrgconf == nil || signer != nrgconf.CPPSigner {
-			log.Warn("Checkpoint: invalid CPP signature", "num", cp.Number, "hash", cp.Hash)
-			return errors.New("invalid CPP signature")
-		}
+	err = cm.applyCheckpoint(chain, cp, sigs)
+	log.Info("Added new checkpoint", "checkpoint", cp, "local", local)
+
+	cm.persist(chain)
+
+	if !local {
+		// Send regardless of enforcement success
+		cm.newCpFeed.Send(NewCheckpointEvent{CheckpointInfo{cp, sigs[0], uint64(len(sigs))}})
 	}
 
+	return err
+}
+
+// applyCheckpoint registers an already validated checkpoint and enforces it,
+// unless it is a future one. The caller must hold the lock.
+func (cm *checkpointManager) applyCheckpoint(
+	chain CheckpointChain,
+	cp Checkpoint,
+	sigs []CheckpointSignature,
+) error {
 	cm.validated[cp.Number] = validCheckpoint{
 		Checkpoint: cp,
 		signatures: append([]CheckpointSignature{}, sigs...),
 	}
-	log.Info("Added new checkpoint", "checkpoint", cp, "local", local)
 
-	err = chain.EnforceCheckpoint(cp)
+	if cp.Number > chain.CurrentHeader().Number.Uint64() {
+		cm.future[cp.Number] = futureCheckpoint{cp}
+		log.Debug("Future checkpoint", "checkpoint", cp)
+		return nil
+	}
+
+	delete(cm.future, cp.Number)
+
+	err := chain.EnforceCheckpoint(cp)
 
 	cm.updateLatest(chain, &cp)
 
-	if !local {
-		// Send regardless of enforcement success
-		cm.newCpFeed.Send(NewCheckpointEvent{CheckpointInfo{cp, sigs[0], uint64(len(sigs))}})
+	return err
+}
+
+// deferCheckpoint keeps a remote checkpoint until its block is known. The
+// caller must hold the lock.
+func (cm *checkpointManager) deferCheckpoint(cp Checkpoint, sigs []CheckpointSignature) {
+	if _, ok := cm.deferred[cp.Hash]; ok {
+		return
 	}
 
-	return err
+	cm.expireDeferred()
+
+	// Drop the lowest one, if the memory allowance is reached
+	for len(cm.deferred) >= maxDeferredCheckpoints {
+		var lowest common.Hash
+		var lowestNum uint64
+
+		for hash, dcp := range cm.deferred {
+			if (lowest == common.Hash{}) || dcp.Number < lowestNum {
+				lowest, lowestNum = hash, dcp.Number
+			}
+		}
+
+		delete(cm.deferred, lowest)
+	}
+
+	log.Debug("Deferred checkpoint", "checkpoint", cp)
+
+	cm.deferred[cp.Hash] = deferredCheckpoint{
+		Checkpoint: cp,
+		signatures: append([]CheckpointSignature{}, sigs...),
+		expires:    time.Now().Add(deferredCheckpointTTL),
+	}
+}
+
+// expireDeferred drops deferred checkpoints whose block has not arrived in
+// time, e.g. as they are on a side chain. The caller must hold the lock.
+func (cm *checkpointManager) expireDeferred() {
+	now := time.Now()
+
+	for hash, dcp := range cm.deferred {
+		if now.After(dcp.expires) {
+			log.Debug("Expired deferred checkpoint", "checkpoint", dcp.Checkpoint)
+			delete(cm.deferred, hash)
+		}
+	}
+}
+
+// applyDeferred verifies deferred checkpoints whose block has become known.
+// The caller must hold the lock.
+func (cm *checkpointManager) applyDeferred(chain CheckpointChain) (applied bool) {
+	cm.expireDeferred()
+
+	for hash, dcp := range cm.deferred {
+		if !chain.HasBlockAndState(dcp.Hash, dcp.Number) {
+			continue
+		}
+
+		delete(cm.deferred, hash)
+
+		if curr, ok := cm.validated[dcp.Number]; ok && curr.Checkpoint == dcp.Checkpoint {
+			continue
+		}
+
+		if err := cm.verifySignatures(chain, &dcp.Checkpoint, dcp.signatures); err != nil {
+			log.Warn("Rejected deferred checkpoint", "checkpoint", dcp.Checkpoint, "err", err)
+			continue
+		}
+
+		log.Info("Applying deferred checkpoint", "checkpoint", dcp.Checkpoint)
+
+		if err := cm.applyCheckpoint(chain, dcp.Checkpoint, dcp.signatures); err != nil {
+			log.Error("Failed to enforce deferred checkpoint", "checkpoint", dcp.Checkpoint, "err", err)
+		}
+
+		cm.newCpFeed.Send(NewCheckpointEvent{CheckpointInfo{
+			dcp.Checkpoint, dcp.signatures[0], uint64(len(dcp.signatures))}})
+
+		applied = true
+	}
+
+	return applied
+}
+
+// applyFuture enforces future checkpoints which the chain has reached and
+// verifies deferred ones whose block has arrived. It runs on every new head.
+func (cm *checkpointManager) applyFuture(chain CheckpointChain) {
+	cm.mtx.RLock()
+	pending := len(cm.future) + len(cm.deferred)
+	cm.mtx.RUnlock()
+
+	if pending == 0 {
+		return
+	}
+
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	current := chain.CurrentHeader().Number.Uint64()
+	applied := cm.applyDeferred(chain)
+
+	for num, fcp := range cm.future {
+		if num > current {
+			continue
+		}
+
+		vcp := cm.validated[num]
+		if vcp.Checkpoint != fcp.Checkpoint {
+			// Replaced by another checkpoint in the meantime
+			delete(cm.future, num)
+			continue
+		}
+
+		log.Info("Applying future checkpoint", "checkpoint", fcp.Checkpoint)
+
+		if err := cm.applyCheckpoint(chain, vcp.Checkpoint, vcp.signatures); err != nil {
+			log.Error("Failed to enforce future checkpoint", "checkpoint", fcp.Checkpoint, "err", err)
+		}
+
+		applied = true
+	}
+
+	if applied {
+		cm.persist(chain)
+	}
+}
+
+// persist stores all dynamic checkpoints. The hardcoded ones are excluded
+// as they get loaded on every startup anyway. The caller must hold the lock.
+func (cm *checkpointManager) persist(chain CheckpointChain) {
+	if cm.db == nil {
+		return
+	}
+
+	genesis_hash := chain.GetHeaderByNumber(0).Hash()
+	hardcoded := energi_params.EnergiCheckpoints[genesis_hash]
+
+	stored := make([]rawdb.StoredCheckpoint, 0, len(cm.validated))
+
+	for num, vcp := range cm.validated {
+		if hash, ok := hardcoded[num]; ok && hash == vcp.Hash {
+			continue
+		}
+
+		sigs := make([][]byte, len(vcp.signatures))
+		for i, sig := range vcp.signatures {
+			sigs[i] = sig
+		}
+
+		_, future := cm.future[num]
+
+		stored = append(stored, rawdb.StoredCheckpoint{
+			Since:      vcp.Since,
+			Number:     vcp.Number,
+			Hash:       vcp.Hash,
+			Signatures: sigs,
+			Future:     future,
+		})
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].Number < stored[j].Number
+	})
+
+	rawdb.WriteCheckpoints(cm.db, stored)
 }
 
 func (cm *checkpointManager) hashToSign(cp *Checkpoint) []byte {
@@ -291,8 +580,14 @@ func (bc *BlockChain) EnforceCheckpoint(cp Checkpoint) error {
 }
 
 func (bc *BlockChain) ListCheckpoints() []CheckpointInfo {
-	cm := bc.checkpoints
+	return bc.checkpoints.list()
+}
 
+func (bc *BlockChain) CheckpointSignatures(cp Checkpoint) []CheckpointSignature {
+	return bc.checkpoints.signatures(cp)
+}
+
+func (cm *checkpointManager) list() []CheckpointInfo {
 	cm.mtx.Lock()
 	defer cm.mtx.Unlock()
 
@@ -314,9 +609,7 @@ func (bc *BlockChain) ListCheckpoints() []CheckpointInfo {
 	return res
 }
 
-func (bc *BlockChain) CheckpointSignatures(cp Checkpoint) []CheckpointSignature {
-	cm := bc.checkpoints
-
+func (cm *checkpointManager) signatures(cp Checkpoint) []CheckpointSignature {
 	cm.mtx.Lock()
 	defer cm.mtx.Unlock()
 
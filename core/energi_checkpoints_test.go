@@ -20,14 +20,24 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/consensus"
 	"energi.world/core/gen3/consensus/ethash"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/state"
 	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
 	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/params"
 
+	energi_params "energi.world/core/gen3/energi/params"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -184,3 +194,339 @@ func TestCheckpoints(t *testing.T) {
 	assert.Empty(t, err)
 	assert.Equal(t, chain.checkpoints.latest, fpn+2)
 }
+
+func TestFutureCheckpoints(t *testing.T) {
+	t.Parallel()
+	log.Root().SetHandler(log.StdoutHandler)
+
+	engine := ethash.NewFaker()
+	db, chain, err := newCanonical(engine, 10, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+
+	head := chain.CurrentBlock()
+	blocks := makeBlockChain(head, 4, engine, db, canonicalSeed)
+	forks := makeBlockChain(head, 4, engine, db, canonicalSeed+1)
+
+	cp := Checkpoint{
+		Since:  1,
+		Number: blocks[2].NumberU64(),
+		Hash:   blocks[2].Hash(),
+	}
+
+	log.Trace("Future checkpoint is stored, but not enforced")
+	err = chain.AddCheckpoint(cp, []CheckpointSignature{}, true)
+	assert.Empty(t, err)
+	assert.Contains(t, chain.checkpoints.future, cp.Number)
+	assert.Equal(t, uint64(0), chain.checkpoints.latest)
+
+	stored := rawdb.ReadCheckpoints(db)
+	assert.Equal(t, 1, len(stored))
+	assert.Equal(t, cp.Hash, stored[0].Hash)
+	assert.True(t, stored[0].Future)
+
+	log.Trace("Fork is rejected after restart")
+	chain.Stop()
+	chain, _ = NewBlockChain(db, nil, params.AllEthashProtocolChanges, engine, vm.Config{}, nil)
+	defer func() { chain.Stop() }()
+
+	assert.Contains(t, chain.checkpoints.future, cp.Number)
+	_, err = chain.InsertChain(forks)
+	assert.Equal(t, ErrCheckpointMismatch, err)
+
+	log.Trace("Future checkpoint is applied on arrival")
+	_, err = chain.InsertChain(blocks)
+	assert.Empty(t, err)
+	assert.Empty(t, chain.checkpoints.future)
+	assert.Equal(t, cp.Number, chain.checkpoints.latest)
+
+	stored = rawdb.ReadCheckpoints(db)
+	assert.Equal(t, 1, len(stored))
+	assert.False(t, stored[0].Future)
+
+	log.Trace("Applied checkpoint is restored")
+	chain.Stop()
+	chain, _ = NewBlockChain(db, nil, params.AllEthashProtocolChanges, engine, vm.Config{}, nil)
+
+	assert.Empty(t, chain.checkpoints.future)
+	assert.Equal(t, cp.Number, chain.checkpoints.latest)
+	assert.Equal(t, []CheckpointInfo{{cp, nil, 0}}, chain.ListCheckpoints())
+}
+
+func TestHardcodedCheckpointsRestart(t *testing.T) {
+	log.Root().SetHandler(log.StdoutHandler)
+
+	engine := ethash.NewFaker()
+	db := ethdb.NewMemDatabase()
+	genesis := (&Genesis{ExtraData: []byte("hardcoded checkpoints")}).MustCommit(db)
+	blocks := makeBlockChain(genesis, 10, engine, db, canonicalSeed)
+
+	// Not parallel as the hardcoded checkpoints are global
+	hardcoded := Checkpoint{
+		Number: blocks[2].NumberU64(),
+		Hash:   blocks[2].Hash(),
+	}
+	energi_params.EnergiCheckpoints[genesis.Hash()] = map[uint64]common.Hash{
+		hardcoded.Number: hardcoded.Hash,
+	}
+	defer delete(energi_params.EnergiCheckpoints, genesis.Hash())
+
+	chain, _ := NewBlockChain(db, nil, params.AllEthashProtocolChanges, engine, vm.Config{}, nil)
+	_, err := chain.InsertChain(blocks)
+	assert.Empty(t, err)
+
+	cp := Checkpoint{
+		Since:  1,
+		Number: blocks[6].NumberU64(),
+		Hash:   blocks[6].Hash(),
+	}
+	err = chain.AddCheckpoint(cp, []CheckpointSignature{}, true)
+	assert.Empty(t, err)
+
+	stored := rawdb.ReadCheckpoints(db)
+	assert.Equal(t, 1, len(stored))
+	assert.Equal(t, cp.Hash, stored[0].Hash)
+
+	log.Trace("Dynamic checkpoints survive restart")
+	for i := 0; i < 2; i++ {
+		chain.Stop()
+		chain, _ = NewBlockChain(db, nil, params.AllEthashProtocolChanges, engine, vm.Config{}, nil)
+
+		stored = rawdb.ReadCheckpoints(db)
+		assert.Equal(t, 1, len(stored))
+		assert.Equal(t, cp.Hash, stored[0].Hash)
+		assert.Equal(t, cp.Number, chain.checkpoints.latest)
+		assert.Equal(t, []CheckpointInfo{{cp, nil, 0}, {hardcoded, nil, 0}}, chain.ListCheckpoints())
+	}
+	chain.Stop()
+}
+
+type quorumTestChain struct {
+	*BlockChain
+	config     *params.ChainConfig
+	collateral map[common.Address]*big.Int
+	total      *big.Int
+	checked    []common.Hash
+}
+
+func (c *quorumTestChain) Config() *params.ChainConfig {
+	return c.config
+}
+
+func (c *quorumTestChain) CheckpointCollateral(
+	header *types.Header,
+	signers []common.Address,
+) (signed, total *big.Int, err error) {
+	c.checked = append(c.checked, header.Hash())
+	signed = new(big.Int)
+	for _, addr := range signers {
+		if v, ok := c.collateral[addr]; ok {
+			signed.Add(signed, v)
+		}
+	}
+	return signed, c.total, nil
+}
+
+func TestCheckpointQuorum(t *testing.T) {
+	t.Parallel()
+	log.Root().SetHandler(log.StdoutHandler)
+
+	engine := ethash.NewFaker()
+	db, bc, err := newCanonical(engine, 4, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer bc.Stop()
+
+	cpp, _ := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	mns := make([]*ecdsa.PrivateKey, 3)
+	collateral := make(map[common.Address]*big.Int)
+	for i := range mns {
+		mns[i], _ = ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+		collateral[crypto.PubkeyToAddress(mns[i].PublicKey)] = big.NewInt(int64(10 * (i + 1)))
+	}
+
+	cfg := *bc.chainConfig
+	nrgconf := &params.EnergiConfig{
+		CPPSigner: crypto.PubkeyToAddress(cpp.PublicKey),
+	}
+	cfg.Energi = nrgconf
+	chain := &quorumTestChain{
+		BlockChain: bc,
+		config:     &cfg,
+		collateral: collateral,
+		total:      big.NewInt(60),
+	}
+	cm := bc.checkpoints
+
+	cp := &Checkpoint{Number: 2, Hash: bc.GetHeaderByNumber(2).Hash()}
+	signCp := func(cp *Checkpoint, key *ecdsa.PrivateKey, contract bool) CheckpointSignature {
+		sig, _ := crypto.Sign(cm.hashToSign(cp), key)
+		if contract {
+			// Ecrecover workaround
+			sig[64] += 27
+		}
+		return CheckpointSignature(sig)
+	}
+	sign := func(key *ecdsa.PrivateKey, contract bool) CheckpointSignature {
+		return signCp(cp, key, contract)
+	}
+
+	cppSig := sign(cpp, false)
+	mn0 := sign(mns[0], true)
+	mn1 := sign(mns[1], true)
+	mn2 := sign(mns[2], true)
+
+	log.Trace("CPP only")
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig}))
+	assert.Equal(t, errors.New("invalid CPP signature"),
+		cm.verifySignatures(chain, cp, []CheckpointSignature{mn2}))
+
+	log.Trace("CPP and MN quorum")
+	nrgconf.CheckpointPolicy = params.CheckpointPolicyCPPAndMN
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig}))
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig, mn0, mn1}))
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig, mn1, mn1, mn1}))
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig, mn0, mn2}))
+	assert.Equal(t, errors.New("invalid CPP signature"),
+		cm.verifySignatures(chain, cp, []CheckpointSignature{mn0, mn1, mn2}))
+
+	log.Trace("MN quorum only")
+	nrgconf.CheckpointPolicy = params.CheckpointPolicyMN
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{mn2, mn1}))
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig, mn2}))
+
+	nrgconf.CheckpointQuorum = 90
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{mn2, mn1}))
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{mn0, mn1, mn2}))
+
+	log.Trace("Quorum is checked at the checkpoint block")
+	assert.Equal(t, cp.Hash, chain.checked[len(chain.checked)-1])
+
+	log.Trace("Quorum check is deferred until the block is known")
+	head := bc.CurrentBlock()
+	blocks := makeBlockChain(head, 2, engine, db, canonicalSeed)
+	dcp := &Checkpoint{Number: blocks[1].NumberU64(), Hash: blocks[1].Hash()}
+	dsigs := []CheckpointSignature{signCp(dcp, mns[0], true), signCp(dcp, mns[1], true), signCp(dcp, mns[2], true)}
+
+	assert.Equal(t, ErrCheckpointDeferred, cm.verifySignatures(chain, dcp, dsigs))
+
+	// Use a separate manager, so the chain does not apply it on import
+	dcm := newCheckpointManager(nil)
+	assert.Equal(t, ErrCheckpointDeferred, dcm.addCheckpoint(chain, *dcp, dsigs, false))
+	assert.Contains(t, dcm.deferred, dcp.Hash)
+	assert.NotContains(t, dcm.validated, dcp.Number)
+
+	dcm.applyFuture(chain)
+	assert.Contains(t, dcm.deferred, dcp.Hash)
+
+	_, err = bc.InsertChain(blocks)
+	assert.Empty(t, err)
+
+	dcm.applyFuture(chain)
+	assert.Empty(t, dcm.deferred)
+	assert.Equal(t, *dcp, dcm.validated[dcp.Number].Checkpoint)
+	assert.Equal(t, dcp.Hash, chain.checked[len(chain.checked)-1])
+
+	log.Trace("Deferred checkpoints expire")
+	ecp := Checkpoint{Number: 100, Hash: common.HexToHash("0x100")}
+	dcm.deferCheckpoint(ecp, dsigs)
+	dcm.applyFuture(chain)
+	assert.Contains(t, dcm.deferred, ecp.Hash)
+
+	edcp := dcm.deferred[ecp.Hash]
+	edcp.expires = time.Now().Add(-time.Second)
+	dcm.deferred[ecp.Hash] = edcp
+	dcm.applyFuture(chain)
+	assert.Empty(t, dcm.deferred)
+
+	log.Trace("Node policy overrides the chain config")
+	assert.Empty(t, cm.setPolicy(params.CheckpointPolicyCPP, 0))
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig}))
+	assert.Empty(t, cm.setPolicy(params.CheckpointPolicyMN, 10))
+	assert.Empty(t, cm.verifySignatures(chain, cp, []CheckpointSignature{mn0}))
+	assert.Equal(t, errCheckpointPolicy, cm.setPolicy("unknown", 0))
+	assert.Equal(t, errCheckpointQuorum, cm.setPolicy("", 101))
+	assert.Empty(t, cm.setPolicy("", 0))
+	assert.Equal(t, errCheckpointNoQuorum,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{mn0}))
+
+	log.Trace("Unknown policy")
+	nrgconf.CheckpointPolicy = "unknown"
+	assert.Equal(t, errCheckpointPolicy,
+		cm.verifySignatures(chain, cp, []CheckpointSignature{cppSig}))
+}
+
+type sealRecordingEngine struct {
+	consensus.Engine
+	seals []bool
+}
+
+func (e *sealRecordingEngine) VerifyHeaders(
+	chain consensus.ChainReader, headers []*types.Header, seals []bool,
+) (chan<- struct{}, <-chan error, chan<- bool) {
+	e.seals = append(e.seals, seals...)
+	return e.Engine.VerifyHeaders(chain, headers, seals)
+}
+
+func TestLightCheckpointAnchor(t *testing.T) {
+	t.Parallel()
+
+	engine := &sealRecordingEngine{Engine: ethash.NewFaker()}
+	db, chain, err := newCanonical(engine, 0, false)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer chain.Stop()
+
+	hc := chain.hc
+	assert.Nil(t, hc.CalculateBlockState(hc.genesisHeader.Hash(), 0))
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	var requested []uint64
+	hc.EnableLightVerification(func(hash common.Hash, number uint64) *state.StateDB {
+		requested = append(requested, number)
+		return statedb
+	})
+
+	assert.Equal(t, statedb, hc.CalculateBlockState(hc.genesisHeader.Hash(), 0))
+	assert.Equal(t, []uint64{0}, requested)
+
+	headers := makeHeaderChain(hc.genesisHeader, 6, engine, db, canonicalSeed)
+	hc.checkpoints.validated[4] = validCheckpoint{
+		Checkpoint: Checkpoint{Number: 4, Hash: headers[3].Hash()},
+	}
+	assert.Equal(t, 3, hc.checkpoints.anchored(headers))
+
+	log.Trace("Seals up to the anchor are not verified")
+	_, err = hc.ValidateHeaderChain(headers, 1)
+	assert.Empty(t, err)
+	assert.Equal(t, []bool{false, false, false, false, true, true}, engine.seals)
+
+	log.Trace("Headers not linked to the anchor are verified")
+	engine.seals = nil
+	_, err = hc.ValidateHeaderChain(headers[:3], 1)
+	assert.Empty(t, err)
+	assert.Equal(t, []bool{true, true, true}, engine.seals)
+
+	log.Trace("The last header is always verified")
+	engine.seals = nil
+	_, err = hc.ValidateHeaderChain(headers[:4], 1)
+	assert.Empty(t, err)
+	assert.Equal(t, []bool{false, false, false, true}, engine.seals)
+
+	log.Trace("Anchor mismatch is rejected")
+	forks := makeHeaderChain(hc.genesisHeader, 6, engine, db, canonicalSeed+1)
+	idx, err := hc.ValidateHeaderChain(forks, 1)
+	assert.Equal(t, 3, idx)
+	assert.Equal(t, ErrCheckpointMismatch, err)
+
+	assert.Equal(t, []CheckpointInfo{{hc.checkpoints.validated[4].Checkpoint, nil, 0}}, hc.ListCheckpoints())
+	assert.Empty(t, hc.CheckpointSignatures(hc.checkpoints.validated[4].Checkpoint))
+}
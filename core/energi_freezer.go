@@ -0,0 +1,126 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"time"
+
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/log"
+)
+
+const (
+	// freezerRecheckInterval is the frequency to check the key-value store
+	// for blocks to freeze.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks to freeze at once.
+	freezerBatchLimit = 2048
+
+	// freezerBatchPause is the time to wait between two consecutive batches
+	// while catching up, so block import is not starved.
+	freezerBatchPause = 100 * time.Millisecond
+)
+
+// freeze moves finalized blocks from the key-value store into the ancient
+// store. Blocks of existing databases get migrated the same way, batch by
+// batch.
+func (bc *BlockChain) freeze() {
+	defer bc.wg.Done()
+
+	for {
+		frozen := bc.freezeBatch()
+
+		// Continue right away while catching up
+		if frozen < freezerBatchLimit {
+			select {
+			case <-bc.quit:
+				return
+			case <-time.After(freezerRecheckInterval):
+			}
+		} else {
+			select {
+			case <-bc.quit:
+				return
+			case <-time.After(freezerBatchPause):
+			}
+		}
+	}
+}
+
+// freezeBatch freezes the next batch of blocks below the latest checkpoint
+// and returns their number.
+func (bc *BlockChain) freezeBatch() uint64 {
+	bc.mu.Lock()
+	limit := bc.checkpoints.finalized()
+	if head := bc.CurrentBlock().NumberU64(); head < limit {
+		limit = head
+	}
+	bc.mu.Unlock()
+
+	// NOTE: blocks below the latest checkpoint can not get reorganized, so
+	//       they are copied into the ancient store without the chain lock.
+	//       It is held only while removing them from the key-value store.
+	first, hashes, err := rawdb.AppendFrozenBlocks(bc.db, limit, freezerBatchLimit)
+	if err != nil {
+		log.Error("Failed to freeze blocks", "err", err)
+	}
+	if len(hashes) == 0 {
+		return 0
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	frozen := uint64(len(hashes))
+	ancients, _ := bc.db.(ethdb.AncientReader).Ancients()
+
+	// The chain may have been rewound meanwhile
+	if ancients < first+frozen {
+		log.Warn("Frozen blocks got truncated", "first", first, "count", frozen, "ancients", ancients)
+		return 0
+	}
+
+	if err := rawdb.DeleteFrozenBlocks(bc.db, first, hashes); err != nil {
+		log.Error("Failed to remove frozen blocks", "err", err)
+		return 0
+	}
+
+	log.Info("Moved blocks into ancient store", "count", frozen, "ancients", ancients, "limit", limit)
+
+	return frozen
+}
+
+// truncateAncients discards frozen blocks starting at the number, once they
+// get rewound or reorganized away. The caller must hold the chain lock.
+func (bc *BlockChain) truncateAncients(number uint64) {
+	adb, ok := bc.db.(ethdb.AncientStore)
+	if !ok {
+		return
+	}
+
+	if frozen, err := adb.Ancients(); err != nil || frozen <= number {
+		return
+	}
+
+	log.Warn("Truncating ancient chain segment", "number", number)
+
+	if err := adb.TruncateAncients(number); err != nil {
+		log.Crit("Failed to truncate ancient chain segment", "err", err)
+	}
+}
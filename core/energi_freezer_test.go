@@ -0,0 +1,106 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"energi.world/core/gen3/consensus/ethash"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezer(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := rawdb.NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	engine := ethash.NewFaker()
+	genesis := new(Genesis).MustCommit(db)
+	chain, err := NewBlockChain(db, nil, params.AllEthashProtocolChanges, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	blocks := makeBlockChain(genesis, 20, engine, db, canonicalSeed)
+	_, err = chain.InsertChain(blocks)
+	assert.Empty(t, err)
+
+	ancients := func() uint64 {
+		frozen, _ := db.(ethdb.AncientReader).Ancients()
+		return frozen
+	}
+
+	// Nothing is final without checkpoints
+	assert.Equal(t, uint64(0), chain.freezeBatch())
+
+	err = chain.AddCheckpoint(
+		Checkpoint{
+			Number: 12,
+			Hash:   blocks[11].Hash(),
+		},
+		[]CheckpointSignature{},
+		true,
+	)
+	assert.Empty(t, err)
+	assert.Equal(t, uint64(12), chain.freezeBatch())
+	assert.Equal(t, uint64(12), ancients())
+	assert.Equal(t, uint64(0), chain.freezeBatch())
+
+	for _, block := range blocks {
+		assert.Equal(t, block.Hash(), chain.GetBlockByNumber(block.NumberU64()).Hash())
+		assert.Equal(t, block.Hash(), chain.GetHeaderByHash(block.Hash()).Hash())
+		assert.NotNil(t, chain.GetReceiptsByHash(block.Hash()))
+		assert.NotNil(t, chain.GetTd(block.Hash(), block.NumberU64()))
+	}
+
+	// Rewinding discards frozen blocks
+	assert.Empty(t, chain.SetHead(5))
+	assert.Equal(t, uint64(6), ancients())
+	assert.Equal(t, uint64(5), chain.CurrentBlock().NumberU64())
+	assert.Nil(t, chain.GetBlockByNumber(6))
+
+	_, err = chain.InsertChain(blocks[5:])
+	assert.Empty(t, err)
+	assert.Equal(t, blocks[19].Hash(), chain.CurrentBlock().Hash())
+
+	assert.Equal(t, uint64(6), chain.freezeBatch())
+	assert.Equal(t, uint64(12), ancients())
+	assert.Equal(t, blocks[11].Hash(), chain.GetBlockByNumber(12).Hash())
+}
@@ -68,8 +68,14 @@ type HeaderChain struct {
 	engine consensus.Engine
 
 	checkpoints *checkpointManager
+
+	// stateFn provides block state to the consensus engine in light mode
+	stateFn LightStateFn
 }
 
+// LightStateFn retrieves the state of a block on demand for a header-only chain.
+type LightStateFn func(hash common.Hash, number uint64) *state.StateDB
+
 // NewHeaderChain creates a new HeaderChain structure.
 //  getValidator should return the parent's validator
 //  procInterrupt points to the parent's interrupt semaphore
@@ -94,7 +100,7 @@ func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine c
 		procInterrupt: procInterrupt,
 		rand:          mrand.New(mrand.NewSource(seed.Int64())),
 		engine:        engine,
-		checkpoints:   newCheckpointManager(),
+		checkpoints:   newCheckpointManager(chainDb),
 	}
 
 	hc.genesisHeader = hc.GetHeaderByNumber(0)
@@ -233,6 +239,17 @@ func (hc *HeaderChain) ValidateHeaderChain(chain []*types.Header, checkFreq int)
 	}
 	seals[len(seals)-1] = true // Last should always be verified to avoid junk
 
+	// In light mode, headers linked to a checkpoint hash are anchored by it,
+	// so the costly on-demand state lookups are skipped. NOTE: headers below
+	// a checkpoint, which is not part of the batch, are not linked yet.
+	if hc.stateFn != nil {
+		for i := hc.checkpoints.anchored(chain); i >= 0; i-- {
+			if i < len(seals)-1 {
+				seals[i] = false
+			}
+		}
+	}
+
 	abort, results, ready := hc.engine.VerifyHeaders(hc, chain, seals)
 	defer close(abort)
 
@@ -524,6 +541,29 @@ func (hc *HeaderChain) GetBlock(hash common.Hash, number uint64) *types.Block {
 	return nil
 }
 
+// CalculateBlockState returns nil for every input, unless light verification
+// is enabled, as a header chain does not have state available locally.
 func (hc *HeaderChain) CalculateBlockState(hash common.Hash, number uint64) *state.StateDB {
+	if hc.stateFn != nil {
+		return hc.stateFn(hash, number)
+	}
 	return nil
 }
+
+// EnableLightVerification makes the consensus engine verify header seals
+// against the state retrieved by stateFn. Headers linked to a hardcoded
+// checkpoint are trusted by its hash instead.
+func (hc *HeaderChain) EnableLightVerification(stateFn LightStateFn) {
+	hc.stateFn = stateFn
+	hc.checkpoints.setupLight(hc)
+}
+
+// ListCheckpoints returns the checkpoints known to the header chain.
+func (hc *HeaderChain) ListCheckpoints() []CheckpointInfo {
+	return hc.checkpoints.list()
+}
+
+// CheckpointSignatures returns the signatures of a known checkpoint.
+func (hc *HeaderChain) CheckpointSignatures(cp Checkpoint) []CheckpointSignature {
+	return hc.checkpoints.signatures(cp)
+}
@@ -23,13 +23,17 @@ import (
 
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/rlp"
 )
 
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
-	data, _ := db.Get(headerHashKey(number))
+	data := readAncient(db, freezerHashTable, number)
+	if len(data) == 0 {
+		data, _ = db.Get(headerHashKey(number))
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -128,12 +132,18 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	if data := readAncientOf(db, freezerHeaderTable, hash, number); len(data) > 0 {
+		return data
+	}
 	data, _ := db.Get(headerKey(number, hash))
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
+	if isAncient(db, hash, number) {
+		return true
+	}
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -190,6 +200,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 
 // ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	if data := readAncientOf(db, freezerBodiesTable, hash, number); len(data) > 0 {
+		return data
+	}
 	data, _ := db.Get(blockBodyKey(number, hash))
 	return data
 }
@@ -203,6 +216,9 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
+	if isAncient(db, hash, number) {
+		return true
+	}
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -241,7 +257,10 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 
 // ReadTd retrieves a block's total difficulty corresponding to the hash.
 func ReadTd(db DatabaseReader, hash common.Hash, number uint64) *big.Int {
-	data, _ := db.Get(headerTDKey(number, hash))
+	data := readAncientOf(db, freezerDifficultyTable, hash, number)
+	if len(data) == 0 {
+		data, _ = db.Get(headerTDKey(number, hash))
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -274,6 +293,9 @@ func DeleteTd(db DatabaseDeleter, hash common.Hash, number uint64) {
 // HasReceipts verifies the existence of all the transaction receipts belonging
 // to a block.
 func HasReceipts(db DatabaseReader, hash common.Hash, number uint64) bool {
+	if isAncient(db, hash, number) {
+		return true
+	}
 	if has, err := db.Has(blockReceiptsKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -283,7 +305,10 @@ func HasReceipts(db DatabaseReader, hash common.Hash, number uint64) bool {
 // ReadReceipts retrieves all the transaction receipts belonging to a block.
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
-	data, _ := db.Get(blockReceiptsKey(number, hash))
+	data := readAncientOf(db, freezerReceiptTable, hash, number)
+	if len(data) == 0 {
+		data, _ = db.Get(blockReceiptsKey(number, hash))
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -382,3 +407,29 @@ func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 	}
 	return a
 }
+
+// readAncient retrieves an item of a frozen canonical block, if the database
+// has an ancient store.
+func readAncient(db DatabaseReader, kind string, number uint64) []byte {
+	adb, ok := db.(ethdb.AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := adb.Ancient(kind, number)
+	return data
+}
+
+// readAncientOf retrieves an item of a frozen block, if it is the canonical
+// block of the number.
+func readAncientOf(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if !isAncient(db, hash, number) {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
+// isAncient checks whether the block is a frozen canonical block.
+func isAncient(db DatabaseReader, hash common.Hash, number uint64) bool {
+	data := readAncient(db, freezerHashTable, number)
+	return len(data) > 0 && common.BytesToHash(data) == hash
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rlp"
+)
+
+// StoredCheckpoint is a checkpoint accepted by the node together with
+// the signatures it was validated with.
+type StoredCheckpoint struct {
+	Since      uint64
+	Number     uint64
+	Hash       common.Hash
+	Signatures [][]byte
+	Future     bool // the block is not known yet, so it is not enforced
+}
+
+// ReadCheckpoints retrieves all the stored checkpoints.
+func ReadCheckpoints(db DatabaseReader) []StoredCheckpoint {
+	data, _ := db.Get(checkpointsKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var checkpoints []StoredCheckpoint
+	if err := rlp.DecodeBytes(data, &checkpoints); err != nil {
+		log.Error("Invalid checkpoints RLP", "err", err)
+		return nil
+	}
+	return checkpoints
+}
+
+// WriteCheckpoints stores all the checkpoints replacing the previous ones.
+func WriteCheckpoints(db DatabaseWriter, checkpoints []StoredCheckpoint) {
+	data, err := rlp.EncodeToBytes(checkpoints)
+	if err != nil {
+		log.Crit("Failed to encode checkpoints", "err", err)
+	}
+	if err := db.Put(checkpointsKey, data); err != nil {
+		log.Crit("Failed to store checkpoints", "err", err)
+	}
+}
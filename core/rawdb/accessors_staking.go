@@ -0,0 +1,66 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rlp"
+)
+
+// StakingRecord is a single block staked by some coinbase together with
+// the block reward split as paid by the BlockReward contract.
+type StakingRecord struct {
+	Number           uint64
+	Hash             common.Hash
+	Time             uint64
+	Weight           uint64 // used stake weight, i.e. header nonce
+	StakerReward     *big.Int
+	MasternodeReward *big.Int
+	BackboneReward   *big.Int
+	TreasuryReward   *big.Int
+	Estimated        bool // rewards are calculated without historical state
+}
+
+// ReadStakingRecords retrieves all staking records of the given coinbase
+// in the specified staking history section.
+func ReadStakingRecords(db DatabaseReader, section uint64, head common.Hash, addr common.Address) []StakingRecord {
+	data, _ := db.Get(stakingHistoryKey(section, head, addr))
+	if len(data) == 0 {
+		return nil
+	}
+	var records []StakingRecord
+	if err := rlp.DecodeBytes(data, &records); err != nil {
+		log.Error("Invalid staking history RLP", "section", section, "addr", addr, "err", err)
+		return nil
+	}
+	return records
+}
+
+// WriteStakingRecords stores all staking records of the given coinbase
+// in the specified staking history section.
+func WriteStakingRecords(db DatabaseWriter, section uint64, head common.Hash, addr common.Address, records []StakingRecord) {
+	data, err := rlp.EncodeToBytes(records)
+	if err != nil {
+		log.Crit("Failed to encode staking history", "err", err)
+	}
+	if err := db.Put(stakingHistoryKey(section, head, addr), data); err != nil {
+		log.Crit("Failed to store staking history", "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/ethdb"
+)
+
+// Tests that staking history records can be stored and retrieved.
+func TestStakingHistoryStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	addr1 := common.BytesToAddress([]byte{0x11})
+	addr2 := common.BytesToAddress([]byte{0x22})
+	head := common.BytesToHash([]byte{0x33})
+
+	records := []StakingRecord{
+		{
+			Number:           4097,
+			Hash:             common.BytesToHash([]byte{0x01}),
+			Time:             1000,
+			Weight:           10,
+			StakerReward:     big.NewInt(228),
+			MasternodeReward: big.NewInt(914),
+			BackboneReward:   big.NewInt(228),
+			TreasuryReward:   big.NewInt(0),
+		},
+		{
+			Number:           4100,
+			Hash:             common.BytesToHash([]byte{0x02}),
+			Time:             1180,
+			Weight:           3,
+			StakerReward:     big.NewInt(228),
+			MasternodeReward: big.NewInt(914),
+			BackboneReward:   big.NewInt(228),
+			TreasuryReward:   big.NewInt(18400),
+			Estimated:        true,
+		},
+	}
+
+	if res := ReadStakingRecords(db, 1, head, addr1); res != nil {
+		t.Fatalf("non existent staking records returned: %v", res)
+	}
+
+	WriteStakingRecords(db, 1, head, addr1, records)
+
+	if res := ReadStakingRecords(db, 1, head, addr1); !reflect.DeepEqual(res, records) {
+		t.Fatalf("staking records mismatch: have %v, want %v", res, records)
+	}
+	if res := ReadStakingRecords(db, 1, head, addr2); res != nil {
+		t.Fatalf("staking records of other address returned: %v", res)
+	}
+	if res := ReadStakingRecords(db, 0, head, addr1); res != nil {
+		t.Fatalf("staking records of other section returned: %v", res)
+	}
+	if res := ReadStakingRecords(db, 1, common.Hash{}, addr1); res != nil {
+		t.Fatalf("staking records of other section head returned: %v", res)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rlp"
+)
+
+// Kinds of vote records and targets
+const (
+	VoteAccept     uint8 = iota // IProposal.voteAccept()
+	VoteReject                  // IProposal.voteReject()
+	VoteCheckpoint              // CheckpointRegistry.sign()
+	VoteCreate                  // contract creation, possibly a proposal
+)
+
+// VoteRecord is a single successful proposal vote or checkpoint signature
+// transaction.
+type VoteRecord struct {
+	Number uint64
+	TxHash common.Hash
+	Kind   uint8
+	Target common.Address // proposal or checkpoint
+}
+
+// VoteTarget is a proposal which received votes, a contract which was created
+// or a checkpoint which was proposed in some section. Proposals use the block
+// of their first vote.
+type VoteTarget struct {
+	Number uint64
+	Kind   uint8 // VoteAccept for proposals, VoteCreate for created contracts
+	Target common.Address
+	CPNum  uint64
+	CPHash common.Hash
+}
+
+// ReadVoteRecords retrieves all vote records of the given sender in the
+// specified vote index section.
+func ReadVoteRecords(db DatabaseReader, section uint64, head common.Hash, addr common.Address) []VoteRecord {
+	data, _ := db.Get(voteRecordKey(section, head, addr))
+	if len(data) == 0 {
+		return nil
+	}
+	var records []VoteRecord
+	if err := rlp.DecodeBytes(data, &records); err != nil {
+		log.Error("Invalid vote records RLP", "section", section, "addr", addr, "err", err)
+		return nil
+	}
+	return records
+}
+
+// WriteVoteRecords stores all vote records of the given sender in the
+// specified vote index section.
+func WriteVoteRecords(db DatabaseWriter, section uint64, head common.Hash, addr common.Address, records []VoteRecord) {
+	data, err := rlp.EncodeToBytes(records)
+	if err != nil {
+		log.Crit("Failed to encode vote records", "err", err)
+	}
+	if err := db.Put(voteRecordKey(section, head, addr), data); err != nil {
+		log.Crit("Failed to store vote records", "err", err)
+	}
+}
+
+// ReadVoteTargets retrieves all vote targets of the specified vote index
+// section.
+func ReadVoteTargets(db DatabaseReader, section uint64, head common.Hash) []VoteTarget {
+	data, _ := db.Get(voteTargetKey(section, head))
+	if len(data) == 0 {
+		return nil
+	}
+	var targets []VoteTarget
+	if err := rlp.DecodeBytes(data, &targets); err != nil {
+		log.Error("Invalid vote targets RLP", "section", section, "err", err)
+		return nil
+	}
+	return targets
+}
+
+// WriteVoteTargets stores all vote targets of the specified vote index
+// section.
+func WriteVoteTargets(db DatabaseWriter, section uint64, head common.Hash, targets []VoteTarget) {
+	data, err := rlp.EncodeToBytes(targets)
+	if err != nil {
+		log.Crit("Failed to encode vote targets", "err", err)
+	}
+	if err := db.Put(voteTargetKey(section, head), data); err != nil {
+		log.Crit("Failed to store vote targets", "err", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/ethdb"
+)
+
+// Tests that vote records and targets can be stored and retrieved.
+func TestVoteIndexStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	addr1 := common.BytesToAddress([]byte{0x11})
+	addr2 := common.BytesToAddress([]byte{0x22})
+	head := common.BytesToHash([]byte{0x33})
+
+	records := []VoteRecord{
+		{
+			Number: 4097,
+			TxHash: common.BytesToHash([]byte{0x01}),
+			Kind:   VoteAccept,
+			Target: common.BytesToAddress([]byte{0x44}),
+		},
+		{
+			Number: 4100,
+			TxHash: common.BytesToHash([]byte{0x02}),
+			Kind:   VoteCheckpoint,
+			Target: common.BytesToAddress([]byte{0x55}),
+		},
+	}
+	targets := []VoteTarget{
+		{
+			Number: 4097,
+			Kind:   VoteAccept,
+			Target: common.BytesToAddress([]byte{0x44}),
+		},
+		{
+			Number: 4099,
+			Kind:   VoteCheckpoint,
+			Target: common.BytesToAddress([]byte{0x55}),
+			CPNum:  4000,
+			CPHash: common.BytesToHash([]byte{0x66}),
+		},
+	}
+
+	if res := ReadVoteRecords(db, 1, head, addr1); res != nil {
+		t.Fatalf("non existent vote records returned: %v", res)
+	}
+	if res := ReadVoteTargets(db, 1, head); res != nil {
+		t.Fatalf("non existent vote targets returned: %v", res)
+	}
+
+	WriteVoteRecords(db, 1, head, addr1, records)
+	WriteVoteTargets(db, 1, head, targets)
+
+	if res := ReadVoteRecords(db, 1, head, addr1); !reflect.DeepEqual(res, records) {
+		t.Fatalf("vote records mismatch: have %v, want %v", res, records)
+	}
+	if res := ReadVoteRecords(db, 1, head, addr2); res != nil {
+		t.Fatalf("vote records of other address returned: %v", res)
+	}
+	if res := ReadVoteRecords(db, 0, head, addr1); res != nil {
+		t.Fatalf("vote records of other section returned: %v", res)
+	}
+	if res := ReadVoteTargets(db, 1, head); !reflect.DeepEqual(res, targets) {
+		t.Fatalf("vote targets mismatch: have %v, want %v", res, targets)
+	}
+	if res := ReadVoteTargets(db, 1, common.Hash{}); res != nil {
+		t.Fatalf("vote targets of other section head returned: %v", res)
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/log"
+)
+
+// errNoFreezer is returned if the database has no ancient store.
+var errNoFreezer = errors.New("no ancient store")
+
+// freezerdb is a key-value database backed by a freezer for finalized
+// blocks.
+type freezerdb struct {
+	ethdb.Database
+	*freezer
+}
+
+// Close implements ethdb.Database, closing both the freezer and the key-value
+// store.
+func (frdb *freezerdb) Close() {
+	if err := frdb.freezer.Close(); err != nil {
+		log.Error("Failed to close ancient database", "err", err)
+	}
+	frdb.Database.Close()
+}
+
+// NewDatabaseWithFreezer attaches a freezer in the directory to the key-value
+// store. The freezer must hold the same chain as the key-value store.
+func NewDatabaseWithFreezer(db ethdb.Database, freezer string, namespace string) (ethdb.Database, error) {
+	frdb, err := newFreezer(freezer, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateFreezer(db, frdb); err != nil {
+		frdb.Close()
+		return nil, err
+	}
+
+	return &freezerdb{
+		Database: db,
+		freezer:  frdb,
+	}, nil
+}
+
+// validateFreezer checks that the frozen blocks are continued by the key-value
+// store.
+func validateFreezer(db ethdb.Database, frdb *freezer) error {
+	frozen, _ := frdb.Ancients()
+
+	kvgenesis := ReadCanonicalHash(db, 0)
+	if kvgenesis == (common.Hash{}) {
+		if frozen > 0 {
+			return errors.New("ancient chain segment exists, but the key-value store is empty")
+		}
+		return nil
+	}
+
+	var head uint64
+	if number := ReadHeaderNumber(db, ReadHeadHeaderHash(db)); number != nil {
+		head = *number
+	}
+
+	if frozen == 0 {
+		if head > 0 && ReadCanonicalHash(db, 1) == (common.Hash{}) {
+			return errors.New("ancient chain segment is missing, check the ancient directory")
+		}
+		return nil
+	}
+
+	if frgenesis, _ := frdb.Ancient(freezerHashTable, 0); !bytes.Equal(kvgenesis[:], frgenesis) {
+		return fmt.Errorf("genesis mismatch: %#x (key-value) != %#x (ancient)", kvgenesis, frgenesis)
+	}
+
+	if head >= frozen && ReadCanonicalHash(db, frozen) == (common.Hash{}) {
+		return fmt.Errorf("gap between the ancient chain segment and the key-value store at %d", frozen)
+	}
+
+	return nil
+}
+
+// KeyValueStore returns the key-value store of a database with freezer.
+func KeyValueStore(db ethdb.Database) ethdb.Database {
+	if frdb, ok := db.(*freezerdb); ok {
+		return frdb.Database
+	}
+	return db
+}
+
+// FreezeBlocks moves up to count canonical blocks below the limit from
+// the key-value store into the freezer. Existing databases get migrated this
+// way gradually. The genesis block is kept in the key-value store as well.
+// It returns the number of frozen blocks.
+//
+// The caller must ensure the blocks can not get reorganized meanwhile.
+func FreezeBlocks(db ethdb.Database, limit uint64, count uint64) (uint64, error) {
+	first, hashes, err := AppendFrozenBlocks(db, limit, count)
+	if len(hashes) == 0 {
+		return 0, err
+	}
+
+	if derr := DeleteFrozenBlocks(db, first, hashes); derr != nil {
+		return 0, derr
+	}
+
+	return uint64(len(hashes)), err
+}
+
+// AppendFrozenBlocks copies up to count canonical blocks below the limit
+// into the freezer and syncs it. It returns the number of the first copied
+// block and the hashes of all copied blocks, which are still kept in the
+// key-value store.
+func AppendFrozenBlocks(db ethdb.Database, limit uint64, count uint64) (uint64, []common.Hash, error) {
+	frdb, ok := db.(*freezerdb)
+	if !ok {
+		return 0, nil, errNoFreezer
+	}
+
+	frozen, _ := frdb.Ancients()
+	if limit <= frozen {
+		return frozen, nil, nil
+	}
+	if limit-frozen > count {
+		limit = frozen + count
+	}
+
+	var (
+		kv     = frdb.Database
+		hashes = make([]common.Hash, 0, limit-frozen)
+		err    error
+	)
+
+	for number := frozen; number < limit; number++ {
+		hash := ReadCanonicalHash(kv, number)
+		if hash == (common.Hash{}) {
+			err = fmt.Errorf("canonical hash missing, can't freeze block %d", number)
+			break
+		}
+
+		header := ReadHeaderRLP(kv, hash, number)
+		body := ReadBodyRLP(kv, hash, number)
+		receipts, _ := kv.Get(blockReceiptsKey(number, hash))
+		td, _ := kv.Get(headerTDKey(number, hash))
+
+		if len(header) == 0 || len(body) == 0 || len(receipts) == 0 || len(td) == 0 {
+			err = fmt.Errorf("block data missing, can't freeze block %d", number)
+			break
+		}
+
+		if err = frdb.AppendAncient(number, hash[:], header, body, receipts, td); err != nil {
+			break
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if len(hashes) == 0 {
+		return frozen, nil, err
+	}
+
+	if serr := frdb.Sync(); serr != nil {
+		return frozen, nil, serr
+	}
+
+	return frozen, hashes, err
+}
+
+// DeleteFrozenBlocks wipes out the blocks copied by AppendFrozenBlocks from
+// the key-value store, only the hash to number mapping is kept.
+func DeleteFrozenBlocks(db ethdb.Database, first uint64, hashes []common.Hash) error {
+	kv := KeyValueStore(db)
+	batch := kv.NewBatch()
+
+	for i, hash := range hashes {
+		number := first + uint64(i)
+		if number == 0 {
+			continue
+		}
+
+		DeleteCanonicalHash(batch, number)
+		deleteBlockData(batch, hash, number)
+
+		// Side chains can not become canonical anymore
+		for _, side := range readSideHashes(kv, number, hash) {
+			DeleteBlock(batch, side, number)
+		}
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+
+	return batch.Write()
+}
+
+// deleteBlockData removes all block data, except the hash to number mapping.
+func deleteBlockData(db DatabaseDeleter, hash common.Hash, number uint64) {
+	if err := db.Delete(headerKey(number, hash)); err != nil {
+		log.Crit("Failed to delete header", "err", err)
+	}
+	DeleteBody(db, hash, number)
+	DeleteReceipts(db, hash, number)
+	DeleteTd(db, hash, number)
+}
+
+// readSideHashes returns hashes of all the stored headers at the height,
+// except the canonical one.
+func readSideHashes(db ethdb.Database, number uint64, canonical common.Hash) (hashes []common.Hash) {
+	it := db.NewIteratorWithPrefix(append(append([]byte{}, headerPrefix...), encodeBlockNumber(number)...))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(headerPrefix)+8+common.HashLength {
+			continue
+		}
+
+		if hash := common.BytesToHash(key[len(key)-common.HashLength:]); hash != canonical {
+			hashes = append(hashes, hash)
+		}
+	}
+
+	return hashes
+}
+
+// DatabaseStat is the disk usage of a single kind of data.
+type DatabaseStat struct {
+	Database string
+	Category string
+	Size     common.StorageSize
+	Count    uint64
+}
+
+// InspectDatabase iterates the entire database and breaks the usage down by
+// the schema prefixes. Ancient chain data is reported separately.
+func InspectDatabase(db ethdb.Database) ([]DatabaseStat, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	const kvStore = "Key-Value store"
+
+	// NOTE: the order is the one of the report
+	stats := []DatabaseStat{
+		{Database: kvStore, Category: "Headers"},
+		{Database: kvStore, Category: "Bodies"},
+		{Database: kvStore, Category: "Receipts"},
+		{Database: kvStore, Category: "Difficulties"},
+		{Database: kvStore, Category: "Block number->hash"},
+		{Database: kvStore, Category: "Block hash->number"},
+		{Database: kvStore, Category: "Transaction lookups"},
+		{Database: kvStore, Category: "Bloombit index"},
+		{Database: kvStore, Category: "Trie nodes and codes"},
+		{Database: kvStore, Category: "Trie preimages"},
+		{Database: kvStore, Category: "Staking history"},
+		{Database: kvStore, Category: "Vote records"},
+		{Database: kvStore, Category: "Vote targets"},
+		{Database: kvStore, Category: "Chain indexers"},
+		{Database: kvStore, Category: "Light client CHT"},
+		{Database: kvStore, Category: "Light client bloom trie"},
+		{Database: kvStore, Category: "Energi checkpoints"},
+		{Database: kvStore, Category: "Metadata"},
+		{Database: kvStore, Category: "Unaccounted"},
+	}
+	const (
+		headers = iota
+		bodies
+		receipts
+		tds
+		numHashPairs
+		hashNumPairs
+		txLookups
+		bloomBits
+		tries
+		preimages
+		stakingHistory
+		voteRecords
+		voteTargets
+		indexers
+		chtTries
+		bloomTries
+		checkpoints
+		metadata
+		unaccounted
+	)
+
+	var (
+		numberedLen = len(headerPrefix) + 8 + common.HashLength
+		count       uint64
+		start       = time.Now()
+		logged      = time.Now()
+	)
+	for it.Next() {
+		var (
+			key  = it.Key()
+			size = common.StorageSize(len(key) + len(it.Value()))
+			stat = unaccounted
+		)
+		switch {
+		case bytes.HasPrefix(key, headerPrefix) && len(key) == numberedLen:
+			stat = headers
+		case bytes.HasPrefix(key, headerPrefix) && len(key) == numberedLen+len(headerTDSuffix) && bytes.HasSuffix(key, headerTDSuffix):
+			stat = tds
+		case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+8+len(headerHashSuffix) && bytes.HasSuffix(key, headerHashSuffix):
+			stat = numHashPairs
+		case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == len(headerNumberPrefix)+common.HashLength:
+			stat = hashNumPairs
+		case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == numberedLen:
+			stat = bodies
+		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == numberedLen:
+			stat = receipts
+		case bytes.HasPrefix(key, txLookupPrefix) && len(key) == len(txLookupPrefix)+common.HashLength:
+			stat = txLookups
+		case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == len(bloomBitsPrefix)+10+common.HashLength:
+			stat = bloomBits
+		case bytes.HasPrefix(key, stakingHistoryPrefix) && len(key) == len(stakingHistoryPrefix)+8+common.HashLength+common.AddressLength:
+			stat = stakingHistory
+		case bytes.HasPrefix(key, voteRecordPrefix) && len(key) == len(voteRecordPrefix)+8+common.HashLength+common.AddressLength:
+			stat = voteRecords
+		case bytes.HasPrefix(key, voteTargetPrefix) && len(key) == len(voteTargetPrefix)+8+common.HashLength:
+			stat = voteTargets
+		case len(key) == common.HashLength:
+			stat = tries
+		case bytes.HasPrefix(key, preimagePrefix) && len(key) == len(preimagePrefix)+common.HashLength:
+			stat = preimages
+		case bytes.HasPrefix(key, BloomBitsIndexPrefix) ||
+			bytes.HasPrefix(key, StakingIndexPrefix) ||
+			bytes.HasPrefix(key, VoteIndexPrefix):
+			stat = indexers
+		case bytes.HasPrefix(key, []byte("cht")):
+			stat = chtTries
+		case bytes.HasPrefix(key, []byte("blt")):
+			stat = bloomTries
+		case bytes.Equal(key, checkpointsKey):
+			stat = checkpoints
+		case bytes.HasPrefix(key, configPrefix),
+			bytes.Equal(key, databaseVerisionKey),
+			bytes.Equal(key, headHeaderKey),
+			bytes.Equal(key, headBlockKey),
+			bytes.Equal(key, headFastBlockKey),
+			bytes.Equal(key, fastTrieProgressKey):
+			stat = metadata
+		}
+		stats[stat].Size += size
+		stats[stat].Count++
+
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	// Ancient chain segment, if any
+	if frdb, ok := db.(*freezerdb); ok {
+		frozen, err := frdb.Ancients()
+		if err != nil {
+			return nil, err
+		}
+		for _, kind := range []struct {
+			table    string
+			category string
+		}{
+			{freezerHeaderTable, "Headers"},
+			{freezerBodiesTable, "Bodies"},
+			{freezerReceiptTable, "Receipts"},
+			{freezerDifficultyTable, "Difficulties"},
+			{freezerHashTable, "Block number->hash"},
+		} {
+			size, err := frdb.AncientSize(kind.table)
+			if err != nil {
+				return nil, err
+			}
+			stats = append(stats, DatabaseStat{
+				Database: "Ancient store",
+				Category: kind.category,
+				Size:     common.StorageSize(size),
+				Count:    frozen,
+			})
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/ethdb"
+)
+
+// writeTestChain stores a canonical chain of the length and a side block at
+// the height 3.
+func writeTestChain(db ethdb.Database, length int) (hashes []common.Hash, side common.Hash) {
+	parent := common.Hash{}
+	for i := 0; i < length; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			Extra:      []byte("test block"),
+		}
+		block := types.NewBlockWithHeader(header)
+		receipt := &types.Receipt{CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}
+
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{receipt})
+		WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(int64(i+1)))
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+
+		if i == 3 {
+			header.Extra = []byte("side block")
+			sideBlock := types.NewBlockWithHeader(header)
+			WriteBlock(db, sideBlock)
+			side = sideBlock.Hash()
+		}
+
+		hashes = append(hashes, block.Hash())
+		parent = block.Hash()
+	}
+	WriteHeadHeaderHash(db, parent)
+	WriteHeadBlockHash(db, parent)
+	return hashes, side
+}
+
+func checkTestChain(t *testing.T, db ethdb.Database, hashes []common.Hash) {
+	for i, hash := range hashes {
+		number := uint64(i)
+		if have := ReadCanonicalHash(db, number); have != hash {
+			t.Fatalf("block %d: wrong canonical hash %x", i, have)
+		}
+		if header := ReadHeader(db, hash, number); header == nil || header.Hash() != hash {
+			t.Fatalf("block %d: wrong header %v", i, header)
+		}
+		if !HasHeader(db, hash, number) || !HasBody(db, hash, number) || !HasReceipts(db, hash, number) {
+			t.Fatalf("block %d: missing data", i)
+		}
+		if block := ReadBlock(db, hash, number); block == nil {
+			t.Fatalf("block %d: missing block", i)
+		}
+		if receipts := ReadReceipts(db, hash, number); len(receipts) != 1 || receipts[0].CumulativeGasUsed != number {
+			t.Fatalf("block %d: wrong receipts %v", i, receipts)
+		}
+		if td := ReadTd(db, hash, number); td == nil || td.Int64() != int64(i+1) {
+			t.Fatalf("block %d: wrong td %v", i, td)
+		}
+		if have := ReadHeaderNumber(db, hash); have == nil || *have != number {
+			t.Fatalf("block %d: wrong hash to number mapping %v", i, have)
+		}
+		// A non-canonical hash at the height is not served from the freezer
+		if HasHeader(db, common.Hash{0x1}, number) {
+			t.Fatalf("block %d: unknown header found", i)
+		}
+	}
+}
+
+// Tests moving blocks into the freezer and reading them back.
+func TestFreezeBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes, side := writeTestChain(kvdb, 10)
+
+	db, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient"), "")
+	if err != nil {
+		t.Fatalf("can't open freezer: %v", err)
+	}
+
+	if _, err := FreezeBlocks(kvdb, 6, 4); err != errNoFreezer {
+		t.Fatalf("unexpected error without freezer: %v", err)
+	}
+
+	for _, want := range []uint64{4, 2, 0} {
+		if frozen, err := FreezeBlocks(db, 6, 4); err != nil || frozen != want {
+			t.Fatalf("wrong frozen count: have %d, want %d, err %v", frozen, want, err)
+		}
+		checkTestChain(t, db, hashes)
+	}
+
+	// Only the genesis and the hash to number mappings are kept
+	for i, hash := range hashes[1:6] {
+		if HasHeader(kvdb, hash, uint64(i+1)) || ReadCanonicalHash(kvdb, uint64(i+1)) != (common.Hash{}) {
+			t.Fatalf("block %d: frozen data kept", i+1)
+		}
+	}
+	if !HasHeader(kvdb, hashes[0], 0) || !HasHeader(kvdb, hashes[6], 6) {
+		t.Fatalf("unfrozen data removed")
+	}
+	if HasHeader(kvdb, side, 3) || ReadHeaderNumber(kvdb, side) != nil {
+		t.Fatalf("side block kept")
+	}
+	db.Close()
+
+	// Reopen the database
+	kvdb, err = ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "other"), ""); err == nil {
+		t.Fatalf("missing freezer not detected")
+	}
+	db, err = NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient"), "")
+	if err != nil {
+		t.Fatalf("can't reopen freezer: %v", err)
+	}
+	defer db.Close()
+	checkTestChain(t, db, hashes)
+
+	// Truncated blocks are gone
+	if err := db.(ethdb.AncientStore).TruncateAncients(4); err != nil {
+		t.Fatalf("can't truncate: %v", err)
+	}
+	checkTestChain(t, db, hashes[:4])
+	if ReadCanonicalHash(db, 4) != (common.Hash{}) || HasHeader(db, hashes[4], 4) {
+		t.Fatalf("truncated block found")
+	}
+}
+
+// Tests breaking the database usage down by the schema.
+func TestInspectDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestChain(kvdb, 10)
+	WritePreimages(kvdb, map[common.Hash][]byte{common.Hash{0x1}: []byte("preimage")})
+	kvdb.Put(common.Hash{0x2}.Bytes(), []byte("trie node"))
+	kvdb.Put(checkpointsKey, []byte("checkpoints"))
+	kvdb.Put([]byte("unknown"), []byte("data"))
+
+	db, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient"), "")
+	if err != nil {
+		t.Fatalf("can't open freezer: %v", err)
+	}
+	defer db.Close()
+
+	count := func(stats []DatabaseStat) map[string]uint64 {
+		res := make(map[string]uint64)
+		for _, stat := range stats {
+			if stat.Count > 0 && stat.Size == 0 {
+				t.Errorf("%s %s: no size", stat.Database, stat.Category)
+			}
+			res[stat.Database+" "+stat.Category] = stat.Count
+		}
+		return res
+	}
+
+	stats, err := InspectDatabase(db)
+	if err != nil {
+		t.Fatalf("can't inspect: %v", err)
+	}
+	want := map[string]uint64{
+		"Key-Value store Headers":              11,
+		"Key-Value store Bodies":               11,
+		"Key-Value store Receipts":             10,
+		"Key-Value store Difficulties":         10,
+		"Key-Value store Block number->hash":   10,
+		"Key-Value store Block hash->number":   11,
+		"Key-Value store Trie nodes and codes": 1,
+		"Key-Value store Trie preimages":       1,
+		"Key-Value store Energi checkpoints":   1,
+		"Key-Value store Metadata":             2,
+		"Key-Value store Unaccounted":          1,
+		"Ancient store Headers":                0,
+	}
+	have := count(stats)
+	for category, n := range want {
+		if have[category] != n {
+			t.Errorf("%s: have %d, want %d", category, have[category], n)
+		}
+	}
+
+	// Frozen blocks get reported by the ancient store, the genesis is kept
+	// and the side block is gone
+	if _, err := FreezeBlocks(db, 6, 4); err != nil {
+		t.Fatalf("can't freeze: %v", err)
+	}
+	if stats, err = InspectDatabase(db); err != nil {
+		t.Fatalf("can't inspect: %v", err)
+	}
+	want = map[string]uint64{
+		"Key-Value store Headers":    7,
+		"Key-Value store Bodies":     7,
+		"Ancient store Headers":      4,
+		"Ancient store Bodies":       4,
+		"Ancient store Receipts":     4,
+		"Ancient store Difficulties": 4,
+	}
+	have = count(stats)
+	for category, n := range want {
+		if have[category] != n {
+			t.Errorf("%s: have %d, want %d", category, have[category], n)
+		}
+	}
+}
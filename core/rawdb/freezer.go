@@ -0,0 +1,234 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/metrics"
+)
+
+const (
+	// freezerHashTable indicates the name of the freezer canonical hash table.
+	freezerHashTable = "hashes"
+
+	// freezerHeaderTable indicates the name of the freezer header table.
+	freezerHeaderTable = "headers"
+
+	// freezerBodiesTable indicates the name of the freezer block body table.
+	freezerBodiesTable = "bodies"
+
+	// freezerReceiptTable indicates the name of the freezer receipts table.
+	freezerReceiptTable = "receipts"
+
+	// freezerDifficultyTable indicates the name of the freezer total difficulty table.
+	freezerDifficultyTable = "diffs"
+)
+
+// freezerNoSnappy configures whether compression is disabled for the tables.
+// Hashes and total difficulties do not compress.
+var freezerNoSnappy = map[string]bool{
+	freezerHashTable:       true,
+	freezerHeaderTable:     false,
+	freezerBodiesTable:     false,
+	freezerReceiptTable:    false,
+	freezerDifficultyTable: true,
+}
+
+// errUnknownTable is returned if the user attempts to access an unknown table.
+var errUnknownTable = errors.New("unknown table")
+
+// freezer is an append-only store of finalized canonical blocks, which are
+// moved out of the key-value store to keep it small.
+//
+// Each kind of block data has its own table. All tables have the same number
+// of items, so a block is either frozen as a whole or not at all.
+type freezer struct {
+	frozen uint64 // number of frozen blocks, accessed atomically
+
+	tables map[string]*freezerTable
+}
+
+// newFreezer opens the freezer tables in the directory, dropping any
+// partially appended block.
+func newFreezer(datadir string, namespace string) (*freezer, error) {
+	var (
+		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
+		writeMeter = metrics.NewRegisteredMeter(namespace+"ancient/write", nil)
+	)
+
+	f := &freezer{
+		tables: make(map[string]*freezerTable),
+	}
+
+	for name, noCompression := range freezerNoSnappy {
+		table, err := newTable(datadir, name, readMeter, writeMeter, noCompression)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+
+	if err := f.repair(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	log.Info("Opened ancient database", "database", datadir, "frozen", f.frozen)
+
+	return f, nil
+}
+
+// Close closes all the tables.
+func (f *freezer) Close() error {
+	var errs []error
+
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// HasAncient implements ethdb.AncientReader.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.has(number), nil
+	}
+	return false, nil
+}
+
+// Ancient implements ethdb.AncientReader.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.Retrieve(number)
+	}
+	return nil, errUnknownTable
+}
+
+// Ancients implements ethdb.AncientReader.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// AncientSize implements ethdb.AncientReader.
+func (f *freezer) AncientSize(kind string) (uint64, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.size()
+	}
+	return 0, errUnknownTable
+}
+
+// AppendAncient implements ethdb.AncientWriter. The tables get reverted to
+// the previous block on failure.
+func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
+	defer func() {
+		if err != nil {
+			if rerr := f.repair(); rerr != nil {
+				log.Crit("Failed to repair ancient database", "err", rerr)
+			}
+		}
+	}()
+
+	items := []struct {
+		kind string
+		data []byte
+	}{
+		{freezerHashTable, hash},
+		{freezerHeaderTable, header},
+		{freezerBodiesTable, body},
+		{freezerReceiptTable, receipts},
+		{freezerDifficultyTable, td},
+	}
+
+	for _, item := range items {
+		if err = f.tables[item.kind].Append(number, item.data); err != nil {
+			log.Error("Failed to append ancient", "kind", item.kind, "number", number, "err", err)
+			return err
+		}
+	}
+
+	atomic.AddUint64(&f.frozen, 1)
+
+	return nil
+}
+
+// TruncateAncients implements ethdb.AncientWriter.
+func (f *freezer) TruncateAncients(items uint64) error {
+	if atomic.LoadUint64(&f.frozen) <= items {
+		return nil
+	}
+
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+
+	atomic.StoreUint64(&f.frozen, items)
+
+	return nil
+}
+
+// Sync implements ethdb.AncientWriter.
+func (f *freezer) Sync() error {
+	var errs []error
+
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// repair truncates all the tables to the shortest one.
+func (f *freezer) repair() error {
+	frozen := uint64(0)
+	first := true
+
+	for _, table := range f.tables {
+		items := atomic.LoadUint64(&table.items)
+		if first || items < frozen {
+			frozen = items
+			first = false
+		}
+	}
+
+	for _, table := range f.tables {
+		if err := table.truncate(frozen); err != nil {
+			return err
+		}
+	}
+
+	atomic.StoreUint64(&f.frozen, frozen)
+
+	return nil
+}
@@ -0,0 +1,494 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/metrics"
+
+	"github.com/golang/snappy"
+)
+
+var (
+	// errClosed is returned if an operation attempts to use a closed table.
+	errClosed = errors.New("closed")
+
+	// errOutOfBounds is returned if the item requested is not in the table.
+	errOutOfBounds = errors.New("out of bounds")
+)
+
+const (
+	// indexEntrySize is the size of a single index entry.
+	indexEntrySize = 6
+
+	// freezerTableSize is the maximum size of a single data file.
+	freezerTableSize = 2 * 1000 * 1000 * 1000
+)
+
+// indexEntry points to the end of an item within a data file.
+type indexEntry struct {
+	filenum uint16 // data file of the item
+	offset  uint32 // end of the item within the data file
+}
+
+func (i *indexEntry) unmarshalBinary(b []byte) {
+	i.filenum = binary.BigEndian.Uint16(b[:2])
+	i.offset = binary.BigEndian.Uint32(b[2:6])
+}
+
+func (i *indexEntry) marshalBinary() []byte {
+	b := make([]byte, indexEntrySize)
+	binary.BigEndian.PutUint16(b[:2], i.filenum)
+	binary.BigEndian.PutUint32(b[2:6], i.offset)
+	return b
+}
+
+// freezerTable is an append-only store of a single kind of chain data. Items
+// are appended to data files of limited size, while the index file holds
+// an entry per item pointing to its end.
+//
+// The index starts with a zero entry, so the item N spans from the end
+// pointed by the entry N to the end pointed by the entry N+1. An item never
+// crosses data files.
+type freezerTable struct {
+	items uint64 // number of stored items, accessed atomically
+
+	noCompression bool   // whether snappy compression is disabled
+	maxFileSize   uint32 // maximum size of a single data file
+	name          string
+	path          string
+
+	index     *os.File            // index entries of all items
+	head      *os.File            // data file being appended to
+	headId    uint16              // number of the head data file
+	headBytes uint32              // size of the head data file
+	files     map[uint16]*os.File // all open data files, including the head
+
+	readMeter  metrics.Meter
+	writeMeter metrics.Meter
+
+	logger log.Logger
+	lock   sync.RWMutex
+}
+
+// newTable opens a freezer table with the default data file size limit.
+func newTable(path string, name string, readMeter, writeMeter metrics.Meter, noCompression bool) (*freezerTable, error) {
+	return newCustomTable(path, name, readMeter, writeMeter, freezerTableSize, noCompression)
+}
+
+// newCustomTable opens a freezer table, creating the files if needed and
+// repairing the leftovers of an interrupted append.
+func newCustomTable(path string, name string, readMeter, writeMeter metrics.Meter, maxFileSize uint32, noCompression bool) (*freezerTable, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	var idxName string
+	if noCompression {
+		idxName = fmt.Sprintf("%s.ridx", name)
+	} else {
+		idxName = fmt.Sprintf("%s.cidx", name)
+	}
+
+	index, err := os.OpenFile(filepath.Join(path, idxName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &freezerTable{
+		noCompression: noCompression,
+		maxFileSize:   maxFileSize,
+		name:          name,
+		path:          path,
+		index:         index,
+		files:         make(map[uint16]*os.File),
+		readMeter:     readMeter,
+		writeMeter:    writeMeter,
+		logger:        log.New("table", name),
+	}
+
+	if err := t.repair(); err != nil {
+		t.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// repair cuts off the index entries without data and the data without index
+// entries, which may be left by a crash during append.
+func (t *freezerTable) repair() error {
+	buffer := make([]byte, indexEntrySize)
+
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+
+	if stat.Size() == 0 {
+		if _, err := t.index.Write(buffer); err != nil {
+			return err
+		}
+	}
+
+	if overflow := stat.Size() % indexEntrySize; overflow != 0 {
+		if err := t.index.Truncate(stat.Size() - overflow); err != nil {
+			return err
+		}
+	}
+
+	if stat, err = t.index.Stat(); err != nil {
+		return err
+	}
+	indexSize := stat.Size()
+
+	var lastIndex indexEntry
+	if _, err := t.index.ReadAt(buffer, indexSize-indexEntrySize); err != nil {
+		return err
+	}
+	lastIndex.unmarshalBinary(buffer)
+
+	if t.head, err = t.openFile(lastIndex.filenum, os.O_RDWR|os.O_CREATE|os.O_APPEND); err != nil {
+		return err
+	}
+	if stat, err = t.head.Stat(); err != nil {
+		return err
+	}
+	contentSize := stat.Size()
+
+	for contentExp := int64(lastIndex.offset); contentExp != contentSize; contentExp = int64(lastIndex.offset) {
+		if contentExp < contentSize {
+			t.logger.Warn("Truncating dangling freezer data", "indexed", contentExp, "stored", contentSize)
+			if err := t.head.Truncate(contentExp); err != nil {
+				return err
+			}
+			contentSize = contentExp
+			continue
+		}
+
+		t.logger.Warn("Truncating dangling freezer index", "indexed", contentExp, "stored", contentSize)
+		indexSize -= indexEntrySize
+		if err := t.index.Truncate(indexSize); err != nil {
+			return err
+		}
+
+		var newLastIndex indexEntry
+		if _, err := t.index.ReadAt(buffer, indexSize-indexEntrySize); err != nil {
+			return err
+		}
+		newLastIndex.unmarshalBinary(buffer)
+
+		// The item may be the first one of the head data file
+		if newLastIndex.filenum != lastIndex.filenum {
+			t.releaseFile(lastIndex.filenum, true)
+			if t.head, err = t.openFile(newLastIndex.filenum, os.O_RDWR|os.O_APPEND); err != nil {
+				return err
+			}
+			if stat, err = t.head.Stat(); err != nil {
+				return err
+			}
+			contentSize = stat.Size()
+		}
+		lastIndex = newLastIndex
+	}
+
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	if err := t.head.Sync(); err != nil {
+		return err
+	}
+
+	t.items = uint64(indexSize/indexEntrySize - 1)
+	t.headId = lastIndex.filenum
+	t.headBytes = uint32(contentSize)
+
+	// Data files before the head are only read from
+	for i := uint16(0); i < t.headId; i++ {
+		if _, err := t.openFile(i, os.O_RDONLY); err != nil {
+			return err
+		}
+	}
+
+	t.logger.Debug("Opened freezer table", "items", t.items, "files", t.headId+1)
+
+	return nil
+}
+
+func (t *freezerTable) fileName(num uint16) string {
+	if t.noCompression {
+		return filepath.Join(t.path, fmt.Sprintf("%s.%04d.rdat", t.name, num))
+	}
+	return filepath.Join(t.path, fmt.Sprintf("%s.%04d.cdat", t.name, num))
+}
+
+func (t *freezerTable) openFile(num uint16, flag int) (*os.File, error) {
+	if f, ok := t.files[num]; ok {
+		return f, nil
+	}
+
+	f, err := os.OpenFile(t.fileName(num), flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	t.files[num] = f
+	return f, nil
+}
+
+func (t *freezerTable) releaseFile(num uint16, remove bool) {
+	if f, ok := t.files[num]; ok {
+		delete(t.files, num)
+		f.Close()
+		if remove {
+			os.Remove(f.Name())
+		}
+	}
+}
+
+// truncate discards all items starting at the number.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+
+	if atomic.LoadUint64(&t.items) <= items {
+		return nil
+	}
+
+	t.logger.Warn("Truncating freezer table", "items", t.items, "limit", items)
+
+	if err := t.index.Truncate(int64(items+1) * indexEntrySize); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, indexEntrySize)
+	if _, err := t.index.ReadAt(buffer, int64(items)*indexEntrySize); err != nil {
+		return err
+	}
+
+	var expected indexEntry
+	expected.unmarshalBinary(buffer)
+
+	if expected.filenum != t.headId {
+		for num := range t.files {
+			if num > expected.filenum {
+				t.releaseFile(num, true)
+			}
+		}
+		t.releaseFile(expected.filenum, false)
+
+		head, err := t.openFile(expected.filenum, os.O_RDWR|os.O_APPEND)
+		if err != nil {
+			return err
+		}
+		t.head = head
+		t.headId = expected.filenum
+	}
+
+	if err := t.head.Truncate(int64(expected.offset)); err != nil {
+		return err
+	}
+	t.headBytes = expected.offset
+
+	atomic.StoreUint64(&t.items, items)
+
+	return nil
+}
+
+// Append stores the next item. It's up to the caller to sync.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+
+	if items := atomic.LoadUint64(&t.items); items != item {
+		return fmt.Errorf("appending unexpected item: want %d, have %d", items, item)
+	}
+
+	if !t.noCompression {
+		blob = snappy.Encode(nil, blob)
+	}
+
+	size := uint32(len(blob))
+
+	// Move on to the next data file, if the item does not fit
+	if t.headBytes+size < size || t.headBytes+size > t.maxFileSize {
+		nextId := t.headId + 1
+
+		head, err := t.openFile(nextId, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND)
+		if err != nil {
+			return err
+		}
+
+		t.releaseFile(t.headId, false)
+		if _, err := t.openFile(t.headId, os.O_RDONLY); err != nil {
+			return err
+		}
+
+		t.head = head
+		t.headId = nextId
+		t.headBytes = 0
+	}
+
+	if _, err := t.head.Write(blob); err != nil {
+		return err
+	}
+	t.headBytes += size
+
+	entry := indexEntry{filenum: t.headId, offset: t.headBytes}
+	if _, err := t.index.Write(entry.marshalBinary()); err != nil {
+		return err
+	}
+
+	t.writeMeter.Mark(int64(size + indexEntrySize))
+	atomic.AddUint64(&t.items, 1)
+
+	return nil
+}
+
+// Retrieve looks up an item.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil {
+		return nil, errClosed
+	}
+
+	if atomic.LoadUint64(&t.items) <= item {
+		return nil, errOutOfBounds
+	}
+
+	buffer := make([]byte, 2*indexEntrySize)
+	if _, err := t.index.ReadAt(buffer, int64(item)*indexEntrySize); err != nil {
+		return nil, err
+	}
+
+	var start, end indexEntry
+	start.unmarshalBinary(buffer[:indexEntrySize])
+	end.unmarshalBinary(buffer[indexEntrySize:])
+
+	// The item is the first one of its data file
+	if start.filenum != end.filenum {
+		start.offset = 0
+	}
+
+	file, ok := t.files[end.filenum]
+	if !ok {
+		return nil, fmt.Errorf("missing data file %d", end.filenum)
+	}
+
+	blob := make([]byte, end.offset-start.offset)
+	if _, err := file.ReadAt(blob, int64(start.offset)); err != nil {
+		return nil, err
+	}
+
+	t.readMeter.Mark(int64(len(blob) + 2*indexEntrySize))
+
+	if t.noCompression {
+		return blob, nil
+	}
+	return snappy.Decode(nil, blob)
+}
+
+// has returns whether the item is stored.
+func (t *freezerTable) has(item uint64) bool {
+	return atomic.LoadUint64(&t.items) > item
+}
+
+// size returns the total disk size of the table.
+func (t *freezerTable) size() (uint64, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil {
+		return 0, errClosed
+	}
+
+	stat, err := t.index.Stat()
+	if err != nil {
+		return 0, err
+	}
+	total := uint64(stat.Size())
+
+	for _, f := range t.files {
+		if stat, err = f.Stat(); err != nil {
+			return 0, err
+		}
+		total += uint64(stat.Size())
+	}
+
+	return total, nil
+}
+
+// Sync flushes appended items to the disk.
+func (t *freezerTable) Sync() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	return t.head.Sync()
+}
+
+// Close closes all the files of the table.
+func (t *freezerTable) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var errs []error
+
+	if t.index != nil {
+		if err := t.index.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		t.index = nil
+	}
+
+	for num, f := range t.files {
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(t.files, num)
+	}
+	t.head = nil
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"energi.world/core/gen3/metrics"
+)
+
+// getChunk returns a chunk of data of the size, filled with the byte.
+func getChunk(size int, b int) []byte {
+	return bytes.Repeat([]byte{byte(b)}, size)
+}
+
+func newTestTable(t *testing.T, dir string, noCompression bool) *freezerTable {
+	// Up to three 15 bytes items per data file
+	table, err := newCustomTable(dir, "test", metrics.NilMeter{}, metrics.NilMeter{}, 50, noCompression)
+	if err != nil {
+		t.Fatalf("can't open table: %v", err)
+	}
+	return table
+}
+
+func checkTable(t *testing.T, table *freezerTable, items int) {
+	if have := int(table.items); have != items {
+		t.Fatalf("wrong items: have %d, want %d", have, items)
+	}
+
+	for i := 0; i < items; i++ {
+		blob, err := table.Retrieve(uint64(i))
+		if err != nil {
+			t.Fatalf("can't retrieve item %d: %v", i, err)
+		}
+		if !bytes.Equal(blob, getChunk(15, i)) {
+			t.Fatalf("wrong item %d: %x", i, blob)
+		}
+	}
+
+	if _, err := table.Retrieve(uint64(items)); err != errOutOfBounds {
+		t.Fatalf("unexpected error past the end: %v", err)
+	}
+}
+
+func TestFreezerTableBasics(t *testing.T) {
+	for _, noCompression := range []bool{true, false} {
+		dir, err := ioutil.TempDir("", "freezer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		table := newTestTable(t, dir, noCompression)
+		for i := 0; i < 255; i++ {
+			if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+				t.Fatalf("can't append item %d: %v", i, err)
+			}
+		}
+		if err := table.Append(300, getChunk(15, 0)); err == nil {
+			t.Fatalf("out of order append succeeded")
+		}
+		checkTable(t, table, 255)
+		table.Close()
+
+		// Everything is there after reopening
+		table = newTestTable(t, dir, noCompression)
+		checkTable(t, table, 255)
+		table.Close()
+	}
+}
+
+func TestFreezerTableRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table := newTestTable(t, dir, true)
+	for i := 0; i < 9; i++ {
+		table.Append(uint64(i), getChunk(15, i))
+	}
+	table.Close()
+
+	// Data of an interrupted append without the index entry
+	head, err := os.OpenFile(filepath.Join(dir, "test.0002.rdat"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Write(getChunk(15, 9))
+	head.Close()
+
+	table = newTestTable(t, dir, true)
+	checkTable(t, table, 9)
+	table.Close()
+
+	// Index entries without data, crossing the data files
+	if err := os.Truncate(filepath.Join(dir, "test.0002.rdat"), 0); err != nil {
+		t.Fatal(err)
+	}
+	index, err := os.OpenFile(filepath.Join(dir, "test.ridx"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index.Write([]byte{0, 2})
+	index.Close()
+
+	table = newTestTable(t, dir, true)
+	checkTable(t, table, 6)
+
+	// Appending continues in the new head
+	for i := 6; i < 12; i++ {
+		if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+			t.Fatalf("can't append item %d: %v", i, err)
+		}
+	}
+	checkTable(t, table, 12)
+	table.Close()
+}
+
+func TestFreezerTableTruncate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table := newTestTable(t, dir, true)
+	defer table.Close()
+
+	for i := 0; i < 30; i++ {
+		table.Append(uint64(i), getChunk(15, i))
+	}
+
+	if err := table.truncate(10); err != nil {
+		t.Fatalf("can't truncate: %v", err)
+	}
+	checkTable(t, table, 10)
+
+	if _, err := os.Stat(filepath.Join(dir, "test.0004.rdat")); !os.IsNotExist(err) {
+		t.Fatalf("data file after the head is kept: %v", err)
+	}
+
+	for i := 10; i < 20; i++ {
+		if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+			t.Fatalf("can't append item %d: %v", i, err)
+		}
+	}
+	checkTable(t, table, 20)
+}
@@ -41,6 +41,9 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// checkpointsKey tracks the dynamic checkpoints together with their signatures.
+	checkpointsKey = []byte("EnergiCheckpoints")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -53,11 +56,17 @@ var (
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
+	stakingHistoryPrefix = []byte("S") // stakingHistoryPrefix + section (uint64 big endian) + hash + address -> staking records
+	voteRecordPrefix     = []byte("V") // voteRecordPrefix + section (uint64 big endian) + hash + address -> vote records
+	voteTargetPrefix     = []byte("v") // voteTargetPrefix + section (uint64 big endian) + hash -> vote targets
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	StakingIndexPrefix   = []byte("iS") // StakingIndexPrefix is the data table of the staking history indexer to track its progress
+	VoteIndexPrefix      = []byte("iV") // VoteIndexPrefix is the data table of the masternode vote indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -123,6 +132,33 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// stakingHistoryKey = stakingHistoryPrefix + section (uint64 big endian) + hash + address
+func stakingHistoryKey(section uint64, hash common.Hash, addr common.Address) []byte {
+	key := append(append(append(stakingHistoryPrefix, make([]byte, 8)...), hash.Bytes()...), addr.Bytes()...)
+
+	binary.BigEndian.PutUint64(key[1:], section)
+
+	return key
+}
+
+// voteRecordKey = voteRecordPrefix + section (uint64 big endian) + hash + address
+func voteRecordKey(section uint64, hash common.Hash, addr common.Address) []byte {
+	key := append(append(append(voteRecordPrefix, make([]byte, 8)...), hash.Bytes()...), addr.Bytes()...)
+
+	binary.BigEndian.PutUint64(key[1:], section)
+
+	return key
+}
+
+// voteTargetKey = voteTargetPrefix + section (uint64 big endian) + hash
+func voteTargetKey(section uint64, hash common.Hash) []byte {
+	key := append(append(voteTargetPrefix, make([]byte, 8)...), hash.Bytes()...)
+
+	binary.BigEndian.PutUint64(key[1:], section)
+
+	return key
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -0,0 +1,311 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pruner implements offline deletion of stale state trie nodes.
+package pruner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rlp"
+	"energi.world/core/gen3/trie"
+
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// emptyCode is the known hash of the empty EVM bytecode.
+	emptyCode = crypto.Keccak256(nil)
+)
+
+// Pruner deletes all trie nodes and contract codes, which are not referenced
+// by the kept states. It must not run on a database used by a node.
+type Pruner struct {
+	chain  *core.BlockChain
+	db     ethdb.Database
+	triedb *trie.Database
+	keep   map[common.Hash]struct{}
+}
+
+// NewPruner creates a pruner for the chain database.
+func NewPruner(chain *core.BlockChain, db ethdb.Database) (*Pruner, error) {
+	return &Pruner{
+		chain:  chain,
+		db:     rawdb.KeyValueStore(db),
+		triedb: chain.StateCache().TrieDB(),
+		keep:   make(map[common.Hash]struct{}),
+	}, nil
+}
+
+// LatestCheckpoint returns the header of the latest validated checkpoint on
+// the canonical chain.
+func LatestCheckpoint(chain *core.BlockChain) (*types.Header, error) {
+	var target *types.Header
+
+	head := chain.CurrentBlock().NumberU64()
+
+	for _, cp := range chain.ListCheckpoints() {
+		if cp.Number > head || (target != nil && cp.Number <= target.Number.Uint64()) {
+			continue
+		}
+
+		if header := chain.GetHeaderByNumber(cp.Number); header != nil && header.Hash() == cp.Hash {
+			target = header
+		}
+	}
+
+	if target == nil {
+		return nil, errors.New("no checkpoint on the canonical chain")
+	}
+
+	return target, nil
+}
+
+// Prune keeps the state of the target block, all the states of the stake
+// maturity period before it, the newer states on disk and the genesis state.
+// Everything else is deleted. The chain gets stopped.
+func (p *Pruner) Prune(target *types.Header) error {
+	start := time.Now()
+
+	roots, err := p.persistStates(target)
+
+	// Recent states get written on stop, so look for them afterwards
+	p.chain.Stop()
+	if err != nil {
+		return err
+	}
+	roots = append(roots, p.diskStates(target)...)
+
+	for _, root := range roots {
+		if err := p.markTrie(root, true); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Marked state to keep", "states", len(roots), "nodes", len(p.keep),
+		"elapsed", common.PrettyDuration(time.Since(start)))
+
+	if err := p.sweep(); err != nil {
+		return err
+	}
+
+	cstart := time.Now()
+	log.Info("Compacting database")
+
+	if err := p.db.Compact(nil, nil); err != nil {
+		log.Error("Failed to compact database", "err", err)
+		return err
+	}
+
+	log.Info("State pruning successful", "target", target.Number,
+		"compaction", common.PrettyDuration(time.Since(cstart)),
+		"elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+// persistStates writes the states of the target block together with the stake
+// maturity period before it, regenerating missing ones. It returns their roots
+// together with the genesis root.
+func (p *Pruner) persistStates(target *types.Header) ([]common.Hash, error) {
+	chain := p.chain
+	number := target.Number.Uint64()
+
+	if header := chain.GetHeaderByNumber(number); header == nil || header.Hash() != target.Hash() {
+		return nil, fmt.Errorf("block %d is not canonical", number)
+	}
+
+	head := chain.CurrentBlock().NumberU64()
+	if number > head {
+		return nil, fmt.Errorf("block %d is above the head %d", number, head)
+	}
+
+	// lookupStakeWeight() needs every state of the period
+	var since uint64
+	if target.Time > energi_params.MaturityPeriod {
+		since = target.Time - energi_params.MaturityPeriod
+	}
+
+	first := target
+	for first.Number.Sign() > 0 && first.Time > since {
+		parent := chain.GetHeader(first.ParentHash, first.Number.Uint64()-1)
+		if parent == nil {
+			return nil, fmt.Errorf("missing parent of block %d", first.Number)
+		}
+		first = parent
+	}
+
+	roots := []common.Hash{chain.Genesis().Root()}
+
+	for n := first.Number.Uint64(); n <= number; n++ {
+		header := chain.GetHeaderByNumber(n)
+		if header == nil {
+			return nil, fmt.Errorf("missing block %d", n)
+		}
+
+		if chain.CalculateBlockState(header.Hash(), n) == nil {
+			return nil, fmt.Errorf("failed to regenerate state of block %d", n)
+		}
+
+		if err := p.triedb.Commit(header.Root, false); err != nil {
+			log.Error("Failed", "err", err)
+			return nil, err
+		}
+
+		roots = append(roots, header.Root)
+	}
+
+	log.Info("Persisted states to keep", "target", number, "since", first.Number)
+
+	return roots, nil
+}
+
+// diskStates returns the roots of the states on disk newer than the target.
+func (p *Pruner) diskStates(target *types.Header) (roots []common.Hash) {
+	head := p.chain.CurrentBlock().NumberU64()
+
+	for n := target.Number.Uint64() + 1; n <= head; n++ {
+		header := p.chain.GetHeaderByNumber(n)
+		if header == nil {
+			continue
+		}
+
+		if has, _ := p.db.Has(header.Root[:]); has {
+			roots = append(roots, header.Root)
+		}
+	}
+
+	return roots
+}
+
+// markTrie adds all nodes of the trie to the kept set. Accounts of the state
+// trie get their storage trie and code marked as well.
+func (p *Pruner) markTrie(root common.Hash, accounts bool) error {
+	if root == emptyRoot {
+		return nil
+	}
+
+	tr, err := trie.New(root, p.triedb)
+	if err != nil {
+		return err
+	}
+
+	it := tr.NodeIterator(nil)
+
+	for descend := true; it.Next(descend); {
+		descend = true
+
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			// Shared parts of the tries get marked only once
+			if _, ok := p.keep[hash]; ok {
+				descend = false
+				continue
+			}
+			p.keep[hash] = struct{}{}
+		}
+
+		if !accounts || !it.Leaf() {
+			continue
+		}
+
+		var acc state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &acc); err != nil {
+			return err
+		}
+
+		if err := p.markTrie(acc.Root, false); err != nil {
+			return err
+		}
+
+		if !bytes.Equal(acc.CodeHash, emptyCode) {
+			p.keep[common.BytesToHash(acc.CodeHash)] = struct{}{}
+		}
+	}
+
+	return it.Error()
+}
+
+// sweep deletes all trie nodes and codes, which are not marked.
+func (p *Pruner) sweep() error {
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		batch   = p.db.NewBatch()
+		deleted int
+		size    common.StorageSize
+	)
+
+	it := p.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		// Trie nodes and codes are keyed by their hashes only
+		key := it.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+
+		if _, ok := p.keep[common.BytesToHash(key)]; ok {
+			continue
+		}
+
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		deleted++
+		size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning state data", "nodes", deleted, "size", size,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	log.Info("Pruned state data", "nodes", deleted, "size", size,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/consensus/ethash"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/params"
+)
+
+func countNodes(t *testing.T, db *ethdb.LDBDatabase) (count int) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) == common.HashLength {
+			count++
+		}
+	}
+	return count
+}
+
+func TestPrune(t *testing.T) {
+	t.Run("archive", func(t *testing.T) {
+		// All the newer states are kept
+		testPrune(t, true, func(number uint64) bool { return number >= 24 })
+	})
+	t.Run("full", func(t *testing.T) {
+		// The head states are written on stop
+		testPrune(t, false, func(number uint64) bool { return number >= 24 && number <= 30 || number >= 39 })
+	})
+}
+
+func testPrune(t *testing.T, archive bool, kept func(number uint64) bool) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x1000")
+		signer   = types.HomesteadSigner{}
+		gspec    = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				sender: {Balance: big.NewInt(1000000000000000000)},
+				// SSTORE(CALLVALUE, NUMBER)
+				contract: {Balance: common.Big0, Code: []byte{0x43, 0x34, 0x55}},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+
+	gendb := ethdb.NewMemDatabase()
+	gspec.MustCommit(gendb)
+
+	// 10 minutes between blocks, so the maturity period spans 6 blocks
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, gendb, 40, func(i int, b *core.BlockGen) {
+		b.OffsetTime(590)
+
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(sender), contract, big.NewInt(int64(i+1)), 100000, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+		tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(sender), common.BigToAddress(big.NewInt(int64(i+0x2000))), big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+	})
+
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{
+		Disabled:       archive,
+		TrieCleanLimit: 256,
+		TrieDirtyLimit: 256,
+		TrieTimeLimit:  5 * time.Minute,
+	}, gspec.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	before := countNodes(t, db)
+
+	p, err := NewPruner(chain, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Prune(blocks[29].Header()); err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+
+	// Without archive mode, the maturity period states get written instead
+	if after := countNodes(t, db); archive && after >= before {
+		t.Fatalf("nothing pruned: before %d, after %d", before, after)
+	}
+
+	sdb := state.NewDatabase(db)
+
+	checkState := func(root common.Hash) bool {
+		statedb, err := state.New(root, sdb)
+		if err != nil {
+			return false
+		}
+		it := state.NewNodeIterator(statedb)
+		for it.Next() {
+		}
+		if it.Error != nil {
+			t.Fatalf("incomplete state %x: %v", root, it.Error)
+		}
+		return true
+	}
+
+	if !checkState(genesis.Root()) {
+		t.Fatalf("genesis state pruned")
+	}
+	for _, block := range blocks {
+		// The maturity period before block 30 starts at block 24
+		want := kept(block.NumberU64())
+		if have := checkState(block.Root()); have != want {
+			t.Fatalf("block %d: state kept %v, want %v", block.NumberU64(), have, want)
+		}
+	}
+}
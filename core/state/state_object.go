@@ -83,6 +83,7 @@ type stateObject struct {
 
 	originStorage Storage // Storage cache of original entries to dedup rewrites
 	dirtyStorage  Storage // Storage entries that need to be flushed to disk
+	fakeStorage   Storage // Storage replaced by the caller for debugging purposes
 
 	// Cache flags.
 	// When an object is marked suicided it will be delete from the trie
@@ -165,6 +166,10 @@ func (c *stateObject) getTrie(db Database) Trie {
 
 // GetState retrieves a value from the account storage trie.
 func (self *stateObject) GetState(db Database, key common.Hash) common.Hash {
+	// If the storage is replaced, only the fake entries exist
+	if self.fakeStorage != nil {
+		return self.fakeStorage[key]
+	}
 	// If we have a dirty value for this state entry, return it
 	value, dirty := self.dirtyStorage[key]
 	if dirty {
@@ -176,6 +181,10 @@ func (self *stateObject) GetState(db Database, key common.Hash) common.Hash {
 
 // GetCommittedState retrieves a value from the committed account storage trie.
 func (self *stateObject) GetCommittedState(db Database, key common.Hash) common.Hash {
+	// If the storage is replaced, only the fake entries exist
+	if self.fakeStorage != nil {
+		return self.fakeStorage[key]
+	}
 	// If we have the original value cached, return that
 	value, cached := self.originStorage[key]
 	if cached {
@@ -215,9 +224,25 @@ func (self *stateObject) SetState(db Database, key, value common.Hash) {
 }
 
 func (self *stateObject) setState(key, value common.Hash) {
+	if self.fakeStorage != nil {
+		self.fakeStorage[key] = value
+		return
+	}
 	self.dirtyStorage[key] = value
 }
 
+// SetStorage replaces the entire storage of the object with the given one.
+// It must only be used for debugging, as the replacement is neither journaled
+// nor committed to the database.
+func (self *stateObject) SetStorage(storage map[common.Hash]common.Hash) {
+	if self.fakeStorage == nil {
+		self.fakeStorage = make(Storage)
+	}
+	for key, value := range storage {
+		self.fakeStorage[key] = value
+	}
+}
+
 // updateTrie writes cached storage modifications into the object's storage trie.
 func (self *stateObject) updateTrie(db Database) Trie {
 	tr := self.getTrie(db)
@@ -308,6 +333,9 @@ func (self *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.code = self.code
 	stateObject.dirtyStorage = self.dirtyStorage.Copy()
 	stateObject.originStorage = self.originStorage.Copy()
+	if self.fakeStorage != nil {
+		stateObject.fakeStorage = self.fakeStorage.Copy()
+	}
 	stateObject.suicided = self.suicided
 	stateObject.dirtyCode = self.dirtyCode
 	stateObject.deleted = self.deleted
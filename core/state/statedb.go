@@ -360,6 +360,17 @@ func (self *StateDB) SetState(addr common.Address, key, value common.Hash) {
 	}
 }
 
+// SetStorage replaces the entire storage of the account with the given one.
+// It must only be used for debugging, e.g. for calls with state overrides.
+func (self *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetStorage(storage)
+		// Keep the replacement in copies
+		self.stateObjectsDirty[addr] = struct{}{}
+	}
+}
+
 // Suicide marks the given account as suicided.
 // This clears the account balance.
 //
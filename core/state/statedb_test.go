@@ -167,6 +167,44 @@ func TestCopy(t *testing.T) {
 	}
 }
 
+// Tests that replaced storage hides all the existing slots, including
+// the ones of copies.
+func TestSetStorage(t *testing.T) {
+	db := NewDatabase(ethdb.NewMemDatabase())
+	state, _ := New(common.Hash{}, db)
+
+	addr := common.BytesToAddress([]byte{0x1})
+	state.SetState(addr, common.Hash{0x1}, common.Hash{0x11})
+	state.SetState(addr, common.Hash{0x2}, common.Hash{0x22})
+	root, _ := state.Commit(false)
+
+	state, _ = New(root, db)
+	state.SetStorage(addr, map[common.Hash]common.Hash{{0x2}: {0x33}})
+	state.SetState(addr, common.Hash{0x3}, common.Hash{0x44})
+
+	copy := state.Copy()
+	for i, s := range []*StateDB{state, copy} {
+		for key, want := range map[common.Hash]common.Hash{
+			{0x1}: {},
+			{0x2}: {0x33},
+			{0x3}: {0x44},
+		} {
+			if have := s.GetState(addr, key); have != want {
+				t.Errorf("state %d, slot %x: have %x, want %x", i, key, have, want)
+			}
+			if have := s.GetCommittedState(addr, key); have != want {
+				t.Errorf("state %d, committed slot %x: have %x, want %x", i, key, have, want)
+			}
+		}
+	}
+
+	// The original storage is untouched
+	state, _ = New(root, db)
+	if have := state.GetState(addr, common.Hash{0x1}); have != (common.Hash{0x11}) {
+		t.Errorf("original storage changed: %x", have)
+	}
+}
+
 func TestSnapshotRandom(t *testing.T) {
 	config := &quick.Config{MaxCount: 1000}
 	err := quick.Check((*snapshotTest).run, config)
@@ -24,7 +24,9 @@ import (
 	"energi.world/core/gen3/accounts"
 	"energi.world/core/gen3/accounts/abi/bind"
 	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/consensus"
 	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/rawdb"
 	"energi.world/core/gen3/core/state"
 	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/core/vm"
@@ -33,6 +35,9 @@ import (
 	"energi.world/core/gen3/event"
 	"energi.world/core/gen3/params"
 	"energi.world/core/gen3/rpc"
+
+	energi_common "energi.world/core/gen3/energi/common"
+	energi_params "energi.world/core/gen3/energi/params"
 )
 
 // Backend interface provides the common API services (that are provided by
@@ -50,6 +55,7 @@ type Backend interface {
 	// BlockChain API
 	SetHead(number uint64)
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
+	HeaderByHash(ctx context.Context, blockHash common.Hash) (*types.Header, error)
 	BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error)
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
 	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
@@ -59,7 +65,6 @@ type Backend interface {
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
-	BlockChain() *core.BlockChain
 
 	// TxPool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
@@ -72,11 +77,15 @@ type Backend interface {
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+	Engine() consensus.Engine
 
 	AddLocalCheckpoint(num uint64, hash common.Hash) error
 	ListCheckpoints() []core.CheckpointInfo
 	CheckpointSignatures(cp core.Checkpoint) []core.CheckpointSignature
 
+	StakingHistory(ctx context.Context, addr common.Address, from, to uint64) ([]rawdb.StakingRecord, error)
+	MasternodeVotes(ctx context.Context, senders []common.Address, from, to uint64) ([]rawdb.VoteRecord, []rawdb.VoteTarget, error)
+
 	IsPublicService() bool
 	OnSyncedHeadUpdates(cb func())
 }
@@ -107,3 +116,23 @@ func createSignerCallback(
 			account, *password, tx, chain_id)
 	}
 }
+
+// proxyHashGen is a backend agnostic version of GeneralProxyHashGen, so
+// governed proxy logs can also get filtered by light clients.
+func proxyHashGen(backend Backend) energi_common.GeneralProxyHashFunc {
+	return func(addr common.Address, blockheight *uint64) *common.Hash {
+		blockNr := rpc.LatestBlockNumber
+		if blockheight != nil && *blockheight > 0 {
+			blockNr = rpc.BlockNumber(*blockheight)
+		}
+
+		statedb, _, err := backend.StateAndHeaderByNumber(context.Background(), blockNr)
+		if err != nil || statedb == nil {
+			return nil
+		}
+
+		// Get a hash that allows logs from governed proxy checkpoint contract to be filtered.
+		prxyHash := statedb.GetState(addr, energi_params.Storage_ProxyImpl)
+		return &prxyHash
+	}
+}
@@ -109,17 +109,18 @@ func (b *BlacklistAPI) BlacklistInfo() (res []BLInfo, err error) {
 }
 
 func (b *BlacklistAPI) blacklistInfo(num *big.Int) (interface{}, error) {
+	return blacklistRegistryInfo(b.backend, nil)
+}
+
+func blacklistRegistryInfo(backend Backend, num *big.Int) ([]BLInfo, error) {
 	registry, err := energi_abi.NewIBlacklistRegistryCaller(
-		energi_params.Energi_BlacklistRegistry, b.backend.(bind.ContractCaller))
+		energi_params.Energi_BlacklistRegistry, backend.(bind.ContractCaller))
 	if err != nil {
 		log.Error("Failed", "err", err)
 		return nil, err
 	}
 
-	call_opts := &bind.CallOpts{
-		Pending:  true,
-		GasLimit: energi_params.UnlimitedGas,
-	}
+	call_opts := callOptsAt(num)
 	addresses, err := registry.EnumerateAll(call_opts)
 	if err != nil {
 		log.Error("Failed", "err", err)
@@ -141,17 +142,17 @@ func (b *BlacklistAPI) blacklistInfo(num *big.Int) (interface{}, error) {
 			continue
 		}
 
-		enforceInfo, err := proposalInfo(b.backend, proposals.Enforce)
+		enforceInfo, err := proposalInfo(backend, proposals.Enforce, num)
 		if err != nil {
 			log.Debug("Enforce info error", "addr", addr, "err", err)
 		}
 
-		revokeInfo, err := proposalInfo(b.backend, proposals.Revoke)
+		revokeInfo, err := proposalInfo(backend, proposals.Revoke, num)
 		if err != nil {
 			log.Debug("Revoke info error", "addr", addr, "err", err)
 		}
 
-		drainInfo, err := proposalInfo(b.backend, proposals.Drain)
+		drainInfo, err := proposalInfo(backend, proposals.Drain, num)
 		if err != nil {
 			log.Debug("Drain info error", "addr", addr, "err", err)
 		}
@@ -288,7 +289,7 @@ func (b *BlacklistAPI) compensationInfo(num *big.Int) (interface{}, error) {
 		return nil, err
 	}
 
-	return treasuryInfo(comp_fund, b.backend)
+	return treasuryInfo(comp_fund, b.backend, nil)
 }
 
 func (b *BlacklistAPI) CompensationPropose(
@@ -17,14 +17,18 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"math/big"
+	"sync"
 
 	"github.com/pborman/uuid"
 
 	"energi.world/core/gen3/accounts/abi/bind"
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/event"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/rpc"
 
@@ -42,6 +46,11 @@ type GovernanceAPI struct {
 	backend    Backend
 	uInfoCache *energi_common.CacheStorage
 	bInfoCache *energi_common.CacheStorage
+
+	proposalFeed event.Feed
+	trackerMtx   sync.Mutex
+	trackerRefs  int
+	trackerQuit  chan struct{}
 }
 
 func NewGovernanceAPI(b Backend) *GovernanceAPI {
@@ -200,11 +209,27 @@ type ProposalInfo struct {
 	Balance      *hexutil.Big
 }
 
-func getBalance(backend Backend, address common.Address) (*hexutil.Big, error) {
-	curr_block := backend.CurrentBlock()
+// callOptsAt reads at the given block or the pending state, if num is nil.
+func callOptsAt(num *big.Int) *bind.CallOpts {
+	if num == nil {
+		return &bind.CallOpts{
+			Pending:  true,
+			GasLimit: energi_params.UnlimitedGas,
+		}
+	}
+	return &bind.CallOpts{
+		BlockNumber: num,
+		GasLimit:    energi_params.UnlimitedGas,
+	}
+}
+
+func getBalance(backend Backend, address common.Address, num *big.Int) (*hexutil.Big, error) {
+	if num == nil {
+		num = backend.CurrentBlock().Number()
+	}
 
 	state, _, err := backend.StateAndHeaderByNumber(
-		nil, rpc.BlockNumber(curr_block.Number().Int64()))
+		nil, rpc.BlockNumber(num.Int64()))
 	if err != nil {
 		log.Error("Failed at state", "err", err)
 		return nil, err
@@ -213,7 +238,7 @@ func getBalance(backend Backend, address common.Address) (*hexutil.Big, error) {
 	return (*hexutil.Big)(state.GetBalance(address)), nil
 }
 
-func proposalInfo(backend Backend, address common.Address) (*ProposalInfo, error) {
+func proposalInfo(backend Backend, address common.Address, num *big.Int) (*ProposalInfo, error) {
 	if (address == common.Address{}) {
 		return nil, nil
 	}
@@ -225,10 +250,7 @@ func proposalInfo(backend Backend, address common.Address) (*ProposalInfo, error
 		return nil, err
 	}
 
-	call_opts := &bind.CallOpts{
-		Pending:  true,
-		GasLimit: energi_params.UnlimitedGas,
-	}
+	call_opts := callOptsAt(num)
 
 	proposer, err := proposal.FeePayer(call_opts)
 	if err != nil {
@@ -284,7 +306,7 @@ func proposalInfo(backend Backend, address common.Address) (*ProposalInfo, error
 		return nil, err
 	}
 
-	balance, err := getBalance(backend, address)
+	balance, err := getBalance(backend, address, num)
 	if err != nil {
 		log.Error("Failed at getBalance", "err", err)
 		return nil, err
@@ -324,10 +346,7 @@ func (g *GovernanceAPI) upgradeProposalInfo(num *big.Int, proxy common.Address)
 		return nil, err
 	}
 
-	call_opts := &bind.CallOpts{
-		Pending:  true,
-		GasLimit: energi_params.UnlimitedGas,
-	}
+	call_opts := callOptsAt(num)
 	proposals, err := proxy_obj.ListUpgradeProposals(call_opts)
 	if err != nil {
 		log.Error("Failed ListUpgradeProposals", "err", err)
@@ -336,7 +355,7 @@ func (g *GovernanceAPI) upgradeProposalInfo(num *big.Int, proxy common.Address)
 
 	ret := make([]UpgradeProposalInfo, 0, len(proposals))
 	for i, p := range proposals {
-		pInfo, err := proposalInfo(g.backend, p)
+		pInfo, err := proposalInfo(g.backend, p, num)
 		if err != nil {
 			log.Error("Failed at proposalInfo", "err", err)
 			continue
@@ -575,10 +594,10 @@ func (g *GovernanceAPI) BudgetInfo() (*BudgetInfo, error) {
 }
 
 func (g *GovernanceAPI) budgetInfo(num *big.Int) (interface{}, error) {
-	return treasuryInfo(energi_params.Energi_Treasury, g.backend)
+	return treasuryInfo(energi_params.Energi_Treasury, g.backend, nil)
 }
 
-func treasuryInfo(addr common.Address, backend Backend) (interface{}, error) {
+func treasuryInfo(addr common.Address, backend Backend, num *big.Int) (interface{}, error) {
 	treasury, err := energi_abi.NewITreasuryCaller(
 		addr, backend.(bind.ContractCaller))
 	if err != nil {
@@ -593,10 +612,7 @@ func treasuryInfo(addr common.Address, backend Backend) (interface{}, error) {
 		return nil, err
 	}
 
-	call_opts := &bind.CallOpts{
-		Pending:  true,
-		GasLimit: energi_params.UnlimitedGas,
-	}
+	call_opts := callOptsAt(num)
 
 	proposals, err := treasury.ListProposals(call_opts)
 	if err != nil {
@@ -613,7 +629,7 @@ func treasuryInfo(addr common.Address, backend Backend) (interface{}, error) {
 
 	ret := make([]BudgetProposalInfo, 0, len(proposals))
 	for i, p := range proposals {
-		pInfo, err := proposalInfo(backend, p)
+		pInfo, err := proposalInfo(backend, p, num)
 		if err != nil {
 			log.Debug("Failed at proposalInfo", "err", err)
 			continue
@@ -648,7 +664,7 @@ func treasuryInfo(addr common.Address, backend Backend) (interface{}, error) {
 		ret[i].RefUUID = uuid.UUID(common.LeftPadBytes(ref_uuid.Bytes(), 16)).String()
 	}
 
-	balance, err := getBalance(backend, impl)
+	balance, err := getBalance(backend, impl, num)
 	if err != nil {
 		log.Error("Failed at getBalance", "err", err)
 	}
@@ -713,3 +729,136 @@ func treasuryPropose(
 
 	return
 }
+
+//=============================================================================
+// Generic proposal API
+//=============================================================================
+
+// NOTE: generic proposals implement IProposal, so VoteAccept, VoteReject and
+//       WithdrawFee apply to them as is.
+
+// TODO: GenericPropose, in the way of BudgetPropose. Standalone generic
+//       proposals are deployed directly, which needs DeployGenericProposalV1.
+//       The binding has to be regenerated with bytecode by
+//       `make energi-contracts` (solc 0.5.16) first.
+
+type GenericProposalInfo struct {
+	ProposalInfo
+	Parent       common.Address
+	FeeAmount    *hexutil.Big
+	FinishWeight *hexutil.Big
+}
+
+func (g *GovernanceAPI) GenericProposalInfo(
+	proposal common.Address,
+) (*GenericProposalInfo, error) {
+	pInfo, err := proposalInfo(g.backend, proposal, nil)
+	if err != nil || pInfo == nil {
+		log.Error("Failed at proposalInfo", "err", err)
+		return nil, err
+	}
+
+	caller, err := energi_abi.NewGenericProposalV1Caller(
+		proposal, g.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed NewGenericProposalV1Caller", "err", err)
+		return nil, err
+	}
+
+	call_opts := &bind.CallOpts{
+		Pending:  true,
+		GasLimit: energi_params.UnlimitedGas,
+	}
+
+	parent, err := caller.Parent(call_opts)
+	if err != nil {
+		log.Error("Failed Parent", "err", err)
+		return nil, err
+	}
+
+	fee_amount, err := caller.FeeAmount(call_opts)
+	if err != nil {
+		log.Error("Failed FeeAmount", "err", err)
+		return nil, err
+	}
+
+	finish_w, err := caller.FinishWeight(call_opts)
+	if err != nil {
+		log.Error("Failed FinishWeight", "err", err)
+		return nil, err
+	}
+
+	return &GenericProposalInfo{
+		ProposalInfo: *pInfo,
+		Parent:       parent,
+		FeeAmount:    (*hexutil.Big)(fee_amount),
+		FinishWeight: (*hexutil.Big)(finish_w),
+	}, nil
+}
+
+// GenericProposals lists standalone generic proposals which were created or
+// received votes in the block range. Such proposals are created by a plain
+// account, so their parent has no code unlike proposals of the governance
+// contracts.
+func (g *GovernanceAPI) GenericProposals(
+	ctx context.Context,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) ([]GenericProposalInfo, error) {
+	from := blockNumber(g.backend, fromBlock)
+	to := blockNumber(g.backend, toBlock)
+
+	if from > to {
+		return nil, errors.New("Invalid block range")
+	}
+
+	_, targets, err := g.backend.MasternodeVotes(ctx, nil, from, to)
+	if err != nil {
+		log.Error("MasternodeVotes failed", "err", err)
+		return nil, err
+	}
+
+	call_opts := &bind.CallOpts{
+		Pending:  true,
+		GasLimit: energi_params.UnlimitedGas,
+	}
+
+	ret := make([]GenericProposalInfo, 0, len(targets))
+	known := make(map[common.Address]bool, len(targets))
+
+	for _, t := range targets {
+		if t.Kind == rawdb.VoteCheckpoint || known[t.Target] {
+			continue
+		}
+		known[t.Target] = true
+
+		// NOTE: most of created contracts are not proposals at all, so
+		//       they are filtered out quietly by their parent first.
+		caller, err := energi_abi.NewGenericProposalV1Caller(
+			t.Target, g.backend.(bind.ContractCaller))
+		if err != nil {
+			log.Error("Failed NewGenericProposalV1Caller", "err", err)
+			return nil, err
+		}
+
+		parent, err := caller.Parent(call_opts)
+		if err != nil {
+			continue
+		}
+
+		code, err := g.backend.(bind.ContractCaller).CodeAt(ctx, parent, nil)
+		if err != nil || len(code) > 0 {
+			continue
+		}
+
+		pInfo, err := g.GenericProposalInfo(t.Target)
+		if err != nil || pInfo == nil {
+			log.Debug("Failed at GenericProposalInfo", "proposal", t.Target, "err", err)
+			continue
+		}
+
+		ret = append(ret, *pInfo)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,387 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+
+	"energi.world/core/gen3/accounts/abi/bind"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rpc"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+//=============================================================================
+// Governance proposal lifecycle subscriptions
+//=============================================================================
+
+// Proposal lifecycle event types
+const (
+	ProposalCreated  = "created"
+	ProposalVoted    = "voted"
+	ProposalQuorum   = "quorum"
+	ProposalFinished = "finished"
+	ProposalAccepted = "accepted"
+	ProposalPaid     = "paid"
+)
+
+// Proposal sources
+const (
+	ProposalSourceTreasury         = "treasury"
+	ProposalSourceBlacklistEnforce = "blacklistEnforce"
+	ProposalSourceBlacklistRevoke  = "blacklistRevoke"
+	ProposalSourceBlacklistDrain   = "blacklistDrain"
+	ProposalSourceUpgrade          = "upgrade"
+)
+
+const (
+	govChainHeadChanSize = 10
+)
+
+// All governed proxies which accept upgrade proposals
+var governedProxies = []common.Address{
+	energi_params.Energi_Treasury,
+	energi_params.Energi_MasternodeRegistry,
+	energi_params.Energi_StakerReward,
+	energi_params.Energi_BackboneReward,
+	energi_params.Energi_SporkRegistry,
+	energi_params.Energi_CheckpointRegistry,
+	energi_params.Energi_BlacklistRegistry,
+	energi_params.Energi_MasternodeToken,
+}
+
+// ProposalEvent is emitted on every observed proposal state transition.
+type ProposalEvent struct {
+	ProposalInfo
+	Type        string
+	Source      string
+	Target      common.Address // blacklist target or upgraded proxy
+	PaidAmount  *hexutil.Big   `json:",omitempty"`
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// ProposalEventCriteria limits the events sent to a subscriber. Empty
+// lists match everything.
+type ProposalEventCriteria struct {
+	Types   []string
+	Sources []string
+}
+
+func (c *ProposalEventCriteria) matches(ev *ProposalEvent) bool {
+	if c == nil {
+		return true
+	}
+	return matchesAny(c.Types, ev.Type) && matchesAny(c.Sources, ev.Source)
+}
+
+func matchesAny(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == value {
+			return true
+		}
+	}
+	return false
+}
+
+type trackedProposal struct {
+	source string
+	target common.Address
+	info   *ProposalInfo
+	paid   *big.Int
+}
+
+type proposalSnapshot map[common.Address]*trackedProposal
+
+// ProposalEvents creates a subscription for Treasury, blacklist registry and
+// GovernedProxy upgrade proposal lifecycle events.
+func (g *GovernanceAPI) ProposalEvents(ctx context.Context, crit *ProposalEventCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan *ProposalEvent, govChainHeadChanSize)
+		eventsSub := g.proposalFeed.Subscribe(events)
+		g.retainTracker()
+
+		defer func() {
+			eventsSub.Unsubscribe()
+			g.releaseTracker()
+		}()
+
+		for {
+			select {
+			case ev := <-events:
+				if crit.matches(ev) {
+					notifier.Notify(rpcSub.ID, ev)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// retainTracker starts the proposal tracker on the first subscription.
+func (g *GovernanceAPI) retainTracker() {
+	g.trackerMtx.Lock()
+	defer g.trackerMtx.Unlock()
+
+	g.trackerRefs++
+	if g.trackerRefs == 1 {
+		g.trackerQuit = make(chan struct{})
+		go g.proposalTracker(g.trackerQuit)
+	}
+}
+
+// releaseTracker stops the proposal tracker after the last subscription.
+func (g *GovernanceAPI) releaseTracker() {
+	g.trackerMtx.Lock()
+	defer g.trackerMtx.Unlock()
+
+	g.trackerRefs--
+	if g.trackerRefs == 0 {
+		close(g.trackerQuit)
+	}
+}
+
+func (g *GovernanceAPI) proposalTracker(quitCh chan struct{}) {
+	headCh := make(chan core.ChainHeadEvent, govChainHeadChanSize)
+	headSub := g.backend.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	known := g.proposalSnapshot(g.backend.CurrentBlock().Number())
+
+	for {
+		select {
+		case ev := <-headCh:
+			// NOTE: the whole difference is processed once synced
+			if dl := g.backend.Downloader(); dl != nil && dl.Synchronising() {
+				continue
+			}
+
+			header := ev.Block.Header()
+			current := g.proposalSnapshot(header.Number)
+			g.refreshRemoved(known, current, header.Number)
+
+			for _, pev := range proposalEvents(known, current, header) {
+				g.proposalFeed.Send(pev)
+			}
+
+			known = current
+		case <-headSub.Err():
+			return
+		case <-quitCh:
+			return
+		}
+	}
+}
+
+// proposalSnapshot collects all the governance proposals listed at the
+// given block. NOTE: the pending state may be ahead of the block the events
+// are reported for.
+func (g *GovernanceAPI) proposalSnapshot(num *big.Int) proposalSnapshot {
+	res := make(proposalSnapshot)
+
+	budget, err := treasuryInfo(energi_params.Energi_Treasury, g.backend, num)
+	if err != nil {
+		log.Error("Failed at treasuryInfo", "err", err)
+	} else {
+		for _, p := range budget.(*BudgetInfo).Proposals {
+			info := p.ProposalInfo
+			res[info.Proposal] = &trackedProposal{
+				source: ProposalSourceTreasury,
+				info:   &info,
+				paid:   p.PaidAmount.ToInt(),
+			}
+		}
+	}
+
+	blacklist, err := blacklistRegistryInfo(g.backend, num)
+	if err != nil {
+		log.Error("Failed at blacklistRegistryInfo", "err", err)
+	}
+	for _, bl := range blacklist {
+		for source, info := range map[string]*ProposalInfo{
+			ProposalSourceBlacklistEnforce: bl.Enforce,
+			ProposalSourceBlacklistRevoke:  bl.Revoke,
+			ProposalSourceBlacklistDrain:   bl.Drain,
+		} {
+			if info == nil {
+				continue
+			}
+			res[info.Proposal] = &trackedProposal{
+				source: source,
+				target: bl.Target,
+				info:   info,
+			}
+		}
+	}
+
+	for _, proxy := range governedProxies {
+		upgrades, err := g.upgradeProposalInfo(num, proxy)
+		if err != nil {
+			log.Error("Failed at upgradeProposalInfo", "proxy", proxy, "err", err)
+			continue
+		}
+		for _, p := range upgrades {
+			info := p.ProposalInfo
+			res[info.Proposal] = &trackedProposal{
+				source: ProposalSourceUpgrade,
+				target: proxy,
+				info:   &info,
+			}
+		}
+	}
+
+	return res
+}
+
+// refreshRemoved re-reads proposals which disappeared from the lists. They
+// usually get removed on the same block their final transition happens.
+func (g *GovernanceAPI) refreshRemoved(known, current proposalSnapshot, num *big.Int) {
+	for addr, prev := range known {
+		if _, ok := current[addr]; ok {
+			continue
+		}
+
+		// NOTE: destroyed proposals fail here and are simply dropped
+		info, err := proposalInfo(g.backend, addr, num)
+		if err != nil || info == nil {
+			log.Debug("Dropping removed proposal", "proposal", addr, "err", err)
+			continue
+		}
+
+		updated := &trackedProposal{
+			source: prev.source,
+			target: prev.target,
+			info:   info,
+		}
+
+		if prev.source == ProposalSourceTreasury {
+			updated.paid = prev.paid
+
+			budget, err := energi_abi.NewIBudgetProposalCaller(
+				addr, g.backend.(bind.ContractCaller))
+			if err == nil {
+				paid, err := budget.PaidAmount(callOptsAt(num))
+				if err == nil {
+					updated.paid = paid
+				}
+			}
+		}
+
+		current[addr] = updated
+	}
+}
+
+// proposalEvents returns events for all state transitions between two
+// snapshots. Unknown proposals transition from the initial state.
+func proposalEvents(known, current proposalSnapshot, header *types.Header) []*ProposalEvent {
+	var res []*ProposalEvent
+
+	// Keep the event order stable
+	addrs := make([]common.Address, 0, len(current))
+	for addr := range current {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+
+	for _, addr := range addrs {
+		curr := current[addr]
+		prev, ok := known[addr]
+		if !ok {
+			prev = &trackedProposal{
+				info: &ProposalInfo{
+					AcceptWeight: (*hexutil.Big)(common.Big0),
+					RejectWeight: (*hexutil.Big)(common.Big0),
+				},
+				paid: common.Big0,
+			}
+		}
+
+		emit := func(evType string) {
+			ev := &ProposalEvent{
+				ProposalInfo: *curr.info,
+				Type:         evType,
+				Source:       curr.source,
+				Target:       curr.target,
+				BlockNumber:  header.Number.Uint64(),
+				BlockHash:    header.Hash(),
+			}
+			if curr.paid != nil {
+				ev.PaidAmount = (*hexutil.Big)(curr.paid)
+			}
+			res = append(res, ev)
+		}
+
+		if !ok {
+			emit(ProposalCreated)
+		}
+
+		prevAccept, currAccept := prev.info.AcceptWeight.ToInt(), curr.info.AcceptWeight.ToInt()
+		prevReject, currReject := prev.info.RejectWeight.ToInt(), curr.info.RejectWeight.ToInt()
+
+		if prevAccept.Cmp(currAccept) != 0 || prevReject.Cmp(currReject) != 0 {
+			emit(ProposalVoted)
+
+			quorum := curr.info.QuorumWeight.ToInt()
+			prevVotes := new(big.Int).Add(prevAccept, prevReject)
+			currVotes := new(big.Int).Add(currAccept, currReject)
+
+			if quorum != nil && prevVotes.Cmp(quorum) < 0 && currVotes.Cmp(quorum) >= 0 {
+				emit(ProposalQuorum)
+			}
+		}
+
+		if !prev.info.Finished && curr.info.Finished {
+			emit(ProposalFinished)
+		}
+
+		if !prev.info.Accepted && curr.info.Accepted {
+			emit(ProposalAccepted)
+		}
+
+		if prev.paid != nil && curr.paid != nil && curr.paid.Cmp(prev.paid) > 0 {
+			emit(ProposalPaid)
+		}
+	}
+
+	return res
+}
@@ -39,16 +39,18 @@ const (
 )
 
 type MasternodeAPI struct {
-	backend    Backend
-	nodesCache *energi_common.CacheStorage
-	statsCache *energi_common.CacheStorage
+	backend     Backend
+	nodesCache  *energi_common.CacheStorage
+	statsCache  *energi_common.CacheStorage
+	payoutCache *energi_common.CacheStorage
 }
 
 func NewMasternodeAPI(b Backend) *MasternodeAPI {
 	r := &MasternodeAPI{
-		backend:    b,
-		nodesCache: energi_common.NewCacheStorage(),
-		statsCache: energi_common.NewCacheStorage(),
+		backend:     b,
+		nodesCache:  energi_common.NewCacheStorage(),
+		statsCache:  energi_common.NewCacheStorage(),
+		payoutCache: energi_common.NewCacheStorage(),
 	}
 	b.OnSyncedHeadUpdates(func() {
 		r.ListMasternodes()
@@ -432,11 +434,27 @@ func (m *MasternodeAPI) Announce(
 		return
 	}
 
-	var (
-		ipv4address uint32
-		pubkey      [2][32]byte
-	)
+	masternode, ipv4address, pubkey, err := m.announceArgs(enode_url)
+	if err != nil {
+		return
+	}
 
+	//---
+	tx, err := registry.Announce(masternode, ipv4address, pubkey)
+
+	if tx != nil {
+		txhash = tx.Hash()
+		log.Info("Note: please wait until the TX gets into a block!", "tx", txhash.Hex())
+	}
+
+	return
+}
+
+// announceArgs converts enode URL into MasternodeRegistryV2.announce()
+// arguments.
+func (m *MasternodeAPI) announceArgs(
+	enode_url string,
+) (masternode common.Address, ipv4address uint32, pubkey [2][32]byte, err error) {
 	//---
 	res, err := enode.ParseV4(enode_url)
 	if err != nil {
@@ -478,16 +496,7 @@ func (m *MasternodeAPI) Announce(
 	copy(pubkey[1][:], pk[32:33])
 
 	//---
-	masternode := crypto.PubkeyToAddress(*res.Pubkey())
-
-	//---
-	tx, err := registry.Announce(masternode, ipv4address, pubkey)
-
-	if tx != nil {
-		txhash = tx.Hash()
-		log.Info("Note: please wait until the TX gets into a block!", "tx", txhash.Hex())
-	}
-
+	masternode = crypto.PubkeyToAddress(*res.Pubkey())
 	return
 }
 
@@ -0,0 +1,214 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"errors"
+	"math/big"
+
+	"energi.world/core/gen3/accounts/abi/bind"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/log"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_consensus "energi.world/core/gen3/energi/consensus"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+//=============================================================================
+// Masternode payout queue
+//=============================================================================
+
+// MNPayoutInfo is the place of a masternode in the MasternodeRegistryV2
+// reward rotation. Each block pays PaymentsPerBlock reward parts and every
+// active masternode gets a part per minimal collateral in a row.
+type MNPayoutInfo struct {
+	Masternode     common.Address
+	Owner          common.Address
+	IsActive       bool
+	QueuePosition  uint64 // among active masternodes, 0 is being paid
+	PartsBefore    uint64 // reward parts due to masternodes ahead
+	Parts          uint64 // reward parts due in this round
+	NextBlock      uint64
+	NextTime       uint64 // expected time of NextBlock
+	ExpectedReward *hexutil.Big
+}
+
+// PayoutQueue returns all masternodes in the reward rotation order starting
+// from the one being paid.
+func (m *MasternodeAPI) PayoutQueue() (res []MNPayoutInfo, err error) {
+	data, err := m.payoutCache.Get(m.backend, m.payoutQueue)
+	if err != nil || data == nil {
+		log.Error("PayoutQueue failed", "err", err)
+		return
+	}
+
+	res = data.([]MNPayoutInfo)
+
+	return
+}
+
+// PayoutInfo returns the queue position and the next payout estimate of
+// a single masternode.
+func (m *MasternodeAPI) PayoutInfo(owner_or_mn common.Address) (*MNPayoutInfo, error) {
+	queue, err := m.PayoutQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range queue {
+		if info.Masternode == owner_or_mn || info.Owner == owner_or_mn {
+			return &info, nil
+		}
+	}
+
+	return nil, errors.New("Unknown masternode")
+}
+
+func (m *MasternodeAPI) payoutQueue(num *big.Int) (interface{}, error) {
+	registry, err := energi_abi.NewMasternodeRegistryV2Caller(
+		energi_params.Energi_MasternodeRegistry, m.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	call_opts := &bind.CallOpts{
+		Pending:  true,
+		GasLimit: energi_params.UnlimitedGas,
+	}
+
+	current, err := registry.CurrentMasternode(call_opts)
+	if err != nil {
+		log.Error("Failed CurrentMasternode", "err", err)
+		return nil, err
+	}
+
+	current_payouts, err := registry.CurrentPayouts(call_opts)
+	if err != nil {
+		log.Error("Failed CurrentPayouts", "err", err)
+		return nil, err
+	}
+
+	payments_per_block, err := registry.PaymentsPerBlock(call_opts)
+	if err != nil {
+		log.Error("Failed PaymentsPerBlock", "err", err)
+		return nil, err
+	}
+	if payments_per_block.Sign() <= 0 {
+		return nil, errors.New("Invalid payments per block")
+	}
+	ppb := payments_per_block.Uint64()
+
+	cycle, err := superblockCycle(m.backend, call_opts)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := m.backend.CurrentBlock().Header()
+	height := parent.Number.Uint64()
+	engine, _ := m.backend.Engine().(*energi_consensus.Energi)
+
+	reward, err := registry.GetReward(
+		call_opts, new(big.Int).SetUint64(rewardBlock(height, 0, cycle)))
+	if err != nil {
+		log.Error("Failed GetReward", "err", err)
+		return nil, err
+	}
+	reward_part := new(big.Int).Div(reward, payments_per_block)
+
+	mnlist, err := m.ListMasternodes()
+	if err != nil {
+		return nil, err
+	}
+	mninfos := make(map[common.Address]MNInfo, len(mnlist))
+	for _, mninfo := range mnlist {
+		mninfos[mninfo.Masternode] = mninfo
+	}
+
+	// NOTE: enumeration starts from the current masternode
+	masternodes, err := registry.Enumerate(call_opts)
+	if err != nil {
+		log.Error("Failed Enumerate", "err", err)
+		return nil, err
+	}
+
+	res := make([]MNPayoutInfo, 0, len(masternodes))
+	position := uint64(0)
+	parts_before := uint64(0)
+
+	for _, mn := range masternodes {
+		mninfo, ok := mninfos[mn]
+		if !ok {
+			continue
+		}
+
+		info := MNPayoutInfo{
+			Masternode:     mn,
+			Owner:          mninfo.Owner,
+			IsActive:       mninfo.IsActive,
+			ExpectedReward: (*hexutil.Big)(new(big.Int)),
+		}
+
+		if mninfo.IsActive {
+			status, err := registry.MnStatus(call_opts, mn)
+			if err != nil {
+				log.Debug("MnStatus error", "mn", mn, "err", err)
+				continue
+			}
+
+			parts := status.SeqPayouts.Uint64()
+			if mn == current {
+				parts -= current_payouts.Uint64()
+			}
+
+			info.QueuePosition = position
+			info.PartsBefore = parts_before
+			info.Parts = parts
+			info.NextBlock = rewardBlock(height, parts_before/ppb, cycle)
+			info.ExpectedReward = (*hexutil.Big)(new(big.Int).Mul(
+				reward_part, new(big.Int).SetUint64(parts)))
+
+			if engine != nil {
+				info.NextTime = engine.EstimateBlockTime(
+					&headerReader{m.backend}, parent, info.NextBlock)
+			}
+
+			position++
+			parts_before += parts
+		}
+
+		res = append(res, info)
+	}
+
+	return res, nil
+}
+
+// rewardBlock returns the number of the offset-th block after the head which
+// pays masternode rewards. Superblocks pay only the treasury.
+func rewardBlock(head, offset, cycle uint64) uint64 {
+	number := head + offset + 1
+
+	for {
+		next := head + offset + 1 + (number/cycle - head/cycle)
+		if next == number {
+			return number
+		}
+		number = next
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rpc"
+)
+
+//=============================================================================
+// Voting record and participation analytics
+//=============================================================================
+
+type MNVote struct {
+	Proposal common.Address
+	Vote     string // "accept" or "reject"
+	Number   uint64
+	TxHash   common.Hash
+}
+
+type MNVotingRecord struct {
+	Owner      common.Address
+	Masternode common.Address
+	FromBlock  uint64
+	ToBlock    uint64
+	Votes      []MNVote
+}
+
+type MNParticipation struct {
+	Owner           common.Address
+	Masternode      common.Address
+	FromBlock       uint64
+	ToBlock         uint64
+	Proposals       uint64 // proposals voted by anyone
+	ProposalVotes   uint64
+	ProposalRate    float64
+	Checkpoints     uint64 // checkpoints proposed
+	CheckpointVotes uint64
+	CheckpointRate  float64
+}
+
+type MNMissedCheckpoint struct {
+	Number     uint64 // block of the checkpoint proposal
+	Checkpoint common.Address
+	CPNumber   uint64
+	CPHash     common.Hash
+}
+
+type mnVotes struct {
+	owner      common.Address
+	masternode common.Address
+	from       uint64
+	to         uint64
+	records    []rawdb.VoteRecord
+	targets    []rawdb.VoteTarget
+}
+
+// votes collects vote records of both the masternode and its owner. Targets
+// before the current announcement of the masternode are not eligible.
+func (m *MasternodeAPI) votes(
+	ctx context.Context,
+	owner_or_mn common.Address,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) (*mnVotes, error) {
+	from := blockNumber(m.backend, fromBlock)
+	to := blockNumber(m.backend, toBlock)
+
+	if from > to {
+		return nil, errors.New("Invalid block range")
+	}
+
+	res := &mnVotes{
+		owner:      owner_or_mn,
+		masternode: owner_or_mn,
+		from:       from,
+		to:         to,
+	}
+
+	mninfo, err := m.MasternodeInfo(owner_or_mn)
+	if err != nil {
+		log.Error("Failed at MasternodeInfo", "err", err)
+		return nil, err
+	}
+
+	senders := []common.Address{owner_or_mn}
+
+	// NOTE: denounced masternodes are only known by the given address
+	if (mninfo.Masternode != common.Address{}) {
+		res.owner = mninfo.Owner
+		res.masternode = mninfo.Masternode
+		senders = []common.Address{mninfo.Owner, mninfo.Masternode}
+
+		if mninfo.AnnouncedBlock > from {
+			from = mninfo.AnnouncedBlock
+		}
+	}
+
+	records, targets, err := m.backend.MasternodeVotes(ctx, senders, res.from, res.to)
+	if err != nil {
+		log.Error("MasternodeVotes failed", "err", err)
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Number < records[j].Number
+	})
+
+	res.records = records
+	res.targets = make([]rawdb.VoteTarget, 0, len(targets))
+
+	for _, t := range targets {
+		if t.Number >= from {
+			res.targets = append(res.targets, t)
+		}
+	}
+
+	return res, nil
+}
+
+// VotingRecord returns all proposal votes of the masternode owner.
+func (m *MasternodeAPI) VotingRecord(
+	ctx context.Context,
+	owner_or_mn common.Address,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) (*MNVotingRecord, error) {
+	votes, err := m.votes(ctx, owner_or_mn, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MNVotingRecord{
+		Owner:      votes.owner,
+		Masternode: votes.masternode,
+		FromBlock:  votes.from,
+		ToBlock:    votes.to,
+		Votes:      []MNVote{},
+	}
+
+	for _, r := range votes.records {
+		var vote string
+
+		switch r.Kind {
+		case rawdb.VoteAccept:
+			vote = "accept"
+		case rawdb.VoteReject:
+			vote = "reject"
+		default:
+			continue
+		}
+
+		res.Votes = append(res.Votes, MNVote{
+			Proposal: r.Target,
+			Vote:     vote,
+			Number:   r.Number,
+			TxHash:   r.TxHash,
+		})
+	}
+
+	return res, nil
+}
+
+// Participation returns proposal voting and checkpoint signing rates.
+func (m *MasternodeAPI) Participation(
+	ctx context.Context,
+	owner_or_mn common.Address,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) (*MNParticipation, error) {
+	votes, err := m.votes(ctx, owner_or_mn, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MNParticipation{
+		Owner:      votes.owner,
+		Masternode: votes.masternode,
+		FromBlock:  votes.from,
+		ToBlock:    votes.to,
+	}
+
+	proposals := make(map[common.Address]bool)
+	checkpoints := make(map[common.Address]bool)
+
+	for _, t := range votes.targets {
+		switch t.Kind {
+		case rawdb.VoteCheckpoint:
+			checkpoints[t.Target] = true
+		case rawdb.VoteCreate:
+			// NOTE: only proposals which received votes are counted
+		default:
+			proposals[t.Target] = true
+		}
+	}
+
+	for _, r := range votes.records {
+		if r.Kind == rawdb.VoteCheckpoint {
+			if checkpoints[r.Target] {
+				res.CheckpointVotes++
+				checkpoints[r.Target] = false
+			}
+		} else if proposals[r.Target] {
+			res.ProposalVotes++
+			proposals[r.Target] = false
+		}
+	}
+
+	res.Proposals = uint64(len(proposals))
+	res.Checkpoints = uint64(len(checkpoints))
+
+	if res.Proposals > 0 {
+		res.ProposalRate = float64(res.ProposalVotes) / float64(res.Proposals)
+	}
+	if res.Checkpoints > 0 {
+		res.CheckpointRate = float64(res.CheckpointVotes) / float64(res.Checkpoints)
+	}
+
+	return res, nil
+}
+
+// MissedCheckpoints returns checkpoints proposed in the block range which
+// the masternode has not signed.
+func (m *MasternodeAPI) MissedCheckpoints(
+	ctx context.Context,
+	owner_or_mn common.Address,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) ([]MNMissedCheckpoint, error) {
+	votes, err := m.votes(ctx, owner_or_mn, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := make(map[common.Address]bool)
+	for _, r := range votes.records {
+		if r.Kind == rawdb.VoteCheckpoint {
+			signed[r.Target] = true
+		}
+	}
+
+	res := []MNMissedCheckpoint{}
+	for _, t := range votes.targets {
+		if t.Kind != rawdb.VoteCheckpoint || signed[t.Target] {
+			continue
+		}
+
+		res = append(res, MNMissedCheckpoint{
+			Number:     t.Number,
+			Checkpoint: t.Target,
+			CPNumber:   t.CPNum,
+			CPHash:     t.CPHash,
+		})
+	}
+
+	return res, nil
+}
@@ -217,7 +217,7 @@ func (m *MigrationAPI) SearchGen3DestinationByGen2Address(
 		Context: context.WithValue(
 			context.Background(),
 			energi_params.GeneralProxyCtxKey,
-			energi_common.GeneralProxyHashGen(m.backend.BlockChain()),
+			proxyHashGen(m.backend),
 		),
 		End: &currBlockNo,
 	}
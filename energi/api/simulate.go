@@ -0,0 +1,439 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/pborman/uuid"
+
+	ethereum "energi.world/core/gen3"
+	"energi.world/core/gen3/accounts/abi"
+	"energi.world/core/gen3/accounts/abi/bind"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rpc"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+// revertSelector is the selector of Error(string) used by Solidity require().
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+//=============================================================================
+// Transaction simulation
+//=============================================================================
+
+type SimBalanceChange struct {
+	Address common.Address
+	Before  *hexutil.Big
+	After   *hexutil.Big
+}
+
+type SimCollateralChange struct {
+	Owner  common.Address
+	Before *hexutil.Big
+	After  *hexutil.Big
+}
+
+type SimMasternodeEntry struct {
+	Masternode     common.Address
+	Collateral     *hexutil.Big
+	AnnouncedBlock uint64
+}
+
+type SimMasternodeChange struct {
+	Owner  common.Address
+	Before *SimMasternodeEntry
+	After  *SimMasternodeEntry
+}
+
+// SimulationResult is the outcome of a transaction executed against
+// the pending state without broadcasting it.
+type SimulationResult struct {
+	From         common.Address
+	To           common.Address
+	Gas          uint64
+	Failed       bool
+	RevertReason string
+	Balances     []SimBalanceChange
+	Collateral   *SimCollateralChange `json:",omitempty"`
+	Masternode   *SimMasternodeChange `json:",omitempty"`
+	Logs         []*types.Log
+}
+
+// stateCaller serves contract calls from the given state.
+type stateCaller struct {
+	backend Backend
+	state   *state.StateDB
+	header  *types.Header
+}
+
+func (sc *stateCaller) CodeAt(
+	ctx context.Context,
+	contract common.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return sc.state.GetCode(contract), nil
+}
+
+func (sc *stateCaller) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	value := call.Value
+	if value == nil {
+		value = common.Big0
+	}
+
+	msg := types.NewMessage(
+		call.From, call.To, 0, value, call.Gas, common.Big0, call.Data, false)
+
+	ret, _, failed, err := applySimMessage(ctx, sc.backend, sc.state.Copy(), sc.header, msg)
+	if err == nil && failed {
+		err = errors.New("Call reverted")
+	}
+	return ret, err
+}
+
+// applySimMessage executes the message preserving the sender balance, which
+// the backend EVM setup overrides for plain calls.
+func applySimMessage(
+	ctx context.Context,
+	backend Backend,
+	statedb *state.StateDB,
+	header *types.Header,
+	msg types.Message,
+) ([]byte, uint64, bool, error) {
+	balance := statedb.GetBalance(msg.From())
+
+	evm, vmError, err := backend.GetEVM(ctx, msg, statedb, header)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	statedb.SetBalance(msg.From(), balance)
+
+	gp := new(core.GasPool).AddGas(msg.Gas())
+	ret, gas, failed, err := core.ApplyMessage(evm, msg, gp)
+	if vmErr := vmError(); vmErr != nil {
+		return nil, 0, false, vmErr
+	}
+	return ret, gas, failed, err
+}
+
+// revertReason decodes the message of a failed Solidity require().
+func revertReason(ret []byte) string {
+	if len(ret) < 4 || !bytes.Equal(ret[:4], revertSelector) {
+		return ""
+	}
+
+	str_type, err := abi.NewType("string", nil)
+	if err != nil {
+		return ""
+	}
+
+	var reason string
+	if err := (abi.Arguments{{Type: str_type}}).Unpack(&reason, ret[4:]); err != nil {
+		return ""
+	}
+	return reason
+}
+
+// simulateTx executes a transaction against the pending state. The diff
+// callback fills operation specific changes from the state callers before
+// and after the transaction.
+func simulateTx(
+	backend Backend,
+	from common.Address,
+	to common.Address,
+	value *big.Int,
+	gas uint64,
+	data []byte,
+	diff func(pre, post bind.ContractCaller, res *SimulationResult),
+) (*SimulationResult, error) {
+	ctx := context.Background()
+
+	statedb, header, err := backend.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if statedb == nil || err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	gas_price, err := backend.SuggestPrice(ctx)
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	nonce := statedb.GetNonce(from)
+	if value == nil {
+		value = common.Big0
+	}
+
+	pre_state := statedb.Copy()
+	msg := types.NewMessage(from, &to, nonce, value, gas, gas_price, data, false)
+
+	statedb.Prepare(common.Hash{}, header.Hash(), 0)
+	ret, gas_used, failed, err := applySimMessage(ctx, backend, statedb, header, msg)
+	if err != nil {
+		// Consensus errors like insufficient balance
+		log.Debug("Simulation failed", "err", err)
+		return nil, err
+	}
+
+	res := &SimulationResult{
+		From:   from,
+		To:     to,
+		Gas:    gas_used,
+		Failed: failed,
+		Logs:   statedb.GetLogs(common.Hash{}),
+		Balances: []SimBalanceChange{{
+			Address: from,
+			Before:  (*hexutil.Big)(pre_state.GetBalance(from)),
+			After:   (*hexutil.Big)(statedb.GetBalance(from)),
+		}},
+	}
+
+	if failed {
+		res.RevertReason = revertReason(ret)
+	}
+
+	if res.Logs == nil {
+		res.Logs = []*types.Log{}
+	}
+
+	if diff != nil {
+		diff(
+			&stateCaller{backend, pre_state, header},
+			&stateCaller{backend, statedb, header},
+			res,
+		)
+	}
+
+	return res, nil
+}
+
+func packCall(abi_json string, method string, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(abi_json))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack(method, args...)
+}
+
+func simCallOpts() *bind.CallOpts {
+	return &bind.CallOpts{
+		GasLimit: energi_params.UnlimitedGas,
+	}
+}
+
+func simCollateral(owner common.Address) func(pre, post bind.ContractCaller, res *SimulationResult) {
+	return func(pre, post bind.ContractCaller, res *SimulationResult) {
+		collateral := func(caller bind.ContractCaller) *hexutil.Big {
+			token, err := energi_abi.NewIMasternodeTokenCaller(
+				energi_params.Energi_MasternodeToken, caller)
+			if err != nil {
+				return nil
+			}
+			balance, err := token.BalanceOf(simCallOpts(), owner)
+			if err != nil {
+				log.Debug("Failed BalanceOf", "err", err)
+				return nil
+			}
+			return (*hexutil.Big)(balance)
+		}
+
+		res.Collateral = &SimCollateralChange{
+			Owner:  owner,
+			Before: collateral(pre),
+			After:  collateral(post),
+		}
+
+		simMasternode(owner)(pre, post, res)
+	}
+}
+
+func simMasternode(owner common.Address) func(pre, post bind.ContractCaller, res *SimulationResult) {
+	return func(pre, post bind.ContractCaller, res *SimulationResult) {
+		entry := func(caller bind.ContractCaller) *SimMasternodeEntry {
+			registry, err := energi_abi.NewIMasternodeRegistryV2Caller(
+				energi_params.Energi_MasternodeRegistry, caller)
+			if err != nil {
+				return nil
+			}
+			// NOTE: it fails for unknown owners
+			info, err := registry.OwnerInfo(simCallOpts(), owner)
+			if err != nil {
+				return nil
+			}
+			return &SimMasternodeEntry{
+				Masternode:     info.Masternode,
+				Collateral:     (*hexutil.Big)(info.Collateral),
+				AnnouncedBlock: info.AnnouncedBlock.Uint64(),
+			}
+		}
+
+		res.Masternode = &SimMasternodeChange{
+			Owner:  owner,
+			Before: entry(pre),
+			After:  entry(post),
+		}
+	}
+}
+
+// SimulateDepositCollateral executes DepositCollateral without sending it.
+func (m *MasternodeAPI) SimulateDepositCollateral(
+	dst common.Address,
+	amount *hexutil.Big,
+) (*SimulationResult, error) {
+	data, err := packCall(energi_abi.IMasternodeTokenABI, "depositCollateral")
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		m.backend, dst, energi_params.Energi_MasternodeToken,
+		amount.ToInt(), mntokenCallGas, data, simCollateral(dst))
+}
+
+// SimulateWithdrawCollateral executes WithdrawCollateral without sending it.
+func (m *MasternodeAPI) SimulateWithdrawCollateral(
+	dst common.Address,
+	amount *hexutil.Big,
+) (*SimulationResult, error) {
+	data, err := packCall(energi_abi.IMasternodeTokenABI, "withdrawCollateral", amount.ToInt())
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		m.backend, dst, energi_params.Energi_MasternodeToken,
+		common.Big0, mntokenCallGas, data, simCollateral(dst))
+}
+
+// SimulateAnnounce executes Announce without sending it.
+func (m *MasternodeAPI) SimulateAnnounce(
+	owner common.Address,
+	enode_url string,
+) (*SimulationResult, error) {
+	masternode, ipv4address, pubkey, err := m.announceArgs(enode_url)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := packCall(energi_abi.IMasternodeRegistryV2ABI, "announce",
+		masternode, ipv4address, pubkey)
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		m.backend, owner, energi_params.Energi_MasternodeRegistry,
+		common.Big0, masternodeCallGas, data, simMasternode(owner))
+}
+
+// SimulateDenounce executes Denounce without sending it.
+func (m *MasternodeAPI) SimulateDenounce(owner common.Address) (*SimulationResult, error) {
+	registry, err := energi_abi.NewIMasternodeRegistryV2Caller(
+		energi_params.Energi_MasternodeRegistry, m.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	ownerinfo, err := registry.OwnerInfo(&bind.CallOpts{
+		Pending:  true,
+		From:     owner,
+		GasLimit: energi_params.UnlimitedGas,
+	}, owner)
+	if err != nil {
+		log.Error("Not found", "owner", owner)
+		return nil, err
+	}
+
+	data, err := packCall(energi_abi.IMasternodeRegistryV2ABI, "denounce", ownerinfo.Masternode)
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		m.backend, owner, energi_params.Energi_MasternodeRegistry,
+		common.Big0, masternodeCallGas, data, simMasternode(owner))
+}
+
+// SimulateBudgetPropose executes BudgetPropose without sending it.
+func (g *GovernanceAPI) SimulateBudgetPropose(
+	amount *hexutil.Big,
+	ref_uuid string,
+	period uint64,
+	fee *hexutil.Big,
+	payer common.Address,
+) (*SimulationResult, error) {
+	ref_uuid_b := uuid.Parse(ref_uuid)
+	if ref_uuid_b == nil {
+		err := errors.New("Failed to parse UUID")
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	data, err := packCall(energi_abi.ITreasuryABI, "propose",
+		amount.ToInt(),
+		new(big.Int).SetBytes(ref_uuid_b),
+		new(big.Int).SetUint64(period))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		g.backend, payer, energi_params.Energi_Treasury,
+		fee.ToInt(), proposalCallGas, data, nil)
+}
+
+// SimulateUpgradePropose executes UpgradePropose without sending it.
+func (g *GovernanceAPI) SimulateUpgradePropose(
+	proxy common.Address,
+	new_impl common.Address,
+	period uint64,
+	fee *hexutil.Big,
+	payer common.Address,
+) (*SimulationResult, error) {
+	data, err := packCall(energi_abi.IGovernedProxyABI, "proposeUpgrade",
+		new_impl, new(big.Int).SetUint64(period))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	return simulateTx(
+		g.backend, payer, proxy,
+		fee.ToInt(), upgradeCallGas, data, nil)
+}
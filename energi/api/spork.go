@@ -0,0 +1,218 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"errors"
+	"math/big"
+
+	"energi.world/core/gen3/accounts/abi/bind"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/log"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_common "energi.world/core/gen3/energi/common"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+// SporkAPI exposes the SporkRegistry governance to the node operators.
+type SporkAPI struct {
+	backend    Backend
+	gov        *GovernanceAPI
+	sInfoCache *energi_common.CacheStorage
+}
+
+func NewSporkAPI(b Backend) *SporkAPI {
+	r := &SporkAPI{
+		backend:    b,
+		gov:        &GovernanceAPI{backend: b},
+		sInfoCache: energi_common.NewCacheStorage(),
+	}
+	b.OnSyncedHeadUpdates(func() {
+		r.SporkInfo()
+	})
+	return r
+}
+
+type SporkInfo struct {
+	Proxy           common.Address
+	Impl            common.Address
+	EmergencySigner common.Address
+	CallGas         *hexutil.Big
+	XferGas         *hexutil.Big
+	Proposals       []UpgradeProposalInfo
+}
+
+func (s *SporkAPI) SporkInfo() (*SporkInfo, error) {
+	data, err := s.sInfoCache.Get(s.backend, s.sporkInfo)
+	if err != nil || data == nil {
+		log.Error("SporkInfo failed", "err", err)
+		return nil, err
+	}
+
+	return data.(*SporkInfo), nil
+}
+
+func (s *SporkAPI) sporkInfo(num *big.Int) (interface{}, error) {
+	call_opts := &bind.CallOpts{
+		Pending:  true,
+		GasLimit: energi_params.UnlimitedGas,
+	}
+
+	proxy, err := energi_abi.NewIGovernedProxyCaller(
+		energi_params.Energi_SporkRegistry, s.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed NewIGovernedProxyCaller", "err", err)
+		return nil, err
+	}
+
+	impl, err := proxy.Impl(call_opts)
+	if err != nil {
+		log.Error("Failed Impl", "err", err)
+		return nil, err
+	}
+
+	registry, err := energi_abi.NewSporkRegistryV2Caller(
+		energi_params.Energi_SporkRegistry, s.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed NewSporkRegistryV2Caller", "err", err)
+		return nil, err
+	}
+
+	limits, err := registry.ConsensusGasLimits(call_opts)
+	if err != nil {
+		log.Error("Failed ConsensusGasLimits", "err", err)
+		return nil, err
+	}
+
+	emergency_signer, err := registry.EmergencySigner(call_opts)
+	if err != nil {
+		// NOTE: SporkRegistryV1 has no emergency signer
+		log.Debug("Failed EmergencySigner", "err", err)
+	}
+
+	proposals, err := s.gov.upgradeProposalInfo(num, energi_params.Energi_SporkRegistry)
+	if err != nil {
+		log.Error("SporkRegistry info fetch failed", "err", err)
+		return nil, err
+	}
+
+	ret := &SporkInfo{
+		Proxy:           energi_params.Energi_SporkRegistry,
+		Impl:            impl,
+		EmergencySigner: emergency_signer,
+		CallGas:         (*hexutil.Big)(limits.CallGas),
+		XferGas:         (*hexutil.Big)(limits.XferGas),
+		Proposals:       proposals,
+	}
+
+	return ret, nil
+}
+
+func (s *SporkAPI) isSporkProposal(proposal common.Address) bool {
+	info, err := s.SporkInfo()
+	if err != nil || info == nil {
+		return false
+	}
+
+	for _, p := range info.Proposals {
+		if p.Proposal == proposal {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *SporkAPI) SporkPropose(
+	new_impl common.Address,
+	period uint64,
+	fee *hexutil.Big,
+	payer common.Address,
+	password *string,
+) (txhash common.Hash, err error) {
+	return s.gov.UpgradePropose(
+		energi_params.Energi_SporkRegistry,
+		new_impl, period, fee, payer, password)
+}
+
+func (s *SporkAPI) SporkEmergencyPropose(
+	proxy common.Address,
+	new_impl common.Address,
+	password *string,
+) (txhash common.Hash, err error) {
+	if proxy != energi_params.Energi_SporkRegistry {
+		err = errors.New("Not a spork registry proxy!")
+		log.Error("Failed", "err", err)
+		return
+	}
+
+	info, err := s.SporkInfo()
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return
+	}
+
+	if (info.EmergencySigner == common.Address{}) {
+		err = errors.New("Emergency signer is not configured!")
+		log.Error("Failed", "err", err)
+		return
+	}
+
+	// NOTE: emergency proposals must have zero period and zero fee
+	return s.gov.UpgradePropose(
+		proxy, new_impl, 0, (*hexutil.Big)(common.Big0),
+		info.EmergencySigner, password)
+}
+
+func (s *SporkAPI) SporkVoteAccept(
+	proposal common.Address,
+	mn_owner common.Address,
+	password *string,
+) (txhash common.Hash, err error) {
+	if !s.isSporkProposal(proposal) {
+		err = errors.New("Not a spork proposal!")
+		log.Error("Failed", "err", err)
+		return
+	}
+
+	return s.gov.VoteAccept(proposal, mn_owner, password)
+}
+
+func (s *SporkAPI) SporkVoteReject(
+	proposal common.Address,
+	mn_owner common.Address,
+	password *string,
+) (txhash common.Hash, err error) {
+	if !s.isSporkProposal(proposal) {
+		err = errors.New("Not a spork proposal!")
+		log.Error("Failed", "err", err)
+		return
+	}
+
+	return s.gov.VoteReject(proposal, mn_owner, password)
+}
+
+func (s *SporkAPI) SporkPerform(
+	proposal common.Address,
+	payer common.Address,
+	password *string,
+) (txhash common.Hash, err error) {
+	return s.gov.UpgradePerform(
+		energi_params.Energi_SporkRegistry, proposal, payer, password)
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rpc"
+)
+
+type StakingAPI struct {
+	backend Backend
+}
+
+func NewStakingAPI(b Backend) *StakingAPI {
+	return &StakingAPI{b}
+}
+
+type StakingRecord struct {
+	Number           uint64
+	Hash             common.Hash
+	Time             uint64
+	Weight           uint64
+	StakerReward     *hexutil.Big
+	MasternodeReward *hexutil.Big
+	BackboneReward   *hexutil.Big
+	TreasuryReward   *hexutil.Big
+	Estimated        bool
+}
+
+type StakingHistory struct {
+	Address     common.Address
+	FromBlock   uint64
+	ToBlock     uint64
+	TotalReward *hexutil.Big
+	Blocks      []StakingRecord
+}
+
+// blockNumber resolves the block range parameters of history calls.
+func blockNumber(backend Backend, num rpc.BlockNumber) uint64 {
+	if num < 0 {
+		return backend.CurrentBlock().NumberU64()
+	}
+
+	return uint64(num)
+}
+
+func (s *StakingAPI) StakingHistory(
+	ctx context.Context,
+	address common.Address,
+	fromBlock rpc.BlockNumber,
+	toBlock rpc.BlockNumber,
+) (*StakingHistory, error) {
+	from := blockNumber(s.backend, fromBlock)
+	to := blockNumber(s.backend, toBlock)
+
+	if from > to {
+		return nil, errors.New("Invalid block range")
+	}
+
+	records, err := s.backend.StakingHistory(ctx, address, from, to)
+	if err != nil {
+		log.Error("StakingHistory failed", "err", err)
+		return nil, err
+	}
+
+	total := new(big.Int)
+	res := &StakingHistory{
+		Address:     address,
+		FromBlock:   from,
+		ToBlock:     to,
+		TotalReward: (*hexutil.Big)(total),
+		Blocks:      make([]StakingRecord, 0, len(records)),
+	}
+
+	for _, r := range records {
+		total.Add(total, r.StakerReward)
+		res.Blocks = append(res.Blocks, StakingRecord{
+			Number:           r.Number,
+			Hash:             r.Hash,
+			Time:             r.Time,
+			Weight:           r.Weight,
+			StakerReward:     (*hexutil.Big)(r.StakerReward),
+			MasternodeReward: (*hexutil.Big)(r.MasternodeReward),
+			BackboneReward:   (*hexutil.Big)(r.BackboneReward),
+			TreasuryReward:   (*hexutil.Big)(r.TreasuryReward),
+			Estimated:        r.Estimated,
+		})
+	}
+
+	return res, nil
+}
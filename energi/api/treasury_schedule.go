@@ -0,0 +1,297 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/pborman/uuid"
+
+	"energi.world/core/gen3/accounts/abi/bind"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/log"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_consensus "energi.world/core/gen3/energi/consensus"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+const (
+	treasuryScheduleDefault uint64 = 3
+	treasuryScheduleMax     uint64 = 100
+)
+
+//=============================================================================
+// Treasury superblock schedule
+//=============================================================================
+
+type TreasuryPayout struct {
+	Proposal common.Address
+	RefUUID  string
+	Amount   *hexutil.Big
+	Unpaid   *hexutil.Big // left after the payout
+}
+
+type TreasurySuperblock struct {
+	Number  uint64
+	ETA     uint64 // expected block time
+	Reward  *hexutil.Big
+	Balance *hexutil.Big // available for payouts, including the reward
+	Payouts []TreasuryPayout
+}
+
+type TreasurySchedule struct {
+	Height      uint64
+	Cycle       uint64
+	Balance     *hexutil.Big
+	NextBlock   TreasurySuperblock // pays out the current balance
+	Superblocks []TreasurySuperblock
+}
+
+type treasuryProposal struct {
+	address common.Address
+	refUUID string
+	unpaid  *big.Int
+}
+
+// headerReader looks up headers through the backend for time targets.
+type headerReader struct {
+	backend Backend
+}
+
+func (hr *headerReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header, err := hr.backend.HeaderByHash(context.Background(), hash)
+	if err != nil || header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
+// TreasurySchedule returns upcoming superblocks with their expected time and
+// the projected payouts of accepted budget proposals.
+func (g *GovernanceAPI) TreasurySchedule(count *uint64) (*TreasurySchedule, error) {
+	superblocks := treasuryScheduleDefault
+	if count != nil {
+		superblocks = *count
+	}
+	if superblocks == 0 || superblocks > treasuryScheduleMax {
+		return nil, errors.New("Invalid superblock count")
+	}
+
+	treasury, err := energi_abi.NewTreasuryV1Caller(
+		energi_params.Energi_Treasury, g.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	parent := g.backend.CurrentBlock().Header()
+	height := parent.Number.Uint64()
+	engine, _ := g.backend.Engine().(*energi_consensus.Energi)
+
+	call_opts := &bind.CallOpts{
+		BlockNumber: parent.Number,
+		GasLimit:    energi_params.UnlimitedGas,
+	}
+
+	cycle, err := superblockCycle(g.backend, call_opts)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := treasury.Balance(call_opts)
+	if err != nil {
+		log.Error("Failed Balance", "err", err)
+		return nil, err
+	}
+
+	proposals, err := g.treasuryProposals(call_opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TreasurySchedule{
+		Height:  height,
+		Cycle:   cycle,
+		Balance: (*hexutil.Big)(balance),
+	}
+
+	// NOTE: the treasury pays out on every block, the next block goes first
+	//       unless it is a superblock on its own.
+	blocks := make([]TreasurySuperblock, 0, superblocks+1)
+	numbers := make([]uint64, 0, superblocks+1)
+
+	number := (height/res.Cycle + 1) * res.Cycle
+	if number != height+1 {
+		numbers = append(numbers, height+1)
+	}
+	for i := uint64(0); i < superblocks; i++ {
+		numbers = append(numbers, number)
+		number += res.Cycle
+	}
+
+	for _, number := range numbers {
+		reward, err := treasury.GetReward(call_opts, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Error("Failed GetReward", "err", err)
+			return nil, err
+		}
+
+		sb := TreasurySuperblock{
+			Number: number,
+			Reward: (*hexutil.Big)(reward),
+		}
+		if engine != nil {
+			sb.ETA = engine.EstimateBlockTime(&headerReader{g.backend}, parent, number)
+		}
+
+		blocks = append(blocks, sb)
+	}
+
+	projectTreasuryPayouts(balance, blocks, proposals)
+
+	res.NextBlock = blocks[0]
+	res.Superblocks = blocks[len(blocks)-int(superblocks):]
+
+	return res, nil
+}
+
+func superblockCycle(backend Backend, call_opts *bind.CallOpts) (uint64, error) {
+	treasury, err := energi_abi.NewTreasuryV1Caller(
+		energi_params.Energi_Treasury, backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return 0, err
+	}
+
+	cycle, err := treasury.SuperblockCycle(call_opts)
+	if err != nil {
+		log.Error("Failed SuperblockCycle", "err", err)
+		return 0, err
+	}
+	if cycle.Sign() <= 0 || !cycle.IsUint64() {
+		return 0, errors.New("Invalid superblock cycle")
+	}
+
+	return cycle.Uint64(), nil
+}
+
+// treasuryProposals returns the unpaid amounts of accepted budget proposals.
+func (g *GovernanceAPI) treasuryProposals(call_opts *bind.CallOpts) ([]treasuryProposal, error) {
+	treasury, err := energi_abi.NewITreasuryCaller(
+		energi_params.Energi_Treasury, g.backend.(bind.ContractCaller))
+	if err != nil {
+		log.Error("Failed NewITreasuryCaller", "err", err)
+		return nil, err
+	}
+
+	proposals, err := treasury.ListProposals(call_opts)
+	if err != nil {
+		log.Error("Failed ListProposals", "err", err)
+		return nil, err
+	}
+
+	ret := make([]treasuryProposal, 0, len(proposals))
+	for _, p := range proposals {
+		budget_proposal, err := energi_abi.NewIBudgetProposalCaller(
+			p, g.backend.(bind.ContractCaller))
+		if err != nil {
+			log.Error("Failed NewIBudgetProposalCaller", "err", err)
+			return nil, err
+		}
+
+		status, err := budget_proposal.BudgetStatus(call_opts)
+		if err != nil {
+			log.Debug("Failed BudgetStatus", "err", err)
+			continue
+		}
+
+		if !status.IsAccepted || status.Unpaid.Sign() <= 0 {
+			continue
+		}
+
+		ret = append(ret, treasuryProposal{
+			address: p,
+			refUUID: uuid.UUID(common.LeftPadBytes(status.RefUuid.Bytes(), 16)).String(),
+			unpaid:  status.Unpaid,
+		})
+	}
+
+	return ret, nil
+}
+
+// projectTreasuryPayouts replays TreasuryV1.reward() for each given block.
+// Payouts are scaled down by permille, if the balance is not enough to pay
+// all accepted proposals in full. NOTE: the rounding leftover is paid on the
+// following blocks, it is attributed to the next given block instead.
+func projectTreasuryPayouts(
+	balance *big.Int,
+	superblocks []TreasurySuperblock,
+	proposals []treasuryProposal,
+) {
+	balance = new(big.Int).Set(balance)
+	unpaid := make([]*big.Int, len(proposals))
+	for i, p := range proposals {
+		unpaid[i] = new(big.Int).Set(p.unpaid)
+	}
+
+	for i := range superblocks {
+		sb := &superblocks[i]
+
+		balance.Add(balance, sb.Reward.ToInt())
+		sb.Balance = (*hexutil.Big)(new(big.Int).Set(balance))
+		sb.Payouts = []TreasuryPayout{}
+
+		unpaid_total := new(big.Int)
+		for _, u := range unpaid {
+			unpaid_total.Add(unpaid_total, u)
+		}
+
+		if balance.Sign() <= 0 || unpaid_total.Sign() <= 0 {
+			continue
+		}
+
+		permille := big.NewInt(1000)
+		if unpaid_total.Cmp(balance) > 0 {
+			permille.Mul(balance, permille)
+			permille.Div(permille, unpaid_total)
+		}
+
+		for j, p := range proposals {
+			if unpaid[j].Sign() <= 0 {
+				continue
+			}
+
+			amount := new(big.Int).Mul(unpaid[j], permille)
+			amount.Div(amount, big.NewInt(1000))
+
+			balance.Sub(balance, amount)
+			unpaid[j].Sub(unpaid[j], amount)
+
+			sb.Payouts = append(sb.Payouts, TreasuryPayout{
+				Proposal: p.address,
+				RefUUID:  p.refUUID,
+				Amount:   (*hexutil.Big)(amount),
+				Unpaid:   (*hexutil.Big)(new(big.Int).Set(unpaid[j])),
+			})
+		}
+	}
+}
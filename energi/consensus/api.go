@@ -18,9 +18,13 @@ package consensus
 
 import (
 	"bytes"
+	"math"
+	"math/big"
 	"sort"
 
 	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/log"
 )
 
@@ -93,6 +97,171 @@ func (a *EngineAPI) StakingStatus() *StakingStatusInfo {
 	return res
 }
 
+type StakingPredictionInfo struct {
+	Hash              common.Hash
+	Height            uint64
+	Difficulty        *hexutil.Big
+	AverageDifficulty *hexutil.Big
+	AverageBlockTime  uint64
+	AverageUsedWeight uint64
+	NetworkWeight     uint64
+	Accounts          []StakingPrediction
+}
+
+type StakingPrediction struct {
+	Account            common.Address
+	Weight             uint64
+	BalanceWeight      uint64
+	LockedWeight       uint64
+	MaturityLocked     bool
+	UnlockTime         uint64
+	ProbabilityPerHour float64
+	ExpectedTime       uint64
+}
+
+type networkStakeEstimate struct {
+	avgDifficulty *big.Int
+	avgBlockTime  uint64
+	avgUsedWeight uint64
+	weight        uint64
+}
+
+// Estimate network-wide stake weight from the recent headers.
+//
+// Every second each account gets a chance of weight/difficulty to stake
+// a block. Therefore, the total weight W of the network is roughly the sum
+// of difficulties divided by the time it took to produce the blocks.
+func estimateNetworkStake(
+	chain ChainReader,
+	head *types.Header,
+	samples uint64,
+) *networkStakeEstimate {
+	res := &networkStakeEstimate{
+		avgDifficulty: new(big.Int),
+	}
+
+	total_diff := new(big.Int)
+	total_used := uint64(0)
+	count := uint64(0)
+	curr := head
+
+	for ; count < samples; count++ {
+		if curr.Number.Cmp(common.Big0) == 0 {
+			break
+		}
+
+		parent := chain.GetHeader(curr.ParentHash, curr.Number.Uint64()-1)
+		if parent == nil {
+			break
+		}
+
+		total_diff.Add(total_diff, curr.Difficulty)
+		total_used += curr.Nonce.Uint64()
+		curr = parent
+	}
+
+	if count == 0 || head.Time <= curr.Time {
+		return res
+	}
+
+	timespan := head.Time - curr.Time
+
+	res.avgDifficulty.Div(total_diff, new(big.Int).SetUint64(count))
+	res.avgBlockTime = timespan / count
+	res.avgUsedWeight = total_used / count
+	res.weight = new(big.Int).Div(
+		total_diff, new(big.Int).SetUint64(timespan)).Uint64()
+
+	return res
+}
+
+// Probability to stake within a single second, adjusted for POS-22 which
+// does not allow to use more weight than available.
+func stakeProbability(weight uint64, difficulty *big.Int) float64 {
+	if weight == 0 || difficulty.Sign() <= 0 {
+		return 0
+	}
+
+	diff, _ := new(big.Float).SetInt(difficulty).Float64()
+	p := float64(weight) / diff
+
+	if p > 1 {
+		p = 1
+	}
+
+	return p
+}
+
+func (a *EngineAPI) StakingPrediction() *StakingPredictionInfo {
+	res := &StakingPredictionInfo{}
+
+	chain := a.chain
+	engine := a.engine
+
+	parent := chain.CurrentHeader()
+	res.Hash = parent.Hash()
+	res.Height = parent.Number.Uint64()
+
+	now := engine.now()
+	time_target := engine.calcTimeTarget(chain, parent)
+	blockTime := now
+	if blockTime < time_target.min_time {
+		blockTime = time_target.min_time
+	}
+	difficulty := engine.calcPoSDifficulty(chain, blockTime, parent, time_target)
+	res.Difficulty = (*hexutil.Big)(difficulty)
+
+	network := estimateNetworkStake(chain, parent, AverageTimeBlocks)
+	res.AverageDifficulty = (*hexutil.Big)(network.avgDifficulty)
+	res.AverageBlockTime = network.avgBlockTime
+	res.AverageUsedWeight = network.avgUsedWeight
+	res.NetworkWeight = network.weight
+
+	// Use steady state difficulty, if possible
+	if network.avgDifficulty.Sign() > 0 {
+		difficulty = network.avgDifficulty
+	}
+
+	raw_accounts := engine.accountsFn()
+	sort.Slice(raw_accounts, func(a, b int) bool {
+		return bytes.Compare(raw_accounts[a][:], raw_accounts[b][:]) < 0
+	})
+	res.Accounts = make([]StakingPrediction, 0, len(raw_accounts))
+
+	for _, acct := range raw_accounts {
+		info, err := engine.lookupStakeInfo(chain, now, parent, acct)
+		if err != nil {
+			log.Warn("PoS weight lookup failed", "err", err)
+			continue
+		}
+
+		pred := StakingPrediction{
+			Account:       acct,
+			Weight:        info.weight,
+			BalanceWeight: info.balanceWeight,
+			LockedWeight:  info.balanceWeight - info.weight,
+		}
+
+		if info.lockedUntil > now {
+			pred.MaturityLocked = info.weight == 0
+			pred.UnlockTime = info.lockedUntil
+		}
+
+		if p := stakeProbability(info.weight, difficulty); p > 0 {
+			pred.ProbabilityPerHour = -math.Expm1(3600 * math.Log1p(-p))
+			pred.ExpectedTime = uint64(math.Ceil(1 / p))
+		} else if pred.MaturityLocked {
+			if p := stakeProbability(info.balanceWeight, difficulty); p > 0 {
+				pred.ExpectedTime = info.lockedUntil - now + uint64(math.Ceil(1/p))
+			}
+		}
+
+		res.Accounts = append(res.Accounts, pred)
+	}
+
+	return res
+}
+
 func (a *EngineAPI) SetNonceCap(nonce *uint64) (oldNonce uint64) {
 	oldNonce = a.engine.GetMinerNonceCap()
 	if nonce == nil {
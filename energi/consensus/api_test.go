@@ -0,0 +1,77 @@
+// Copyright 2019 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateNetworkStake(t *testing.T) {
+	t.Parallel()
+
+	fakeChain := new(mockChainReader)
+	fakeChain.headers = make(map[common.Hash]*types.Header)
+
+	parent := &types.Header{
+		Number:     common.Big0,
+		Time:       1000,
+		Difficulty: big.NewInt(1),
+	}
+	fakeChain.headers[parent.Hash()] = parent
+
+	for i := 1; i <= 10; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(int64(i)),
+			Time:       parent.Time + 60,
+			Difficulty: big.NewInt(6000),
+			Nonce:      types.EncodeNonce(uint64(i)),
+		}
+		fakeChain.headers[header.Hash()] = header
+		parent = header
+	}
+
+	est := estimateNetworkStake(fakeChain, parent, 5)
+	assert.Equal(t, uint64(60), est.avgBlockTime)
+	assert.Equal(t, uint64(100), est.weight)
+	assert.Equal(t, uint64(8), est.avgUsedWeight)
+	assert.Equal(t, big.NewInt(6000), est.avgDifficulty)
+
+	// Must stop at genesis
+	est = estimateNetworkStake(fakeChain, parent, 100)
+	assert.Equal(t, uint64(60), est.avgBlockTime)
+	assert.Equal(t, uint64(100), est.weight)
+
+	// No history
+	est = estimateNetworkStake(fakeChain, fakeChain.headers[parent.ParentHash], 0)
+	assert.Equal(t, uint64(0), est.weight)
+	assert.Equal(t, 0, est.avgDifficulty.Sign())
+}
+
+func TestStakeProbability(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, float64(0), stakeProbability(0, big.NewInt(100)))
+	assert.Equal(t, float64(0), stakeProbability(10, big.NewInt(0)))
+	assert.Equal(t, 0.1, stakeProbability(10, big.NewInt(100)))
+	assert.Equal(t, float64(1), stakeProbability(1000, big.NewInt(100)))
+}
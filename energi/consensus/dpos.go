@@ -0,0 +1,106 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"errors"
+
+	"energi.world/core/gen3/common"
+	eth_consensus "energi.world/core/gen3/consensus"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/log"
+)
+
+var (
+	errNotDelegatedPoS = errors.New("not a delegated PoS contract")
+)
+
+// DPoSSigner returns the address allowed to stake on behalf of the contract
+// on top of the given block.
+func (e *Energi) DPoSSigner(
+	chain ChainReader,
+	header *types.Header,
+	contract common.Address,
+) (common.Address, error) {
+	blockst := chain.CalculateBlockState(header.Hash(), header.Number.Uint64())
+	if blockst == nil {
+		log.Warn("DPoS state root failure", "header", header.Hash())
+		return common.Address{}, eth_consensus.ErrMissingState
+	}
+
+	if blockst.GetCodeSize(contract) == 0 {
+		return common.Address{}, errNotDelegatedPoS
+	}
+
+	return e.dposSigner(chain, header, blockst, contract)
+}
+
+// StakeWeight returns the stake weight of the address available for a block
+// on top of the parent.
+func (e *Energi) StakeWeight(
+	chain ChainReader,
+	parent *types.Header,
+	addr common.Address,
+) (uint64, error) {
+	return e.lookupStakeWeight(chain, e.now(), parent, addr)
+}
+
+// POS-5: Delegated PoS
+func (e *Energi) dposSigner(
+	chain ChainReader,
+	parent *types.Header,
+	blockst *state.StateDB,
+	contract common.Address,
+) (signer common.Address, err error) {
+	signerData, err := e.dposAbi.Pack("signerAddress")
+	if err != nil {
+		log.Error("Fail to prepare signerAddress() call", "err", err)
+		return signer, err
+	}
+
+	msg := types.NewMessage(
+		e.systemFaucet,
+		&contract,
+		0,
+		common.Big0,
+		e.callGas,
+		common.Big0,
+		signerData,
+		false,
+	)
+
+	rev_id := blockst.Snapshot()
+	evm := e.createEVM(msg, chain, parent, blockst)
+	gp := core.GasPool(e.callGas)
+	output, _, _, err := core.ApplyMessage(evm, msg, &gp)
+	blockst.RevertToSnapshot(rev_id)
+	if err != nil {
+		log.Trace("Fail to get signerAddress()", "err", err)
+		return signer, err
+	}
+
+	//
+	err = e.dposAbi.Unpack(&signer, "signerAddress", output)
+	if err != nil {
+		log.Error("Failed to unpack signerAddress() call", "err", err)
+		return signer, err
+	}
+
+	return signer, nil
+}
@@ -57,8 +57,15 @@ var (
 )
 
 type ChainReader = eth_consensus.ChainReader
+
+// HeaderReader is the part of ChainReader required for block time targets.
+type HeaderReader interface {
+	GetHeader(hash common.Hash, number uint64) *types.Header
+}
+
 type AccountsFn func() []common.Address
 type SignerFn func(common.Address, []byte) ([]byte, error)
+type HeaderSignerFn func(common.Address, *types.Header) ([]byte, error)
 type PeerCountFn func() int
 type IsMiningFn func() bool
 type DiffFn func(ChainReader, uint64, *types.Header, *timeTarget) *big.Int
@@ -81,6 +88,7 @@ type Energi struct {
 	callGas      uint64
 	unlimitedGas uint64
 	signerFn     SignerFn
+	headerSigner HeaderSignerFn
 	accountsFn   AccountsFn
 	peerCountFn  PeerCountFn
 	isMiningFn   IsMiningFn
@@ -90,6 +98,7 @@ type Energi struct {
 	knownStakes  KnownStakes
 	nextKSPurge  uint64
 	txhashMap    *lru.Cache
+	stakeCache   *stakeCache
 }
 
 func New(config *params.EnergiConfig, db ethdb.Database) *Energi {
@@ -152,6 +161,7 @@ func New(config *params.EnergiConfig, db ethdb.Database) *Energi {
 		now:          func() uint64 { return uint64(time.Now().Unix()) },
 		nextKSPurge:  0,
 		txhashMap:    txhashMap,
+		stakeCache:   newStakeCache(),
 
 		accountsFn:  func() []common.Address { return nil },
 		peerCountFn: func() int { return 0 },
@@ -167,8 +177,8 @@ func (e *Energi) createEVM(
 ) *vm.EVM {
 	vmc := &vm.Config{}
 
-	if bc, ok := chain.(*core.BlockChain); ok {
-		vmc = bc.GetVMConfig()
+	if vcg, ok := chain.(vmConfigGetter); ok {
+		vmc = vcg.GetVMConfig()
 	}
 
 	// Only From() is used by fact
@@ -359,6 +369,12 @@ func (e *Energi) VerifySeal(chain ChainReader, header *types.Header) error {
 		return errBlacklistedCoinbase
 	}
 
+	// On-demand state of light clients may fail to get retrieved
+	if err := blockst.Error(); err != nil {
+		log.Warn("PoS state retrieval failure", "header", header.ParentHash, "err", err)
+		return err
+	}
+
 	// Retrieve the signature from the header extra-data
 	if len(header.Signature) != sealLen {
 		return errMissingSig
@@ -392,38 +408,8 @@ func (e *Energi) VerifySeal(chain ChainReader, header *types.Header) error {
 		}
 
 		if blockst.GetCodeSize(header.Coinbase) > 0 {
-			signerData, err := e.dposAbi.Pack("signerAddress")
-			if err != nil {
-				log.Error("Fail to prepare signerAddress() call", "err", err)
-				return err
-			}
-
-			msg := types.NewMessage(
-				e.systemFaucet,
-				&header.Coinbase,
-				0,
-				common.Big0,
-				e.callGas,
-				common.Big0,
-				signerData,
-				false,
-			)
-
-			rev_id := blockst.Snapshot()
-			evm := e.createEVM(msg, chain, parent, blockst)
-			gp := core.GasPool(e.callGas)
-			output, _, _, err := core.ApplyMessage(evm, msg, &gp)
-			blockst.RevertToSnapshot(rev_id)
-			if err != nil {
-				log.Trace("Fail to get signerAddress()", "err", err)
-				return err
-			}
-
-			//
-			signer := common.Address{}
-			err = e.dposAbi.Unpack(&signer, "signerAddress", output)
+			signer, err := e.dposSigner(chain, parent, blockst, header.Coinbase)
 			if err != nil {
-				log.Error("Failed to unpack signerAddress() call", "err", err)
 				return err
 			}
 
@@ -544,26 +530,140 @@ func (e *Energi) govFinalize(
 	txs types.Transactions,
 	receipts types.Receipts,
 ) (types.Transactions, types.Receipts, error) {
-	err := e.processConsensusGasLimits(chain, header, state)
+	return e.finalizeSteps(chain, header, state, txs, receipts, nil)
+}
+
+// Labels of the finalize steps in the execution order
+const (
+	FinalizeStepGasLimits  = "processConsensusGasLimits"
+	FinalizeStepRewards    = "processBlockRewards"
+	FinalizeStepMasternode = "processMasternodes"
+	FinalizeStepBlacklists = "processBlacklists"
+	FinalizeStepDrainable  = "processDrainable"
+	FinalizeStepMigration  = "finalizeMigration"
+)
+
+// StepConfigFn returns the VM config for an EVM call of the labelled finalize
+// step. It gets called for every EVM created by the step.
+type StepConfigFn func(step string) *vm.Config
+
+// vmConfigGetter is implemented by chains with a custom VM config.
+type vmConfigGetter interface {
+	GetVMConfig() *vm.Config
+}
+
+// stepChain runs EVM calls of a single finalize step with the VM config of
+// the step.
+type stepChain struct {
+	ChainReader
+	step     string
+	configFn StepConfigFn
+}
+
+func (sc *stepChain) GetVMConfig() *vm.Config {
+	return sc.configFn(sc.step)
+}
+
+// Engine is required for the EVM context.
+func (sc *stepChain) Engine() eth_consensus.Engine {
+	if cc, ok := sc.ChainReader.(core.ChainContext); ok {
+		return cc.Engine()
+	}
+	return nil
+}
+
+// finalizeSteps runs all the finalize steps. If configFn is set, EVM calls
+// of each step use the VM config it returns, e.g. to trace them.
+func (e *Energi) finalizeSteps(
+	chain ChainReader,
+	header *types.Header,
+	state *state.StateDB,
+	txs types.Transactions,
+	receipts types.Receipts,
+	configFn StepConfigFn,
+) (types.Transactions, types.Receipts, error) {
+	stepChainFor := func(step string) ChainReader {
+		if configFn == nil {
+			return chain
+		}
+		return &stepChain{chain, step, configFn}
+	}
+
+	err := e.processConsensusGasLimits(stepChainFor(FinalizeStepGasLimits), header, state)
 	if err == nil {
-		txs, receipts, err = e.processBlockRewards(chain, header, state, txs, receipts)
+		txs, receipts, err = e.processBlockRewards(stepChainFor(FinalizeStepRewards), header, state, txs, receipts)
 	}
 	if err == nil {
-		err = e.processMasternodes(chain, header, state)
+		err = e.processMasternodes(stepChainFor(FinalizeStepMasternode), header, state)
 	}
 	if err == nil {
-		err = e.processBlacklists(chain, header, state)
+		err = e.processBlacklists(stepChainFor(FinalizeStepBlacklists), header, state)
 	}
 	if err == nil {
-		txs, receipts, err = e.processDrainable(chain, header, state, txs, receipts)
+		txs, receipts, err = e.processDrainable(stepChainFor(FinalizeStepDrainable), header, state, txs, receipts)
 	}
 	if err == nil {
-		err = e.finalizeMigration(chain, header, state, txs)
+		err = e.finalizeMigration(stepChainFor(FinalizeStepMigration), header, state, txs)
 	}
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	return txs, receipts, err
 }
 
+// TraceFinalize runs the finalize steps of the block on top of the state
+// after all its non-consensus transactions. EVM calls of the steps use the VM
+// config returned by configFn, so they can get traced.
+//
+// The header gets updated the same way as by Finalize. The consensus
+// transactions recreated by the steps are returned.
+func (e *Energi) TraceFinalize(
+	chain ChainReader,
+	header *types.Header,
+	state *state.StateDB,
+	txs types.Transactions,
+	configFn StepConfigFn,
+) (types.Transactions, error) {
+	if (header.Coinbase == common.Address{}) {
+		return nil, nil
+	}
+
+	ntxs, _, err := e.traceEngine().finalizeSteps(chain, header, state, txs, nil, configFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return ntxs[len(txs):], nil
+}
+
+// traceEngine returns an engine to run the finalize steps for tracing. It has
+// its own consensus gas limits, so tracing does not race with the block
+// processing, which updates them on the shared engine.
+func (e *Energi) traceEngine() *Energi {
+	return &Energi{
+		config:       e.config,
+		db:           e.db,
+		rewardAbi:    e.rewardAbi,
+		dposAbi:      e.dposAbi,
+		blacklistAbi: e.blacklistAbi,
+		sporkAbi:     e.sporkAbi,
+		mnregAbi:     e.mnregAbi,
+		treasuryAbi:  e.treasuryAbi,
+		systemFaucet: e.systemFaucet,
+		xferGas:      0,
+		callGas:      30000,
+		unlimitedGas: e.unlimitedGas,
+		signerFn:     e.signerFn,
+		diffFn:       e.diffFn,
+		testing:      e.testing,
+		now:          e.now,
+		txhashMap:    e.txhashMap,
+		stakeCache:   e.stakeCache,
+
+		accountsFn:  e.accountsFn,
+		peerCountFn: e.peerCountFn,
+		isMiningFn:  e.isMiningFn,
+	}
+}
+
 // Seal generates a new sealing request for the given input block and pushes
 // the result into the given channel.
 //
@@ -607,10 +707,14 @@ func (e *Energi) Seal(
 			header = result.Block.Header()
 		}
 
-		sighash := e.SignatureHash(header)
-		log.Trace("PoS seal hash", "sighash", sighash)
+		if e.headerSigner != nil {
+			header.Signature, err = e.headerSigner(header.Coinbase, header)
+		} else {
+			sighash := e.SignatureHash(header)
+			log.Trace("PoS seal hash", "sighash", sighash)
 
-		header.Signature, err = e.signerFn(header.Coinbase, sighash.Bytes())
+			header.Signature, err = e.signerFn(header.Coinbase, sighash.Bytes())
+		}
 		if err != nil {
 			log.Error("PoS miner error", "err", err)
 			return
@@ -708,7 +812,13 @@ func (e *Energi) SealHash(header *types.Header) (hash common.Hash) {
 	return hash
 }
 
-func (e *Energi) SignatureHash(header *types.Header) (hash common.Hash) {
+func (e *Energi) SignatureHash(header *types.Header) common.Hash {
+	return SignatureHash(header)
+}
+
+// SignatureHash returns the hash which is signed by the PoS block producer.
+// External signers use it to verify what they are asked to sign.
+func SignatureHash(header *types.Header) (hash common.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
 
 	rlp.Encode(hasher, []interface{}{
@@ -755,6 +865,13 @@ func (e *Energi) SetMinerCB(
 	e.isMiningFn = isMiningFn
 }
 
+// SetHeaderSignerCB sets a callback which signs the whole block header instead
+// of just its signature hash. It is required for signers which refuse to sign
+// arbitrary hashes.
+func (e *Energi) SetHeaderSignerCB(headerSigner HeaderSignerFn) {
+	e.headerSigner = headerSigner
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have.
 func (e *Energi) CalcDifficulty(chain ChainReader, time uint64, parent *types.Header) *big.Int {
@@ -66,7 +66,7 @@ type timeTarget struct {
  * POS-12: Block interval enforcement
  */
 func (e *Energi) calcTimeTarget(
-	chain ChainReader,
+	chain HeaderReader,
 	parent *types.Header,
 ) *timeTarget {
 	ret := &timeTarget{}
@@ -115,6 +115,41 @@ func (e *Energi) calcTimeTarget(
 	return ret
 }
 
+// EstimateBlockTime returns the expected time of a future block on top of
+// the parent. The next block is expected at its period target within
+// POS-11 limits and every following one after TargetBlockGap.
+func (e *Energi) EstimateBlockTime(
+	chain HeaderReader,
+	parent *types.Header,
+	number uint64,
+) uint64 {
+	parent_number := parent.Number.Uint64()
+	if number <= parent_number {
+		return 0
+	}
+
+	time_target := e.calcTimeTarget(chain, parent)
+
+	max_next := time_target.block_target
+	if max_next < time_target.min_time {
+		max_next = time_target.min_time
+	}
+
+	next := time_target.period_target
+	if next < time_target.min_time {
+		next = time_target.min_time
+	} else if next > max_next {
+		next = max_next
+	}
+
+	// The block is already overdue
+	if now := e.now(); next < now {
+		next = now
+	}
+
+	return next + (number-parent_number-1)*TargetBlockGap
+}
+
 func (e *Energi) enforceTime(
 	header *types.Header,
 	time_target *timeTarget,
@@ -175,6 +210,12 @@ func (e *Energi) calcPoSModifier(
 		oldest = header
 		num--
 		header = chain.GetHeader(header.ParentHash, num)
+
+		// NOTE: a light chain may miss old headers, the modifier check fails then
+		if header == nil {
+			log.Debug("PoS modifier missing ancestor", "block", parent_height+1, "number", num)
+			break
+		}
 	}
 
 	// Create Stake Modifier
@@ -365,9 +406,7 @@ func (e *Energi) verifyPoSHash(
  * POS-4: Stake amount
  * POS-22: Partial stake amount
  *
- * This is a basic helper for stake amount calculation.
- * There are ways to optimize it for high load, but we need something
- * to start with.
+ * Balance weights of blocks get cached per address, see stakeCache.
  */
 func (e *Energi) lookupStakeWeight(
 	chain ChainReader,
@@ -375,6 +414,46 @@ func (e *Energi) lookupStakeWeight(
 	till *types.Header,
 	addr common.Address,
 ) (weight uint64, err error) {
+	info, err := e.lookupStakeInfo(chain, now, till, addr)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.weight, nil
+}
+
+type stakeInfo struct {
+	// Stake weight available for staking
+	weight uint64
+	// Minimal balance weight during the maturity period
+	balanceWeight uint64
+	// Weight already used for staking during the maturity period
+	stakedWeight uint64
+	// Time when the most recent stake leaves the maturity period
+	lockedUntil uint64
+}
+
+func (e *Energi) lookupStakeInfo(
+	chain ChainReader,
+	now uint64,
+	till *types.Header,
+	addr common.Address,
+) (info stakeInfo, err error) {
+	if e.stakeCache != nil {
+		return e.stakeCache.lookup(chain, now, till, addr)
+	}
+
+	return e.scanStakeInfo(chain, now, till, addr)
+}
+
+// scanStakeInfo walks the chain back through the maturity period calculating
+// the state of every block.
+func (e *Energi) scanStakeInfo(
+	chain ChainReader,
+	now uint64,
+	till *types.Header,
+	addr common.Address,
+) (info stakeInfo, err error) {
 	var since uint64
 
 	if now > MaturityPeriod {
@@ -384,7 +463,7 @@ func (e *Energi) lookupStakeWeight(
 	}
 
 	// NOTE: Do not set to high initial value due to defensive coding approach!
-	weight = 0
+	weight := uint64(0)
 	total_staked := uint64(0)
 	first_run := true
 	blockst := chain.CalculateBlockState(till.Hash(), till.Number.Uint64())
@@ -393,7 +472,7 @@ func (e *Energi) lookupStakeWeight(
 	for (till.Time > since) || first_run {
 		if blockst == nil {
 			log.Warn("PoS state root failure", "header", till.Hash())
-			return 0, eth_consensus.ErrMissingState
+			return info, eth_consensus.ErrMissingState
 		}
 
 		weight_at_block := new(big.Int).Div(
@@ -401,6 +480,12 @@ func (e *Energi) lookupStakeWeight(
 			minStake,
 		).Uint64()
 
+		// On-demand state of light clients may fail to get retrieved
+		if err = blockst.Error(); err != nil {
+			log.Warn("PoS state retrieval failure", "header", till.Hash(), "err", err)
+			return info, err
+		}
+
 		if first_run {
 			weight = weight_at_block
 			first_run = false
@@ -419,6 +504,10 @@ func (e *Energi) lookupStakeWeight(
 		// POS-22: partial stake amount
 		if till.Coinbase == addr {
 			total_staked += till.Nonce.Uint64()
+
+			if info.lockedUntil == 0 {
+				info.lockedUntil = till.Time + MaturityPeriod
+			}
 		}
 
 		curr := till
@@ -431,22 +520,25 @@ func (e *Energi) lookupStakeWeight(
 			}
 
 			log.Error("PoS state missing parent", "parent", curr.ParentHash)
-			return 0, eth_consensus.ErrUnknownAncestor
+			return info, eth_consensus.ErrUnknownAncestor
 		}
 
 		blockst = chain.CalculateBlockState(curr.ParentHash, parent_number)
 	}
 
+	info.balanceWeight = weight
+	info.stakedWeight = total_staked
+
 	if weight < total_staked {
 		log.Debug("Nothing to stake",
 			"addr", addr, "since", since, "weight", weight, "total_staked", total_staked)
-		weight = 0
+		info.weight = 0
 	} else {
-		weight -= total_staked
+		info.weight = weight - total_staked
 	}
 
-	//log.Trace("PoS stake weight", "addr", addr, "weight", weight)
-	return weight, nil
+	//log.Trace("PoS stake weight", "addr", addr, "weight", info.weight)
+	return info, nil
 }
 
 /**
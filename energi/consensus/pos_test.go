@@ -119,6 +119,7 @@ func TestPoSChain(t *testing.T) {
 			return crypto.Sign(hash, signers[addr])
 		},
 		func() int { return 1 },
+		func() bool { return true },
 	)
 
 	chainConfig := *params.EnergiTestnetChainConfig
@@ -246,6 +247,21 @@ func TestPoSChain(t *testing.T) {
 				append(tmptxs[:len(tmptxs)-1], tmptxs[len(tmptxs)-1].WithConsensusSender(common.Address{})),
 				nil, receipts)
 			assert.Equal(t, eth_consensus.ErrInvalidConsensusTx, err)
+
+			steps := []string{}
+			ctxs, err := engine.TraceFinalize(
+				chain, &tmpheader, blstate.Copy(), tmptxs[:0],
+				func(step string) *vm.Config {
+					if len(steps) == 0 || steps[len(steps)-1] != step {
+						steps = append(steps, step)
+					}
+					return &vm.Config{}
+				})
+			assert.Empty(t, err)
+			assert.Equal(t, len(tmptxs), len(ctxs))
+			assert.Contains(t, steps, FinalizeStepRewards)
+			assert.Contains(t, steps, FinalizeStepBlacklists)
+			assert.Contains(t, steps, FinalizeStepDrainable)
 		}
 
 		// Time tests
@@ -281,6 +297,83 @@ func TestPoSChain(t *testing.T) {
 	}
 }
 
+func TestTraceFinalizeConcurrent(t *testing.T) {
+	t.Parallel()
+
+	addresses, _, alloc, migrationSigner := generateAddresses(2)
+
+	testdb := ethdb.NewMemDatabase()
+	engine := New(&params.EnergiConfig{MigrationSigner: migrationSigner}, testdb)
+	engine.testing = true
+
+	chainConfig := *params.EnergiTestnetChainConfig
+	chainConfig.Energi = &params.EnergiConfig{
+		MigrationSigner: migrationSigner,
+	}
+
+	gspec := &core.Genesis{
+		Config:     &chainConfig,
+		GasLimit:   8000000,
+		Timestamp:  1000,
+		Difficulty: big.NewInt(1),
+		Coinbase:   energi_params.Energi_Treasury,
+		Alloc:      alloc,
+		Xfers:      core.DeployEnergiGovernance(&chainConfig),
+	}
+	genesis := gspec.MustCommit(testdb)
+
+	chain, err := core.NewBlockChain(testdb, nil, &chainConfig, engine, vm.Config{}, nil)
+	assert.Empty(t, err)
+	defer chain.Stop()
+
+	// NOTE: block 1 is the migration one, finalize steps do not need a parent
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Coinbase:   addresses[0],
+		GasLimit:   genesis.GasLimit(),
+		Number:     common.Big2,
+		Time:       genesis.Time() + 30,
+		Difficulty: common.Big1,
+	}
+	trace := func() (types.Transactions, error) {
+		blstate, err := chain.StateAt(genesis.Root())
+		if err != nil {
+			return nil, err
+		}
+		return engine.TraceFinalize(
+			chain, types.CopyHeader(header), blstate, nil,
+			func(step string) *vm.Config { return &vm.Config{} })
+	}
+
+	ctxs, err := trace()
+	assert.Empty(t, err)
+	assert.NotEmpty(t, ctxs)
+
+	// Block processing updates the consensus gas limits of the engine
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			blstate, err := chain.StateAt(genesis.Root())
+			if err == nil {
+				_, _, err = engine.Finalize(
+					chain, types.CopyHeader(header), blstate, ctxs, nil, nil)
+			}
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for i := 0; i < 10; i++ {
+		tctxs, err := trace()
+		assert.Empty(t, err)
+		assert.Equal(t, len(ctxs), len(tctxs))
+	}
+	assert.Empty(t, <-done)
+}
+
 func TestPoSDiffV1(t *testing.T) {
 	t.Parallel()
 	log.Root().SetHandler(log.StdoutHandler)
@@ -384,6 +477,58 @@ func TestPoSDiffV1(t *testing.T) {
 	}
 }
 
+func TestEstimateBlockTime(t *testing.T) {
+	t.Parallel()
+	log.Root().SetHandler(log.StdoutHandler)
+
+	now := uint64(0)
+	engine := &Energi{now: func() uint64 { return now }}
+
+	chain := &mockChainReader{
+		headers: make(map[common.Hash]*types.Header),
+	}
+	genHeaders := func(count uint64, gap uint64) *types.Header {
+		var parent *types.Header
+		for i := uint64(0); i < count; i++ {
+			header := &types.Header{
+				Number: new(big.Int).SetUint64(i),
+				Time:   1000 + i*gap,
+			}
+			if parent != nil {
+				header.ParentHash = parent.Hash()
+			}
+			chain.headers[header.Hash()] = header
+			parent = header
+		}
+		return parent
+	}
+
+	// Short chain uses the block target
+	parent := genHeaders(10, TargetBlockGap)
+	now = parent.Time
+	assert.Equal(t, uint64(0), engine.EstimateBlockTime(chain, parent, 9))
+	assert.Equal(t, parent.Time+TargetBlockGap, engine.EstimateBlockTime(chain, parent, 10))
+	assert.Equal(t, parent.Time+3*TargetBlockGap, engine.EstimateBlockTime(chain, parent, 12))
+
+	// Overdue block is expected now
+	now = parent.Time + 10*TargetBlockGap
+	assert.Equal(t, now, engine.EstimateBlockTime(chain, parent, 10))
+	assert.Equal(t, now+TargetBlockGap, engine.EstimateBlockTime(chain, parent, 11))
+
+	// Fast chain gets delayed by the period target
+	parent = genHeaders(AverageTimeBlocks+10, MinBlockGap)
+	now = parent.Time
+	past := parent.Time - (AverageTimeBlocks-1)*MinBlockGap
+	expected := past + TargetPeriodGap - MinBlockGap
+	assert.True(t, expected > parent.Time+TargetBlockGap)
+	assert.Equal(t, expected, engine.EstimateBlockTime(chain, parent, parent.Number.Uint64()+1))
+
+	// Slow chain catches up
+	parent = genHeaders(AverageTimeBlocks+10, 2*TargetBlockGap)
+	now = parent.Time
+	assert.Equal(t, parent.Time+MinBlockGap, engine.EstimateBlockTime(chain, parent, parent.Number.Uint64()+1))
+}
+
 func TestStakeWeightLookup(t *testing.T) {
 	t.Parallel()
 	log.Root().SetHandler(log.StdoutHandler)
@@ -448,7 +593,7 @@ func TestStakeWeightLookup(t *testing.T) {
 		parent.Nonce = types.BlockNonce{255, 255, 255, 255, 255, 255, 255, 255}
 		weight, err = engine.lookupStakeWeight(fakeChain, header.Time, parent, header.Coinbase)
 		assert.Empty(t, err)
-		assert.Equal(t, weight, 0)
+		assert.Equal(t, weight, uint64(0))
 
 		parent.Coinbase = parentCoinbase
 		parent.Nonce = parentNonce
@@ -489,6 +634,7 @@ func TestPoSMine(t *testing.T) {
 			return crypto.Sign(hash, signers[addr])
 		},
 		func() int { return 1 },
+		func() bool { return true },
 	)
 
 	chainConfig := *params.EnergiTestnetChainConfig
@@ -681,3 +827,167 @@ func TestVerifyPoSHash(t *testing.T) {
 		parent = header
 	}
 }
+
+type stakeChainReader struct {
+	mockChainReader
+	states map[common.Hash]*state.StateDB
+}
+
+func (cr *stakeChainReader) CalculateBlockState(hash common.Hash, number uint64) *state.StateDB {
+	return cr.states[hash]
+}
+
+// extend adds blocks with changing balances and stakes of the addresses.
+func (cr *stakeChainReader) extend(
+	parent *types.Header,
+	addresses []common.Address,
+	count int,
+	seed int64,
+) *types.Header {
+	stateCache := state.NewDatabase(ethdb.NewMemDatabase())
+
+	for i := 0; i < count; i++ {
+		number := new(big.Int).Add(parent.Number, common.Big1)
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Coinbase:   addresses[(int(seed)+i)%len(addresses)],
+			Number:     number,
+			Time:       parent.Time + MinBlockGap,
+			Nonce:      types.EncodeNonce(uint64(seed+int64(i)) % 3),
+		}
+
+		stateDB, _ := state.New(common.Hash{}, stateCache)
+		for j, addr := range addresses {
+			// Only the first address runs out of stake at times
+			weight := (seed + int64(i*(j+1))) % 17
+			if j > 0 {
+				weight++
+			}
+			stateDB.SetBalance(addr, new(big.Int).Mul(big.NewInt(weight), minStake))
+		}
+
+		cr.headers[header.Hash()] = header
+		cr.states[header.Hash()] = stateDB
+		parent = header
+	}
+
+	cr.current = parent
+	return parent
+}
+
+func newStakeChainReader(addresses []common.Address, count int) *stakeChainReader {
+	genesis := &types.Header{
+		Number: big.NewInt(0),
+		Time:   1000,
+	}
+
+	cr := &stakeChainReader{
+		mockChainReader: mockChainReader{
+			headers: map[common.Hash]*types.Header{genesis.Hash(): genesis},
+		},
+		states: map[common.Hash]*state.StateDB{},
+	}
+	cr.states[genesis.Hash()], _ = state.New(
+		common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	cr.extend(genesis, addresses, count, 0)
+
+	return cr
+}
+
+func TestStakeCache(t *testing.T) {
+	t.Parallel()
+	log.Root().SetHandler(log.DiscardHandler())
+
+	addresses, _, _, _ := generateAddresses(4)
+	blocks := int(2 * MaturityPeriod / MinBlockGap)
+	chain := newStakeChainReader(addresses, blocks)
+	engine := New(nil, nil)
+
+	check := func(head *types.Header) {
+		for _, now := range []uint64{
+			head.Time + MinBlockGap,
+			head.Time + MaturityPeriod/2,
+			head.Time - MaturityPeriod/3,
+		} {
+			for _, addr := range addresses {
+				expected, err := engine.scanStakeInfo(chain, now, head, addr)
+				assert.Empty(t, err)
+				info, err := engine.lookupStakeInfo(chain, now, head, addr)
+				assert.Empty(t, err)
+				assert.Equal(t, expected, info, "block %v now %v", head.Number, now)
+			}
+		}
+	}
+
+	// Sequential heads
+	var main []*types.Header
+	for head := chain.current; head.Number.Sign() > 0; {
+		main = append([]*types.Header{head}, main...)
+		head = chain.headers[head.ParentHash]
+	}
+	for _, head := range main {
+		check(head)
+	}
+
+	// Reorg to a side chain and back
+	fork := main[len(main)-20]
+	side := chain.extend(fork, addresses, 25, 7)
+	check(side)
+	check(main[len(main)-1])
+
+	// Jump far ahead
+	far := chain.extend(main[len(main)-1], addresses, blocks, 3)
+	check(far)
+
+	// Missing state must not be cached
+	next := chain.extend(far, addresses, 1, 5)
+	state := chain.states[next.Hash()]
+	delete(chain.states, next.Hash())
+	_, err := engine.lookupStakeInfo(chain, next.Time+MinBlockGap, next, addresses[0])
+	assert.Equal(t, eth_consensus.ErrMissingState, err)
+	chain.states[next.Hash()] = state
+	check(next)
+}
+
+func benchmarkStakeWeight(b *testing.B, lookup func(*Energi) func(
+	ChainReader, uint64, *types.Header, common.Address) (stakeInfo, error),
+) {
+	log.Root().SetHandler(log.DiscardHandler())
+
+	addresses, _, _, _ := generateAddresses(16)
+	chain := newStakeChainReader(addresses, int(2*MaturityPeriod/MinBlockGap))
+	engine := New(nil, nil)
+	fn := lookup(engine)
+	head := chain.current
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Like the PoS miner does for every candidate each second
+		if i%int(TargetBlockGap) == 0 {
+			b.StopTimer()
+			head = chain.extend(head, addresses, 1, int64(i))
+			b.StartTimer()
+		}
+		now := head.Time + MinBlockGap + uint64(i)%TargetBlockGap
+
+		for _, addr := range addresses {
+			if _, err := fn(chain, now, head, addr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkStakeWeightScan(b *testing.B) {
+	benchmarkStakeWeight(b, func(e *Energi) func(
+		ChainReader, uint64, *types.Header, common.Address) (stakeInfo, error) {
+		return e.scanStakeInfo
+	})
+}
+
+func BenchmarkStakeWeightCache(b *testing.B) {
+	benchmarkStakeWeight(b, func(e *Energi) func(
+		ChainReader, uint64, *types.Header, common.Address) (stakeInfo, error) {
+		return e.lookupStakeInfo
+	})
+}
@@ -0,0 +1,318 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"math/big"
+	"sync"
+
+	"energi.world/core/gen3/common"
+	eth_consensus "energi.world/core/gen3/consensus"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/log"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	stakeCacheAddresses = 1024
+)
+
+// stakeEntry is the stake data of a single block for a single address.
+type stakeEntry struct {
+	hash     common.Hash
+	parent   common.Hash
+	number   uint64
+	time     uint64
+	weight   uint64 // balance weight after the block
+	coinbase bool
+	staked   uint64 // weight used by the block, if coinbase
+}
+
+// stakeWindow is a contiguous part of a chain ending at the last looked up
+// head, the oldest entry first.
+//
+// New heads append their entries, reorgs truncate the window back to the
+// common ancestor and entries which left the maturity period get purged.
+// So, each block state gets calculated only once per address.
+type stakeWindow struct {
+	mtx     sync.Mutex
+	entries []stakeEntry
+}
+
+// stakeCache keeps the minimum balance windows of recently used addresses.
+// It is shared by the PoS miner and PoS hash verification.
+type stakeCache struct {
+	windows *lru.Cache
+}
+
+func newStakeCache() *stakeCache {
+	windows, err := lru.New(stakeCacheAddresses)
+	if err != nil {
+		panic(err)
+	}
+
+	return &stakeCache{windows}
+}
+
+func (sc *stakeCache) window(addr common.Address) *stakeWindow {
+	if existing, ok := sc.windows.Get(addr); ok {
+		return existing.(*stakeWindow)
+	}
+
+	window := &stakeWindow{}
+
+	// NOTE: an existing window is kept, if added concurrently
+	if found, _ := sc.windows.ContainsOrAdd(addr, window); found {
+		if existing, ok := sc.windows.Get(addr); ok {
+			return existing.(*stakeWindow)
+		}
+	}
+
+	return window
+}
+
+// lookup is an equivalent of scanStakeInfo() which calculates block states
+// only for blocks not seen before.
+func (sc *stakeCache) lookup(
+	chain ChainReader,
+	now uint64,
+	till *types.Header,
+	addr common.Address,
+) (info stakeInfo, err error) {
+	var since uint64
+
+	if now > MaturityPeriod {
+		since = now - MaturityPeriod
+	} else {
+		since = 0
+	}
+
+	window := sc.window(addr)
+	window.mtx.Lock()
+	defer window.mtx.Unlock()
+
+	if err = window.update(chain, since, till, addr); err != nil {
+		// Rebuild on the next lookup
+		window.entries = nil
+		return info, err
+	}
+
+	info = window.stakeInfo(since, addr)
+
+	if till.Time > MaturityPeriod {
+		window.purge(till.Time - MaturityPeriod)
+	}
+
+	return info, nil
+}
+
+// update makes the window end at the till header and cover all blocks
+// newer than the since time.
+func (w *stakeWindow) update(
+	chain ChainReader,
+	since uint64,
+	till *types.Header,
+	addr common.Address,
+) error {
+	// Collect headers down to the common ancestor
+	headers := []*types.Header{}
+
+	for curr := till; ; {
+		if w.contains(curr) {
+			w.entries = w.entries[:curr.Number.Uint64()-w.entries[0].number+1]
+			break
+		}
+
+		// The window is too far behind or on a different chain
+		if curr != till && curr.Time <= since {
+			w.entries = nil
+			break
+		}
+
+		headers = append(headers, curr)
+
+		if curr.Number.Sign() == 0 {
+			w.entries = nil
+			break
+		}
+
+		parent := chain.GetHeader(curr.ParentHash, curr.Number.Uint64()-1)
+		if parent == nil {
+			log.Error("PoS state missing parent", "parent", curr.ParentHash)
+			return eth_consensus.ErrUnknownAncestor
+		}
+
+		curr = parent
+	}
+
+	// Append new blocks, the oldest first
+	for i := len(headers) - 1; i >= 0; i-- {
+		entry, err := newStakeEntry(chain, headers[i], addr)
+		if err != nil {
+			return err
+		}
+		w.entries = append(w.entries, entry)
+	}
+
+	// Extend back for lookups with earlier time
+	for {
+		oldest := &w.entries[0]
+		if oldest.time <= since || oldest.number == 0 || w.hasZeroWeight() {
+			break
+		}
+
+		header := chain.GetHeader(oldest.parent, oldest.number-1)
+		if header == nil {
+			log.Error("PoS state missing parent", "parent", oldest.parent)
+			return eth_consensus.ErrUnknownAncestor
+		}
+
+		if header.Time <= since {
+			break
+		}
+
+		entry, err := newStakeEntry(chain, header, addr)
+		if err != nil {
+			return err
+		}
+		w.entries = append([]stakeEntry{entry}, w.entries...)
+	}
+
+	return nil
+}
+
+func (w *stakeWindow) contains(header *types.Header) bool {
+	if len(w.entries) == 0 {
+		return false
+	}
+
+	number := header.Number.Uint64()
+	first := w.entries[0].number
+
+	if number < first || number-first >= uint64(len(w.entries)) {
+		return false
+	}
+
+	return w.entries[number-first].hash == header.Hash()
+}
+
+func (w *stakeWindow) hasZeroWeight() bool {
+	for i := range w.entries {
+		if w.entries[i].weight < 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// purge removes entries which are not newer than the time, except the head.
+func (w *stakeWindow) purge(time uint64) {
+	drop := 0
+	for drop < len(w.entries)-1 && w.entries[drop].time <= time {
+		drop++
+	}
+
+	if drop > 0 {
+		w.entries = append([]stakeEntry{}, w.entries[drop:]...)
+	}
+}
+
+// stakeInfo walks the window from the head the same way as scanStakeInfo()
+// walks the chain.
+func (w *stakeWindow) stakeInfo(since uint64, addr common.Address) (info stakeInfo) {
+	// NOTE: Do not set to high initial value due to defensive coding approach!
+	weight := uint64(0)
+	total_staked := uint64(0)
+
+	for i := len(w.entries) - 1; i >= 0; i-- {
+		entry := &w.entries[i]
+
+		if i == len(w.entries)-1 {
+			weight = entry.weight
+		} else if entry.time <= since {
+			break
+		}
+
+		// Find the minimum balance
+		if weight > entry.weight {
+			weight = entry.weight
+		}
+
+		// No need to lookup further
+		if weight < 1 {
+			break
+		}
+
+		// POS-22: partial stake amount
+		if entry.coinbase {
+			total_staked += entry.staked
+
+			if info.lockedUntil == 0 {
+				info.lockedUntil = entry.time + MaturityPeriod
+			}
+		}
+	}
+
+	info.balanceWeight = weight
+	info.stakedWeight = total_staked
+
+	if weight < total_staked {
+		log.Debug("Nothing to stake",
+			"addr", addr, "since", since, "weight", weight, "total_staked", total_staked)
+		info.weight = 0
+	} else {
+		info.weight = weight - total_staked
+	}
+
+	return info
+}
+
+func newStakeEntry(
+	chain ChainReader,
+	header *types.Header,
+	addr common.Address,
+) (entry stakeEntry, err error) {
+	blockst := chain.CalculateBlockState(header.Hash(), header.Number.Uint64())
+	if blockst == nil {
+		log.Warn("PoS state root failure", "header", header.Hash())
+		return entry, eth_consensus.ErrMissingState
+	}
+
+	weight := new(big.Int).Div(blockst.GetBalance(addr), minStake).Uint64()
+
+	// On-demand state of light clients may fail to get retrieved
+	if err = blockst.Error(); err != nil {
+		log.Warn("PoS state retrieval failure", "header", header.Hash(), "err", err)
+		return entry, err
+	}
+
+	entry = stakeEntry{
+		hash:     header.Hash(),
+		parent:   header.ParentHash,
+		number:   header.Number.Uint64(),
+		time:     header.Time,
+		weight:   weight,
+		coinbase: header.Coinbase == addr,
+	}
+
+	if entry.coinbase {
+		entry.staked = header.Nonce.Uint64()
+	}
+
+	return entry, nil
+}
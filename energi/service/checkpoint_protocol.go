@@ -0,0 +1,435 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/eth/downloader"
+	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/p2p"
+
+	mapset "github.com/deckarep/golang-set"
+)
+
+// Checkpoint gossip sub-protocol. It lets nodes exchange signed checkpoints
+// regardless of the sync state, so they get enforced during header download.
+// Peers are asked for their checkpoints only when the header download starts.
+const (
+	cpProtocolName       = "nrgcp"
+	cpProtocolVersion    = 1
+	cpProtocolLength     = 2
+	cpProtocolMaxMsgSize = 1024 * 1024
+
+	// GetCheckpointsMsg requests all signed checkpoints known to the peer.
+	GetCheckpointsMsg = 0x00
+	// CheckpointsMsg delivers signed checkpoints, either as response or
+	// as a new checkpoint announcement.
+	CheckpointsMsg = 0x01
+
+	maxCheckpointsPerMsg = 64
+	maxKnownCheckpoints  = 128
+	maxQueuedCheckpoints = maxKnownCheckpoints
+	cpEventChanSize      = 16
+
+	// Signatures per checkpoint, if the chain can not tell the actual limit
+	defaultMaxCheckpointSignatures = 1024
+
+	// Unsolicited checkpoints accepted from a single peer per interval.
+	// Responses to our own requests are not limited.
+	cpRateInterval = time.Minute
+	cpRateLimit    = 2 * maxCheckpointsPerMsg
+)
+
+var (
+	errCpMsgTooLarge   = errors.New("checkpoint message too large")
+	errCpTooMany       = errors.New("too many checkpoints")
+	errCpTooManySigs   = errors.New("too many checkpoint signatures")
+	errCpRateLimit     = errors.New("checkpoint rate limit exceeded")
+	errCpInvalid       = errors.New("invalid checkpoint")
+	errCpUnknownMsg    = errors.New("unknown checkpoint message")
+	errCpGossipStopped = errors.New("checkpoint gossip stopped")
+)
+
+// signedCheckpoint is the wire format of a checkpoint.
+type signedCheckpoint struct {
+	Number     uint64
+	Hash       common.Hash
+	Signatures []core.CheckpointSignature
+}
+
+// checkpointChain is the part of the blockchain used by the gossip.
+type checkpointChain interface {
+	AddCheckpoint(cp core.Checkpoint, sigs []core.CheckpointSignature, local bool) error
+	ListCheckpoints() []core.CheckpointInfo
+	CheckpointSignatures(cp core.Checkpoint) []core.CheckpointSignature
+	CheckpointSignersLimit() int
+	SubscribeNewCheckpointEvent(ch chan<- core.NewCheckpointEvent) event.Subscription
+}
+
+type cpPeer struct {
+	*p2p.Peer
+	rw p2p.MsgReadWriter
+
+	knownCps  mapset.Set
+	queuedCps chan *signedCheckpoint
+	requestCh chan struct{}
+	term      chan struct{}
+
+	rateSince time.Time
+	rateCount int
+
+	requested int32 // outstanding checkpoint requests, accessed atomically
+}
+
+func newCpPeer(p *p2p.Peer, rw p2p.MsgReadWriter) *cpPeer {
+	return &cpPeer{
+		Peer:      p,
+		rw:        rw,
+		knownCps:  mapset.NewSet(),
+		queuedCps: make(chan *signedCheckpoint, maxQueuedCheckpoints),
+		requestCh: make(chan struct{}, 1),
+		term:      make(chan struct{}),
+	}
+}
+
+func (p *cpPeer) markCheckpoint(hash common.Hash) {
+	// If we reached the memory allowance, drop a previously known checkpoint
+	for p.knownCps.Cardinality() >= maxKnownCheckpoints {
+		p.knownCps.Pop()
+	}
+	p.knownCps.Add(hash)
+}
+
+func (p *cpPeer) sendCheckpoints(cps []*signedCheckpoint) error {
+	for _, cp := range cps {
+		p.markCheckpoint(cp.Hash)
+	}
+	return p2p.Send(p.rw, CheckpointsMsg, cps)
+}
+
+func (p *cpPeer) asyncSendCheckpoint(cp *signedCheckpoint) {
+	if p.knownCps.Contains(cp.Hash) {
+		return
+	}
+
+	select {
+	case p.queuedCps <- cp:
+		p.markCheckpoint(cp.Hash)
+	default:
+		p.Log().Debug("Dropping checkpoint propagation", "number", cp.Number, "hash", cp.Hash)
+	}
+}
+
+// allowCheckpoints accounts received checkpoints against the rate limit of
+// the peer.
+func (p *cpPeer) allowCheckpoints(count int) bool {
+	now := time.Now()
+	if now.Sub(p.rateSince) >= cpRateInterval {
+		p.rateSince = now
+		p.rateCount = 0
+	}
+
+	p.rateCount += count
+	return p.rateCount <= cpRateLimit
+}
+
+// takeRequest consumes an outstanding request, if any. The next checkpoint
+// message is considered to be its response then.
+func (p *cpPeer) takeRequest() bool {
+	for {
+		requested := atomic.LoadInt32(&p.requested)
+		if requested <= 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&p.requested, requested, requested-1) {
+			return true
+		}
+	}
+}
+
+// requestCheckpoints schedules a request for all checkpoints known to the peer.
+func (p *cpPeer) requestCheckpoints() {
+	select {
+	case p.requestCh <- struct{}{}:
+	default:
+		// already pending
+	}
+}
+
+// broadcast is a write loop to avoid blocking on slow peers.
+func (p *cpPeer) broadcast() {
+	for {
+		select {
+		case <-p.requestCh:
+			p.Log().Trace("Requesting checkpoints")
+			atomic.AddInt32(&p.requested, 1)
+			if err := p2p.Send(p.rw, GetCheckpointsMsg, struct{}{}); err != nil {
+				return
+			}
+
+		case cp := <-p.queuedCps:
+			if err := p2p.Send(p.rw, CheckpointsMsg, []*signedCheckpoint{cp}); err != nil {
+				return
+			}
+			p.Log().Trace("Broadcast checkpoint", "number", cp.Number, "hash", cp.Hash)
+
+		case <-p.term:
+			return
+		}
+	}
+}
+
+type checkpointGossip struct {
+	chain   checkpointChain
+	mux     *event.TypeMux
+	syncing func() bool
+
+	peers    map[*cpPeer]struct{}
+	peersMtx sync.RWMutex
+
+	quitCh chan struct{}
+}
+
+func newCheckpointGossip(
+	chain checkpointChain,
+	mux *event.TypeMux,
+	syncing func() bool,
+) *checkpointGossip {
+	return &checkpointGossip{
+		chain:   chain,
+		mux:     mux,
+		syncing: syncing,
+		peers:   make(map[*cpPeer]struct{}),
+		quitCh:  make(chan struct{}),
+	}
+}
+
+func (g *checkpointGossip) protocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    cpProtocolName,
+		Version: cpProtocolVersion,
+		Length:  cpProtocolLength,
+		Run:     g.handlePeer,
+	}
+}
+
+func (g *checkpointGossip) start() {
+	cpCh := make(chan core.NewCheckpointEvent, cpEventChanSize)
+	cpSub := g.chain.SubscribeNewCheckpointEvent(cpCh)
+	syncSub := g.mux.Subscribe(downloader.StartEvent{})
+
+	go g.loop(cpCh, cpSub, syncSub)
+}
+
+func (g *checkpointGossip) stop() {
+	close(g.quitCh)
+}
+
+// loop propagates newly accepted remote checkpoints to all peers and
+// requests checkpoints from all peers when the header download starts.
+func (g *checkpointGossip) loop(
+	cpCh chan core.NewCheckpointEvent,
+	cpSub event.Subscription,
+	syncSub *event.TypeMuxSubscription,
+) {
+	defer cpSub.Unsubscribe()
+	defer syncSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-syncSub.Chan():
+			if ev == nil {
+				return
+			}
+
+			g.peersMtx.RLock()
+			for p := range g.peers {
+				p.requestCheckpoints()
+			}
+			g.peersMtx.RUnlock()
+
+		case ev := <-cpCh:
+			scp := g.signedCheckpoint(ev.Checkpoint)
+			if scp == nil {
+				continue
+			}
+
+			g.peersMtx.RLock()
+			for p := range g.peers {
+				p.asyncSendCheckpoint(scp)
+			}
+			g.peersMtx.RUnlock()
+
+		case <-cpSub.Err():
+			return
+
+		case <-g.quitCh:
+			return
+		}
+	}
+}
+
+func (g *checkpointGossip) signedCheckpoint(cp core.Checkpoint) *signedCheckpoint {
+	sigs := g.chain.CheckpointSignatures(cp)
+
+	// Local checkpoints are not verifiable by peers
+	if len(sigs) == 0 {
+		return nil
+	}
+
+	return &signedCheckpoint{
+		Number:     cp.Number,
+		Hash:       cp.Hash,
+		Signatures: sigs,
+	}
+}
+
+func (g *checkpointGossip) handlePeer(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+	p := newCpPeer(peer, rw)
+
+	g.peersMtx.Lock()
+	g.peers[p] = struct{}{}
+	g.peersMtx.Unlock()
+
+	defer func() {
+		g.peersMtx.Lock()
+		delete(g.peers, p)
+		g.peersMtx.Unlock()
+	}()
+
+	go p.broadcast()
+	defer close(p.term)
+
+	// The peer may be the one we are syncing with
+	if g.syncing() {
+		p.requestCheckpoints()
+	}
+
+	for {
+		select {
+		case <-g.quitCh:
+			return errCpGossipStopped
+		default:
+		}
+
+		if err := g.handleMsg(p); err != nil {
+			p.Log().Debug("Checkpoint gossip failed", "err", err)
+			return err
+		}
+	}
+}
+
+func (g *checkpointGossip) handleMsg(p *cpPeer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Size > cpProtocolMaxMsgSize {
+		return errCpMsgTooLarge
+	}
+
+	switch msg.Code {
+	case GetCheckpointsMsg:
+		cps := g.chain.ListCheckpoints()
+		res := make([]*signedCheckpoint, 0, len(cps))
+
+		// NOTE: the list is sorted with the most recent first
+		for _, cpi := range cps {
+			if len(res) >= maxCheckpointsPerMsg {
+				break
+			}
+
+			if scp := g.signedCheckpoint(cpi.Checkpoint); scp != nil {
+				res = append(res, scp)
+			}
+		}
+
+		p.Log().Trace("Sending checkpoints", "count", len(res))
+		return p.sendCheckpoints(res)
+
+	case CheckpointsMsg:
+		var cps []*signedCheckpoint
+		if err := msg.Decode(&cps); err != nil {
+			return err
+		}
+
+		if len(cps) > maxCheckpointsPerMsg {
+			return errCpTooMany
+		}
+
+		if !p.takeRequest() && !p.allowCheckpoints(len(cps)) {
+			return errCpRateLimit
+		}
+
+		// Every signature costs an ecrecover and possibly a collateral lookup,
+		// so there can not be more than one per masternode plus CPP.
+		maxSigs := g.chain.CheckpointSignersLimit()
+		if maxSigs <= 0 {
+			maxSigs = defaultMaxCheckpointSignatures
+		}
+		for _, scp := range cps {
+			if len(scp.Signatures) > maxSigs {
+				return errCpTooManySigs
+			}
+		}
+
+		for _, scp := range cps {
+			p.markCheckpoint(scp.Hash)
+
+			p.Log().Debug("Received checkpoint", "number", scp.Number, "hash", scp.Hash)
+
+			err := g.chain.AddCheckpoint(
+				core.Checkpoint{
+					Since:  0, // not trusted
+					Number: scp.Number,
+					Hash:   scp.Hash,
+				},
+				scp.Signatures,
+				false,
+			)
+			switch err {
+			case nil:
+			case core.ErrCheckpointDeferred:
+				// NOTE: the peer may be ahead of us
+				p.Log().Debug("Deferred gossip checkpoint",
+					"number", scp.Number, "hash", scp.Hash)
+			case core.ErrCheckpointNoSignatures, core.ErrCheckpointBadSignature:
+				p.Log().Warn("Rejected gossip checkpoint",
+					"number", scp.Number, "hash", scp.Hash, "err", err)
+				return errCpInvalid
+			default:
+				// NOTE: quorum, local state and enforcement failures are
+				//       not the fault of the peer
+				p.Log().Debug("Ignored gossip checkpoint",
+					"number", scp.Number, "hash", scp.Hash, "err", err)
+			}
+		}
+
+		return nil
+
+	default:
+		return errCpUnknownMsg
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/eth/downloader"
+	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/p2p"
+	"energi.world/core/gen3/p2p/enode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gossipTestChain struct {
+	mtx     sync.Mutex
+	cps     map[core.Checkpoint][]core.CheckpointSignature
+	added   []core.Checkpoint
+	feed    event.Feed
+	maxSigs int
+	reject  map[common.Hash]error
+}
+
+func newGossipTestChain() *gossipTestChain {
+	return &gossipTestChain{
+		cps:    make(map[core.Checkpoint][]core.CheckpointSignature),
+		reject: make(map[common.Hash]error),
+	}
+}
+
+func (c *gossipTestChain) AddCheckpoint(
+	cp core.Checkpoint,
+	sigs []core.CheckpointSignature,
+	local bool,
+) error {
+	c.mtx.Lock()
+	if err, ok := c.reject[cp.Hash]; ok {
+		c.mtx.Unlock()
+		return err
+	}
+	c.cps[cp] = sigs
+	c.added = append(c.added, cp)
+	c.mtx.Unlock()
+
+	if !local {
+		c.feed.Send(core.NewCheckpointEvent{CheckpointInfo: core.CheckpointInfo{
+			Checkpoint:   cp,
+			CppSignature: sigs[0],
+			SigCount:     uint64(len(sigs)),
+		}})
+	}
+	return nil
+}
+
+func (c *gossipTestChain) ListCheckpoints() (res []core.CheckpointInfo) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for cp, sigs := range c.cps {
+		res = append(res, core.CheckpointInfo{Checkpoint: cp, SigCount: uint64(len(sigs))})
+	}
+	return
+}
+
+func (c *gossipTestChain) CheckpointSignatures(cp core.Checkpoint) []core.CheckpointSignature {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.cps[cp]
+}
+
+func (c *gossipTestChain) CheckpointSignersLimit() int {
+	return c.maxSigs
+}
+
+func (c *gossipTestChain) SubscribeNewCheckpointEvent(ch chan<- core.NewCheckpointEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func (c *gossipTestChain) addedCount() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.added)
+}
+
+func TestCheckpointGossip(t *testing.T) {
+	t.Parallel()
+
+	chain := newGossipTestChain()
+	mux := new(event.TypeMux)
+	defer mux.Stop()
+	gossip := newCheckpointGossip(chain, mux, func() bool { return true })
+	gossip.start()
+	defer gossip.stop()
+
+	signed := core.Checkpoint{Number: 10, Hash: common.HexToHash("0x10")}
+	chain.cps[signed] = []core.CheckpointSignature{{0x01}, {0x02}}
+	local := core.Checkpoint{Number: 5, Hash: common.HexToHash("0x05")}
+	chain.cps[local] = []core.CheckpointSignature{}
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+
+	peer := p2p.NewPeer(enode.ID{1}, "test", nil)
+	errCh := make(chan error, 1)
+	go func() { errCh <- gossip.handlePeer(peer, net) }()
+
+	// Checkpoints are requested on connect while syncing
+	assert.Empty(t, p2p.ExpectMsg(app, GetCheckpointsMsg, struct{}{}))
+
+	// ... and on every header download start
+	assert.Empty(t, mux.Post(downloader.StartEvent{}))
+	assert.Empty(t, p2p.ExpectMsg(app, GetCheckpointsMsg, struct{}{}))
+
+	// Only signed checkpoints are served
+	go p2p.Send(app, GetCheckpointsMsg, struct{}{})
+	assert.Empty(t, p2p.ExpectMsg(app, CheckpointsMsg, []*signedCheckpoint{
+		{signed.Number, signed.Hash, chain.cps[signed]},
+	}))
+
+	// Received checkpoints are added as remote
+	remote := &signedCheckpoint{
+		Number:     20,
+		Hash:       common.HexToHash("0x20"),
+		Signatures: []core.CheckpointSignature{{0x03}},
+	}
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{remote}))
+
+	for i := 0; i < 100 && chain.addedCount() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []core.Checkpoint{{Number: 20, Hash: remote.Hash}}, chain.added)
+
+	// New checkpoints are propagated, except back to the source
+	fresh := core.Checkpoint{Number: 30, Hash: common.HexToHash("0x30")}
+	assert.Empty(t, chain.AddCheckpoint(fresh, []core.CheckpointSignature{{0x04}}, false))
+	assert.Empty(t, p2p.ExpectMsg(app, CheckpointsMsg, []*signedCheckpoint{
+		{fresh.Number, fresh.Hash, []core.CheckpointSignature{{0x04}}},
+	}))
+
+	// Protocol violation disconnects
+	assert.Empty(t, p2p.Send(app, cpProtocolLength, struct{}{}))
+	select {
+	case err := <-errCh:
+		assert.Equal(t, errCpUnknownMsg, err)
+	case <-time.After(time.Second):
+		t.Fatal("peer is not disconnected")
+	}
+}
+
+func TestCheckpointGossipLimits(t *testing.T) {
+	t.Parallel()
+
+	chain := newGossipTestChain()
+	chain.maxSigs = 2
+	mux := new(event.TypeMux)
+	defer mux.Stop()
+	gossip := newCheckpointGossip(chain, mux, func() bool { return false })
+
+	connect := func() (p2p.MsgReadWriter, chan error) {
+		app, net := p2p.MsgPipe()
+		peer := p2p.NewPeer(enode.ID{2}, "test", nil)
+		errCh := make(chan error, 1)
+		go func() { errCh <- gossip.handlePeer(peer, net) }()
+		return app, errCh
+	}
+	expectDrop := func(errCh chan error, expected error) {
+		select {
+		case err := <-errCh:
+			assert.Equal(t, expected, err)
+		case <-time.After(time.Second):
+			t.Fatal("peer is not disconnected")
+		}
+	}
+	checkpoint := func(num uint64, sigs int) *signedCheckpoint {
+		return &signedCheckpoint{
+			Number:     num,
+			Hash:       common.BigToHash(new(big.Int).SetUint64(num)),
+			Signatures: make([]core.CheckpointSignature, sigs),
+		}
+	}
+
+	// Deferred and unconfirmed checkpoints are fine, invalid ones drop the peer
+	app, errCh := connect()
+	deferred := checkpoint(1, 1)
+	noquorum := checkpoint(5, 1)
+	invalid := checkpoint(2, 1)
+	chain.reject[deferred.Hash] = core.ErrCheckpointDeferred
+	chain.reject[noquorum.Hash] = errors.New("checkpoint masternode quorum is not reached")
+	chain.reject[invalid.Hash] = core.ErrCheckpointBadSignature
+
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{deferred}))
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{noquorum}))
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{invalid}))
+	expectDrop(errCh, errCpInvalid)
+
+	// Signatures are capped
+	app, errCh = connect()
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{checkpoint(3, 2)}))
+	assert.Empty(t, p2p.Send(app, CheckpointsMsg, []*signedCheckpoint{checkpoint(4, 3)}))
+	expectDrop(errCh, errCpTooManySigs)
+
+	// Checkpoints are rate limited per peer
+	app, errCh = connect()
+	for i := 0; i < cpRateLimit/maxCheckpointsPerMsg+1; i++ {
+		cps := make([]*signedCheckpoint, maxCheckpointsPerMsg)
+		for j := range cps {
+			cps[j] = checkpoint(uint64(100+i*maxCheckpointsPerMsg+j), 1)
+		}
+		assert.Empty(t, p2p.Send(app, CheckpointsMsg, cps))
+	}
+	expectDrop(errCh, errCpRateLimit)
+}
+
+func TestCheckpointGossipResponses(t *testing.T) {
+	t.Parallel()
+
+	chain := newGossipTestChain()
+	mux := new(event.TypeMux)
+	defer mux.Stop()
+	gossip := newCheckpointGossip(chain, mux, func() bool { return true })
+	gossip.start()
+	defer gossip.stop()
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+
+	peer := p2p.NewPeer(enode.ID{3}, "test", nil)
+	errCh := make(chan error, 1)
+	go func() { errCh <- gossip.handlePeer(peer, net) }()
+
+	next := uint64(100)
+	checkpoints := func() []*signedCheckpoint {
+		cps := make([]*signedCheckpoint, maxCheckpointsPerMsg)
+		for i := range cps {
+			cps[i] = &signedCheckpoint{
+				Number:     next,
+				Hash:       common.BigToHash(new(big.Int).SetUint64(next)),
+				Signatures: make([]core.CheckpointSignature, 1),
+			}
+			next++
+		}
+		return cps
+	}
+
+	// Responses to our requests do not count against the rate limit
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			assert.Empty(t, mux.Post(downloader.StartEvent{}))
+		}
+		assert.Empty(t, p2p.ExpectMsg(app, GetCheckpointsMsg, struct{}{}))
+		assert.Empty(t, p2p.Send(app, CheckpointsMsg, checkpoints()))
+	}
+
+	// ... unlike announcements
+	for i := 0; i < cpRateLimit/maxCheckpointsPerMsg+1; i++ {
+		assert.Empty(t, p2p.Send(app, CheckpointsMsg, checkpoints()))
+	}
+	select {
+	case err := <-errCh:
+		assert.Equal(t, errCpRateLimit, err)
+	case <-time.After(time.Second):
+		t.Fatal("peer is not disconnected")
+	}
+	assert.Equal(t, 2*maxCheckpointsPerMsg+cpRateLimit, chain.addedCount())
+}
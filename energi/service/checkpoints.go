@@ -50,6 +50,8 @@ type CheckpointService struct {
 
 	cpRegistry *energi_abi.ICheckpointRegistry
 	callOpts   *bind.CallOpts
+
+	gossip *checkpointGossip
 }
 
 func NewCheckpointService(ethServ *eth.Ethereum) (node.Service, error) {
@@ -57,11 +59,16 @@ func NewCheckpointService(ethServ *eth.Ethereum) (node.Service, error) {
 		eth:      ethServ,
 		callOpts: &bind.CallOpts{},
 	}
+	r.gossip = newCheckpointGossip(
+		ethServ.BlockChain(),
+		ethServ.EventMux(),
+		func() bool { return ethServ.Downloader().Synchronising() },
+	)
 	return r, nil
 }
 
 func (c *CheckpointService) Protocols() []p2p.Protocol {
-	return nil
+	return []p2p.Protocol{c.gossip.protocol()}
 }
 
 func (c *CheckpointService) APIs() []rpc.API {
@@ -76,6 +83,7 @@ func (c *CheckpointService) Start(server *p2p.Server) (err error) {
 	}
 
 	c.server = server
+	c.gossip.start()
 
 	//---
 	oldCheckpoints, err := c.cpRegistry.Checkpoints(c.callOpts)
@@ -92,6 +100,7 @@ func (c *CheckpointService) Start(server *p2p.Server) (err error) {
 }
 
 func (c *CheckpointService) Stop() error {
+	c.gossip.stop()
 	return nil
 }
 
@@ -197,6 +206,18 @@ func (c *CheckpointService) onCheckpoint(cpAddr common.Address, live bool) {
 		core.CheckpointSignature(cpp_sig),
 	}
 
+	// Masternode signatures are required by quorum checkpoint policies
+	mn_sigs, err := cp.Signatures(c.callOpts)
+	if err != nil {
+		log.Debug("Failed to get CP signatures", "addr", cpAddr, "err", err)
+	}
+
+	// NOTE: the CPP signature may be listed again, but signers are
+	//       deduplicated during quorum validation.
+	for _, sig := range mn_sigs {
+		sigs = append(sigs, core.CheckpointSignature(sig))
+	}
+
 	backend.AddDynamicCheckpoint(info.Since.Uint64(), info.Number.Uint64(), info.Hash, sigs)
 
 	if live {
@@ -20,6 +20,7 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"net"
+	"os"
 	"reflect"
 	"sync"
 	"testing"
@@ -239,6 +240,7 @@ func TestCheckpointsService(t *testing.T) {
 	for _, data := range nodesInfo {
 		err = data.stack.Stop()
 		withErr("Failed to stop the protocol stack", err)
+		os.RemoveAll(data.stack.DataDir())
 	}
 }
 
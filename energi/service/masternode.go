@@ -19,6 +19,8 @@ package service
 import (
 	"errors"
 	"math/big"
+	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -42,6 +44,11 @@ import (
 var (
 	heartbeatInterval = time.Duration(5) * time.Minute
 	recheckInterval   = time.Duration(2) * time.Minute
+
+	// MN-14: validation duty
+	validationConnectTimeout    = time.Minute
+	validationChallengeTimeout  = time.Duration(15) * time.Second
+	validationChallengeInterval = time.Duration(5) * time.Second
 )
 
 const (
@@ -51,6 +58,16 @@ const (
 	// checkpoints channel before it can be considered to be full.
 	cpChanBufferSize  = 16
 	chainHeadChanSize = 10
+
+	// validationChallenges is the number of block availability challenges
+	// made during a single validation.
+	validationChallenges = 4
+	// validationMaxFailures is the number of failed challenges which are
+	// tolerated before invalidation.
+	validationMaxFailures = 1
+	// validationMinBlockAge prevents challenges on blocks which may still
+	// be propagating through the network.
+	validationMinBlockAge uint64 = 16
 )
 
 type checkpointVote struct {
@@ -76,6 +93,14 @@ type MasternodeService struct {
 	features *big.Int
 
 	validator *peerValidator
+
+	// Self-diagnostics, see MasternodeAPI
+	statusMtx     sync.RWMutex
+	ownerMismatch bool
+	lastHBTx      common.Hash
+	lastHBTime    time.Time
+	lastErr       error
+	lastErrTime   time.Time
 }
 
 func NewMasternodeService(ethServ *eth.Ethereum, owner common.Address) (node.Service, error) {
@@ -99,7 +124,14 @@ func (m *MasternodeService) Protocols() []p2p.Protocol {
 }
 
 func (m *MasternodeService) APIs() []rpc.API {
-	return nil
+	return []rpc.API{
+		{
+			Namespace: "masternode",
+			Version:   "1.0",
+			Service:   NewMasternodeAPI(m),
+			Public:    false,
+		},
+	}
 }
 
 func (m *MasternodeService) Start(server *p2p.Server) error {
@@ -156,7 +188,10 @@ func (m *MasternodeService) Start(server *p2p.Server) error {
 	}
 
 	m.server = server
+
+	m.statusMtx.Lock()
 	m.validator = newPeerValidator(common.Address{}, m)
+	m.statusMtx.Unlock()
 	go m.loop()
 
 	log.Info("Started Energi Masternode", "addr", address)
@@ -205,10 +240,17 @@ func (m *MasternodeService) isActive() bool {
 		mninfo, err := m.registry.Info(m.address)
 		if err != nil {
 			log.Error("Masternode info fetch Err: %v", err)
+			m.setLastError(err)
 			return false
 		}
 
-		if mninfo.Owner != m.owner {
+		owner_mismatch := mninfo.Owner != m.owner
+
+		m.statusMtx.Lock()
+		m.ownerMismatch = owner_mismatch
+		m.statusMtx.Unlock()
+
+		if owner_mismatch {
 			log.Error("Masternode owner mismatch", " needed=", m.owner, " got=", mninfo.Owner)
 			return false
 		}
@@ -218,6 +260,7 @@ func (m *MasternodeService) isActive() bool {
 
 	if err != nil {
 		log.Error("Masternode check failed", "err", err)
+		m.setLastError(err)
 		return false
 	}
 
@@ -335,6 +378,7 @@ func (m *MasternodeService) voteOnCheckpoints() {
 
 				if err != nil {
 					log.Error("Checkpoint vote failed", "checkpoint", cpVote.address, "err", err)
+					m.setLastError(err)
 				}
 			}
 
@@ -357,7 +401,7 @@ func (m *MasternodeService) onChainHead(block *types.Block) {
 	// MN-4 - Heartbeats
 	now := time.Now()
 
-	if now.After(m.nextHB) {
+	if now.After(m.nextHeartbeat()) {
 		// It is more important than invalidation duty.
 		// Some chance of race is still left, but at acceptable probability.
 		m.validator.cancel()
@@ -372,13 +416,19 @@ func (m *MasternodeService) onChainHead(block *types.Block) {
 			current := m.eth.BlockChain().CurrentHeader()
 			tx, err := m.registry.Heartbeat(current.Number, current.Hash(), m.features)
 
+			m.statusMtx.Lock()
 			if err == nil {
 				log.Info("Masternode Heartbeat", "tx", tx.Hash())
 				m.nextHB = now.Add(heartbeatInterval)
+				m.lastHBTx = tx.Hash()
+				m.lastHBTime = now
 			} else {
 				log.Error("Failed to send Masternode Heartbeat", "err", err)
 				m.nextHB = now.Add(recheckInterval)
+				m.lastErr = err
+				m.lastErrTime = now
 			}
+			m.statusMtx.Unlock()
 		} else {
 			// NOTE: we need to recover from Nonce mismatch to enable heartbeats
 			//       as soon as possible.
@@ -395,6 +445,7 @@ func (m *MasternodeService) onChainHead(block *types.Block) {
 	target, err := m.registry.ValidationTarget(m.address)
 	if err != nil {
 		log.Warn("MNTarget error", "mn", m.address, "err", err)
+		m.setLastError(err)
 		m.validator.cancel()
 		return
 	}
@@ -402,7 +453,10 @@ func (m *MasternodeService) onChainHead(block *types.Block) {
 	// MN-14: validation duty
 	if old_target := m.validator.target; old_target != target {
 		m.validator.cancel()
+
+		m.statusMtx.Lock()
 		m.validator = newPeerValidator(target, m)
+		m.statusMtx.Unlock()
 
 		// Only present in IMasternodeRegistryV2
 		if ok, err := m.registry.CanInvalidate(m.address); err == nil && !ok {
@@ -416,6 +470,21 @@ func (m *MasternodeService) onChainHead(block *types.Block) {
 	}
 }
 
+func (m *MasternodeService) nextHeartbeat() time.Time {
+	m.statusMtx.RLock()
+	defer m.statusMtx.RUnlock()
+
+	return m.nextHB
+}
+
+func (m *MasternodeService) setLastError(err error) {
+	m.statusMtx.Lock()
+	defer m.statusMtx.Unlock()
+
+	m.lastErr = err
+	m.lastErrTime = time.Now()
+}
+
 type peerValidator struct {
 	target   common.Address
 	mnsvc    *MasternodeService
@@ -465,57 +534,104 @@ func (v *peerValidator) validate() {
 		return
 	}
 
-	// Check if the node is already connected as a peer and
-	// skip MN Validation if true.
-	if isFound := server.IsPeerActive(enode); isFound {
-		log.Debug("Masternode validation skipped since peer is already connected",
-			"target", v.target.Hex())
-		return
-	}
+	// Connect to the peer, unless it is already connected.
+	if !server.IsPeerActive(enode) {
+		server.AddPeer(enode)
 
-	peerCh := make(chan *p2p.PeerEvent)
-	defer close(peerCh)
+		defer func() {
+			// Disconnect this peer if more than half of the max peers are connected.
+			if server.PeerCount() > server.MaxPeers/2 {
+				server.RemovePeer(enode)
+			}
+		}()
+	}
 
-	peerSub := server.SubscribeEvents(peerCh)
+	//---
+	deadline := time.Now().Add(validationConnectTimeout)
+	failures := 0
+	skipped := 0
 
-	// EnableMsg Events.
-	server.EnableMsgEvents = true
-	defer peerSub.Unsubscribe()
+	for attempt := 0; attempt < validationChallenges; {
+		current := mnsvc.eth.BlockChain().CurrentHeader().Number.Uint64()
+		if current <= validationMinBlockAge {
+			return
+		}
 
-	server.AddPeer(enode)
+		number := 1 + uint64(rand.Int63n(int64(current-validationMinBlockAge)))
+		err := mnsvc.eth.ChallengePeer(enode.ID(), number, validationChallengeTimeout)
 
-	defer func() {
-		// Disconnect this peer if more than half of the max peers are connected.
-		if server.PeerCount() > server.MaxPeers/2 {
-			server.RemovePeer(enode)
+		if err == eth.ErrChallengePeerNotFound && time.Now().Before(deadline) {
+			// Wait for the protocol handshake to complete
+			select {
+			case <-v.cancelCh:
+				return
+			case <-time.After(time.Second):
+			}
+			continue
 		}
-	}()
 
-	//---
-	deadline := time.Now().Add(time.Minute)
+		switch err {
+		case nil:
+			attempt++
 
-	for {
-		select {
-		case <-v.cancelCh:
-			return
-		case pe := <-peerCh:
-			if pe.Peer != enode.ID() || pe.Type != p2p.PeerEventTypeMsgRecv {
-				break
-			}
-			// TODO: validate block availability as per MN-14
-			return
-		case <-time.After(deadline.Sub(time.Now())):
-			log.Info("MN Invalidation", "mn", v.target)
+		case eth.ErrChallengeTimeout, eth.ErrChallengeMismatch:
+			attempt++
+			failures++
+			log.Debug("MN challenge failed", "mn", v.target,
+				"number", number, "attempt", attempt, "err", err)
 
-			// TODO: an excepted, but not seen before problem on scale got identified.
-			log.Warn("Invalidations are temporary disabled.")
+		case eth.ErrChallengePeerNotFound:
+			// NOTE: an unreachable peer may be due to local connectivity
+			//       or a full peer list as well, so it does not count.
+			log.Debug("MN validation aborted", "mn", v.target, "err", err)
 			return
 
-			_, err := mnsvc.registry.Invalidate(v.target)
-			if err != nil {
-				log.Warn("MN Invalidate error", "mn", v.target, "err", err)
+		default:
+			// Local conditions tell nothing about the peer, so the
+			// challenge gets retried. The validation is given up, if
+			// they persist.
+			skipped++
+			log.Debug("MN challenge skipped", "mn", v.target,
+				"number", number, "skipped", skipped, "err", err)
+
+			if skipped > validationChallenges {
+				log.Debug("MN validation aborted", "mn", v.target, "err", err)
+				return
 			}
+		}
+
+		if failures > validationMaxFailures {
+			break
+		}
+
+		if (attempt - failures) >= (validationChallenges - validationMaxFailures) {
+			break
+		}
+
+		select {
+		case <-v.cancelCh:
 			return
+		case <-time.After(validationChallengeInterval):
 		}
 	}
+
+	if failures <= validationMaxFailures {
+		log.Debug("MN validation passed", "mn", v.target, "failures", failures)
+		return
+	}
+
+	// Last chance to cancel, e.g. due to heartbeat duty
+	select {
+	case <-v.cancelCh:
+		return
+	default:
+	}
+
+	log.Info("MN Invalidation", "mn", v.target, "failures", failures)
+
+	_, err = mnsvc.registry.Invalidate(v.target)
+	if err != nil {
+		log.Warn("MN Invalidate error", "mn", v.target, "err", err)
+		mnsvc.setLastError(err)
+	}
 }
@@ -0,0 +1,80 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"sync/atomic"
+
+	"energi.world/core/gen3/common"
+)
+
+// MasternodeAPI exposes the local masternode state for operator diagnostics.
+type MasternodeAPI struct {
+	mnsvc *MasternodeService
+}
+
+func NewMasternodeAPI(mnsvc *MasternodeService) *MasternodeAPI {
+	return &MasternodeAPI{mnsvc}
+}
+
+type MasternodeStatus struct {
+	Address                common.Address
+	Owner                  common.Address
+	InSync                 bool
+	OwnerMismatch          bool
+	NextHeartbeat          uint64
+	LastHeartbeatTx        common.Hash
+	LastHeartbeat          uint64
+	ValidationTarget       common.Address
+	PendingCheckpointVotes int
+	LastError              string
+	LastErrorTime          uint64
+}
+
+// Status reports the masternode duties state of this node. Timestamps are
+// in Unix seconds, zero if the event has not happened yet.
+func (a *MasternodeAPI) Status() *MasternodeStatus {
+	m := a.mnsvc
+
+	m.statusMtx.RLock()
+	defer m.statusMtx.RUnlock()
+
+	ret := &MasternodeStatus{
+		Address:                m.address,
+		Owner:                  m.owner,
+		InSync:                 atomic.LoadInt32(&m.inSync) != 0,
+		OwnerMismatch:          m.ownerMismatch,
+		NextHeartbeat:          uint64(m.nextHB.Unix()),
+		LastHeartbeatTx:        m.lastHBTx,
+		PendingCheckpointVotes: len(m.cpVoteChan),
+	}
+
+	if !m.lastHBTime.IsZero() {
+		ret.LastHeartbeat = uint64(m.lastHBTime.Unix())
+	}
+
+	if m.validator != nil {
+		ret.ValidationTarget = m.validator.target
+	}
+
+	if m.lastErr != nil {
+		ret.LastError = m.lastErr.Error()
+		ret.LastErrorTime = uint64(m.lastErrTime.Unix())
+	}
+
+	return ret
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMasternodeAPIStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	target := common.HexToAddress("0x2")
+
+	mnsvc := &MasternodeService{
+		address:    common.HexToAddress("0x1"),
+		inSync:     1,
+		nextHB:     now.Add(recheckInterval),
+		cpVoteChan: make(chan *checkpointVote, cpChanBufferSize),
+	}
+	api := NewMasternodeAPI(mnsvc)
+
+	status := api.Status()
+	assert.Equal(t, mnsvc.address, status.Address)
+	assert.True(t, status.InSync)
+	assert.False(t, status.OwnerMismatch)
+	assert.Equal(t, uint64(mnsvc.nextHB.Unix()), status.NextHeartbeat)
+	assert.Equal(t, uint64(0), status.LastHeartbeat)
+	assert.Equal(t, common.Address{}, status.ValidationTarget)
+	assert.Equal(t, 0, status.PendingCheckpointVotes)
+	assert.Empty(t, status.LastError)
+
+	mnsvc.validator = newPeerValidator(target, mnsvc)
+	mnsvc.cpVoteChan <- &checkpointVote{}
+	mnsvc.setLastError(errors.New("test error"))
+	mnsvc.inSync = 0
+
+	status = api.Status()
+	assert.False(t, status.InSync)
+	assert.Equal(t, target, status.ValidationTarget)
+	assert.Equal(t, 1, status.PendingCheckpointVotes)
+	assert.Equal(t, "test error", status.LastError)
+	assert.NotZero(t, status.LastErrorTime)
+}
@@ -20,8 +20,10 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"net"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -277,6 +279,7 @@ func TestMasternodeService(t *testing.T) {
 	for _, data := range nodesInfo {
 		err = data.stack.Stop()
 		withErr("Failed to stop the protocol stack", err)
+		os.RemoveAll(data.stack.DataDir())
 	}
 }
 
@@ -300,8 +303,16 @@ func serviceConfig(presale core.GenesisAlloc) *eth.Config {
 }
 
 // newNode create a new ethereum node service and registers it.
+// The data dir must be removed once the node is stopped.
 func newNode(privKey *ecdsa.PrivateKey, presale core.GenesisAlloc) (*node.Node, error) {
+	// Keeps the txpool journal and keystore out of the package dir
+	datadir, err := ioutil.TempDir("", "energi-service")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create data dir: %v", err)
+	}
+
 	config := &node.Config{
+		DataDir: datadir,
 		P2P: p2p.Config{
 			ListenAddr:  "0.0.0.0:0",
 			NAT:         nat.Any(),
@@ -315,6 +326,7 @@ func newNode(privKey *ecdsa.PrivateKey, presale core.GenesisAlloc) (*node.Node,
 
 	stack, err := node.New(config)
 	if err != nil {
+		os.RemoveAll(datadir)
 		return nil, fmt.Errorf("Failed to create network node: %v", err)
 	}
 
@@ -324,6 +336,7 @@ func newNode(privKey *ecdsa.PrivateKey, presale core.GenesisAlloc) (*node.Node,
 
 	// Register the ethereum(energi pos engine) service
 	if err := stack.Register(ethConstructor); err != nil {
+		os.RemoveAll(datadir)
 		return nil, fmt.Errorf("Failed to register Ethereum service: %v", err)
 	}
 
@@ -0,0 +1,399 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package energiclient provides a client for the Energi specific RPC APIs.
+package energiclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"energi.world/core/gen3"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/rpc"
+
+	energi_api "energi.world/core/gen3/energi/api"
+	energi_consensus "energi.world/core/gen3/energi/consensus"
+)
+
+// Client defines typed wrappers for the energi, masternode and staking
+// miner RPC APIs.
+type Client struct {
+	c *rpc.Client
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+func (ec *Client) Close() {
+	ec.c.Close()
+}
+
+// Governance
+
+// BudgetInfo returns the Treasury balance and budget proposals.
+func (ec *Client) BudgetInfo(ctx context.Context) (*energi_api.BudgetInfo, error) {
+	var res *energi_api.BudgetInfo
+	err := ec.c.CallContext(ctx, &res, "energi_budgetInfo")
+	return res, err
+}
+
+// UpgradeInfo returns upgrade proposals of all governed proxies.
+func (ec *Client) UpgradeInfo(ctx context.Context) (*energi_api.UpgradeProposals, error) {
+	var res *energi_api.UpgradeProposals
+	err := ec.c.CallContext(ctx, &res, "energi_upgradeInfo")
+	return res, err
+}
+
+// GenericProposalInfo returns the state of a single generic proposal.
+func (ec *Client) GenericProposalInfo(ctx context.Context, proposal common.Address) (*energi_api.GenericProposalInfo, error) {
+	var res *energi_api.GenericProposalInfo
+	err := ec.c.CallContext(ctx, &res, "energi_genericProposalInfo", proposal)
+	return res, err
+}
+
+// GenericProposals returns generic proposals created in the block range. If
+// a block number is nil, the latest known block is used.
+func (ec *Client) GenericProposals(ctx context.Context, fromBlock, toBlock *big.Int) ([]energi_api.GenericProposalInfo, error) {
+	var res []energi_api.GenericProposalInfo
+	err := ec.c.CallContext(ctx, &res, "energi_genericProposals",
+		toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return res, err
+}
+
+// TreasurySchedule returns the given number of upcoming superblocks with
+// projected payouts.
+func (ec *Client) TreasurySchedule(ctx context.Context, count uint64) (*energi_api.TreasurySchedule, error) {
+	var res *energi_api.TreasurySchedule
+	err := ec.c.CallContext(ctx, &res, "energi_treasurySchedule", count)
+	return res, err
+}
+
+// SubscribeProposalEvents subscribes to notifications about proposal
+// lifecycle events matching the criteria.
+func (ec *Client) SubscribeProposalEvents(
+	ctx context.Context,
+	crit *energi_api.ProposalEventCriteria,
+	ch chan<- *energi_api.ProposalEvent,
+) (ethereum.Subscription, error) {
+	if crit == nil {
+		crit = &energi_api.ProposalEventCriteria{}
+	}
+	return ec.c.Subscribe(ctx, "energi", ch, "proposalEvents", crit)
+}
+
+// Blacklist
+
+// BlacklistInfo returns blacklist proposals and their targets.
+func (ec *Client) BlacklistInfo(ctx context.Context) ([]energi_api.BLInfo, error) {
+	var res []energi_api.BLInfo
+	err := ec.c.CallContext(ctx, &res, "energi_blacklistInfo")
+	return res, err
+}
+
+// CompensationInfo returns the compensation fund balance and proposals.
+func (ec *Client) CompensationInfo(ctx context.Context) (*energi_api.BudgetInfo, error) {
+	var res *energi_api.BudgetInfo
+	err := ec.c.CallContext(ctx, &res, "energi_compensationInfo")
+	return res, err
+}
+
+// Checkpoints
+
+// CheckpointInfo returns checkpoints of the registry and the active ones.
+func (ec *Client) CheckpointInfo(ctx context.Context) (*energi_api.AllCheckpointInfo, error) {
+	var res *energi_api.AllCheckpointInfo
+	err := ec.c.CallContext(ctx, &res, "energi_checkpointInfo")
+	return res, err
+}
+
+// Sporks
+
+// SporkInfo returns the spork registry state and its upgrade proposals.
+func (ec *Client) SporkInfo(ctx context.Context) (*energi_api.SporkInfo, error) {
+	var res *energi_api.SporkInfo
+	err := ec.c.CallContext(ctx, &res, "energi_sporkInfo")
+	return res, err
+}
+
+// Migration
+
+// ListGen2Coins returns all unclaimed Gen 2 coins.
+func (ec *Client) ListGen2Coins(ctx context.Context) ([]energi_api.Gen2Coin, error) {
+	var res []energi_api.Gen2Coin
+	err := ec.c.CallContext(ctx, &res, "energi_listGen2Coins")
+	return res, err
+}
+
+// SearchGen2Coins returns Gen 2 coins of the given Gen 2 addresses.
+func (ec *Client) SearchGen2Coins(ctx context.Context, owners []string, includeEmpty bool) ([]energi_api.Gen2Coin, error) {
+	var res []energi_api.Gen2Coin
+	err := ec.c.CallContext(ctx, &res, "energi_searchGen2Coins", owners, includeEmpty)
+	return res, err
+}
+
+// SearchRawGen2Coins returns Gen 2 coins of the given raw owners.
+func (ec *Client) SearchRawGen2Coins(ctx context.Context, owners []common.Address, includeEmpty bool) ([]energi_api.Gen2Coin, error) {
+	var res []energi_api.Gen2Coin
+	err := ec.c.CallContext(ctx, &res, "energi_searchRawGen2Coins", owners, includeEmpty)
+	return res, err
+}
+
+// SearchGen3DestinationByGen2Address returns Gen 3 destinations of claimed
+// Gen 2 coins.
+func (ec *Client) SearchGen3DestinationByGen2Address(ctx context.Context, owners []string, includeEmpty bool) ([]energi_api.Gen3Dest, error) {
+	var res []energi_api.Gen3Dest
+	err := ec.c.CallContext(ctx, &res, "energi_searchGen3DestinationByGen2Address", owners, includeEmpty)
+	return res, err
+}
+
+// Staking
+
+// StakingHistory returns blocks staked by the address in the block range. If
+// a block number is nil, the latest known block is used.
+func (ec *Client) StakingHistory(ctx context.Context, address common.Address, fromBlock, toBlock *big.Int) (*energi_api.StakingHistory, error) {
+	var res *energi_api.StakingHistory
+	err := ec.c.CallContext(ctx, &res, "energi_stakingHistory",
+		address, toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return res, err
+}
+
+// StakingStatus returns the stake weight of local staking accounts.
+func (ec *Client) StakingStatus(ctx context.Context) (*energi_consensus.StakingStatusInfo, error) {
+	var res *energi_consensus.StakingStatusInfo
+	err := ec.c.CallContext(ctx, &res, "miner_stakingStatus")
+	return res, err
+}
+
+// StakingPrediction returns staking chances of local staking accounts.
+func (ec *Client) StakingPrediction(ctx context.Context) (*energi_consensus.StakingPredictionInfo, error) {
+	var res *energi_consensus.StakingPredictionInfo
+	err := ec.c.CallContext(ctx, &res, "miner_stakingPrediction")
+	return res, err
+}
+
+// Masternodes
+
+// CollateralBalance returns the masternode collateral of the owner and
+// the block it was last changed at.
+func (ec *Client) CollateralBalance(ctx context.Context, owner common.Address) (balance *big.Int, lastBlock *big.Int, err error) {
+	var res struct {
+		Balance   *hexutil.Big
+		LastBlock *hexutil.Big
+	}
+	if err = ec.c.CallContext(ctx, &res, "masternode_collateralBalance", owner); err != nil {
+		return nil, nil, err
+	}
+	return res.Balance.ToInt(), res.LastBlock.ToInt(), nil
+}
+
+// ListMasternodes returns all announced masternodes.
+func (ec *Client) ListMasternodes(ctx context.Context) ([]energi_api.MNInfo, error) {
+	var res []energi_api.MNInfo
+	err := ec.c.CallContext(ctx, &res, "masternode_listMasternodes")
+	return res, err
+}
+
+// MasternodeInfo returns a masternode by its owner or masternode address.
+func (ec *Client) MasternodeInfo(ctx context.Context, ownerOrMN common.Address) (*energi_api.MNInfo, error) {
+	var res *energi_api.MNInfo
+	err := ec.c.CallContext(ctx, &res, "masternode_masternodeInfo", ownerOrMN)
+	return res, err
+}
+
+// MasternodeStats returns masternode count and collateral totals.
+func (ec *Client) MasternodeStats(ctx context.Context) (*energi_api.MasternodeStats, error) {
+	var res *energi_api.MasternodeStats
+	err := ec.c.CallContext(ctx, &res, "masternode_stats")
+	return res, err
+}
+
+// PayoutQueue returns masternodes in the reward rotation order.
+func (ec *Client) PayoutQueue(ctx context.Context) ([]energi_api.MNPayoutInfo, error) {
+	var res []energi_api.MNPayoutInfo
+	err := ec.c.CallContext(ctx, &res, "masternode_payoutQueue")
+	return res, err
+}
+
+// PayoutInfo returns the next payout estimate of a masternode.
+func (ec *Client) PayoutInfo(ctx context.Context, ownerOrMN common.Address) (*energi_api.MNPayoutInfo, error) {
+	var res *energi_api.MNPayoutInfo
+	err := ec.c.CallContext(ctx, &res, "masternode_payoutInfo", ownerOrMN)
+	return res, err
+}
+
+// VotingRecord returns votes of a masternode in the block range. If a block
+// number is nil, the latest known block is used.
+func (ec *Client) VotingRecord(ctx context.Context, ownerOrMN common.Address, fromBlock, toBlock *big.Int) (*energi_api.MNVotingRecord, error) {
+	var res *energi_api.MNVotingRecord
+	err := ec.c.CallContext(ctx, &res, "masternode_votingRecord",
+		ownerOrMN, toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return res, err
+}
+
+// Participation returns the voting participation of a masternode in
+// the block range. If a block number is nil, the latest known block is used.
+func (ec *Client) Participation(ctx context.Context, ownerOrMN common.Address, fromBlock, toBlock *big.Int) (*energi_api.MNParticipation, error) {
+	var res *energi_api.MNParticipation
+	err := ec.c.CallContext(ctx, &res, "masternode_participation",
+		ownerOrMN, toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return res, err
+}
+
+// MissedCheckpoints returns checkpoints the masternode has not signed in
+// the block range. If a block number is nil, the latest known block is used.
+func (ec *Client) MissedCheckpoints(ctx context.Context, ownerOrMN common.Address, fromBlock, toBlock *big.Int) ([]energi_api.MNMissedCheckpoint, error) {
+	var res []energi_api.MNMissedCheckpoint
+	err := ec.c.CallContext(ctx, &res, "masternode_missedCheckpoints",
+		ownerOrMN, toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return res, err
+}
+
+// Contract calls
+
+// OverrideAccount specifies the state of an account to be used during a call
+// with overrides. State replaces the entire storage, while StateDiff patches
+// individual slots. ProxyImpl points a GovernedProxy to another
+// implementation.
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      []byte
+	Balance   *big.Int
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+	ProxyImpl *common.Address
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a OverrideAccount) MarshalJSON() ([]byte, error) {
+	type acc struct {
+		Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+		Code      *hexutil.Bytes              `json:"code,omitempty"`
+		Balance   *hexutil.Big                `json:"balance,omitempty"`
+		State     map[common.Hash]common.Hash `json:"state,omitempty"`
+		StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+		ProxyImpl *common.Address             `json:"proxyImpl,omitempty"`
+	}
+
+	output := acc{
+		Nonce:     (*hexutil.Uint64)(a.Nonce),
+		Balance:   (*hexutil.Big)(a.Balance),
+		State:     a.State,
+		StateDiff: a.StateDiff,
+		ProxyImpl: a.ProxyImpl,
+	}
+	if a.Code != nil {
+		output.Code = (*hexutil.Bytes)(&a.Code)
+	}
+	return json.Marshal(&output)
+}
+
+// BlockOverrides specifies header fields to be used during a call with
+// overrides.
+type BlockOverrides struct {
+	Number    *big.Int
+	Timestamp *uint64
+	Coinbase  *common.Address
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o BlockOverrides) MarshalJSON() ([]byte, error) {
+	type override struct {
+		Number    *hexutil.Big    `json:"number,omitempty"`
+		Timestamp *hexutil.Uint64 `json:"timestamp,omitempty"`
+		Coinbase  *common.Address `json:"coinbase,omitempty"`
+	}
+
+	return json.Marshal(&override{
+		Number:    (*hexutil.Big)(o.Number),
+		Timestamp: (*hexutil.Uint64)(o.Timestamp),
+		Coinbase:  o.Coinbase,
+	})
+}
+
+// CallContract executes a message call on top of the block with the accounts
+// and the header fields overridden. If the block number is nil, the latest
+// known block is used.
+func (ec *Client) CallContract(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+	overrides map[common.Address]OverrideAccount,
+	blockOverrides *BlockOverrides,
+) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := ec.c.CallContext(ctx, &hex, "eth_call",
+		toCallArg(msg), toBlockNumArg(blockNumber), overrides, blockOverrides)
+	return hex, err
+}
+
+// EstimateGas estimates the gas of a message call on top of the pending
+// block with the accounts and the header fields overridden.
+func (ec *Client) EstimateGas(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	overrides map[common.Address]OverrideAccount,
+	blockOverrides *BlockOverrides,
+) (uint64, error) {
+	var hex hexutil.Uint64
+	err := ec.c.CallContext(ctx, &hex, "eth_estimateGas",
+		toCallArg(msg), overrides, blockOverrides)
+	return uint64(hex), err
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
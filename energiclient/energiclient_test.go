@@ -0,0 +1,333 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package energiclient
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"energi.world/core/gen3"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/eth"
+	"energi.world/core/gen3/node"
+	"energi.world/core/gen3/p2p"
+
+	energi_api "energi.world/core/gen3/energi/api"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+// newTestNode starts an in-process node on top of the testnet genesis.
+// The caller must remove its data dir once the node is stopped.
+func newTestNode(t *testing.T) (*node.Node, *Client) {
+	// Keeps the txpool journal and keystore out of the package dir
+	datadir, err := ioutil.TempDir("", "energiclient")
+	if err != nil {
+		t.Fatalf("can't create data dir: %v", err)
+	}
+
+	stack, err := node.New(&node.Config{
+		DataDir: datadir,
+		P2P: p2p.Config{
+			NoDiscovery: true,
+			MaxPeers:    0,
+		},
+		NoUSB:             true,
+		UseLightweightKDF: true,
+	})
+	if err != nil {
+		os.RemoveAll(datadir)
+		t.Fatalf("can't create node: %v", err)
+	}
+
+	config := eth.DefaultConfig
+	config.Genesis = core.DefaultEnergiTestnetGenesisBlock()
+	config.Genesis.Alloc = core.GenesisAlloc{}
+
+	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return eth.New(ctx, &config)
+	})
+	if err != nil {
+		os.RemoveAll(datadir)
+		t.Fatalf("can't register service: %v", err)
+	}
+
+	if err = stack.Start(); err != nil {
+		os.RemoveAll(datadir)
+		t.Fatalf("can't start node: %v", err)
+	}
+
+	rpcClient, err := stack.Attach()
+	if err != nil {
+		stack.Stop()
+		os.RemoveAll(datadir)
+		t.Fatalf("can't attach: %v", err)
+	}
+
+	return stack, NewClient(rpcClient)
+}
+
+func TestClient(t *testing.T) {
+	stack, client := newTestNode(t)
+	defer os.RemoveAll(stack.DataDir())
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mnlist, err := client.ListMasternodes(ctx)
+	if err != nil {
+		t.Fatalf("ListMasternodes: %v", err)
+	}
+	if len(mnlist) != 0 {
+		t.Errorf("unexpected masternodes: %v", mnlist)
+	}
+
+	stats, err := client.MasternodeStats(ctx)
+	if err != nil {
+		t.Fatalf("MasternodeStats: %v", err)
+	}
+	if stats.Total != 0 || stats.TotalCollateral.ToInt().Sign() != 0 {
+		t.Errorf("unexpected masternode stats: %v", stats)
+	}
+
+	balance, _, err := client.CollateralBalance(ctx, common.HexToAddress("0x1"))
+	if err != nil {
+		t.Fatalf("CollateralBalance: %v", err)
+	}
+	if balance.Sign() != 0 {
+		t.Errorf("unexpected collateral: %v", balance)
+	}
+
+	budget, err := client.BudgetInfo(ctx)
+	if err != nil {
+		t.Fatalf("BudgetInfo: %v", err)
+	}
+	if budget.Balance == nil || len(budget.Proposals) != 0 {
+		t.Errorf("unexpected budget: %v", budget)
+	}
+
+	schedule, err := client.TreasurySchedule(ctx, 3)
+	if err != nil {
+		t.Fatalf("TreasurySchedule: %v", err)
+	}
+	if schedule.Cycle == 0 || len(schedule.Superblocks) != 3 {
+		t.Fatalf("unexpected treasury schedule: %v", schedule)
+	}
+	if schedule.NextBlock.Number != schedule.Height+1 {
+		t.Errorf("unexpected next payout block: %v", schedule.NextBlock.Number)
+	}
+	for i, sb := range schedule.Superblocks {
+		if sb.Number != uint64(i+1)*schedule.Cycle {
+			t.Errorf("unexpected superblock %d: %v", i, sb.Number)
+		}
+		if sb.Reward == nil || sb.Reward.ToInt().Sign() <= 0 {
+			t.Errorf("unexpected superblock %d reward: %v", i, sb.Reward)
+		}
+	}
+
+	if _, err = client.UpgradeInfo(ctx); err != nil {
+		t.Fatalf("UpgradeInfo: %v", err)
+	}
+
+	if _, err = client.BlacklistInfo(ctx); err != nil {
+		t.Fatalf("BlacklistInfo: %v", err)
+	}
+
+	if _, err = client.CheckpointInfo(ctx); err != nil {
+		t.Fatalf("CheckpointInfo: %v", err)
+	}
+
+	spork, err := client.SporkInfo(ctx)
+	if err != nil {
+		t.Fatalf("SporkInfo: %v", err)
+	}
+	if spork.Proxy != energi_params.Energi_SporkRegistry {
+		t.Errorf("unexpected spork proxy: %v", spork.Proxy.Hex())
+	}
+
+	status, err := client.StakingStatus(ctx)
+	if err != nil {
+		t.Fatalf("StakingStatus: %v", err)
+	}
+	if status.Height != 0 || status.Staking {
+		t.Errorf("unexpected staking status: %v", status)
+	}
+
+	history, err := client.StakingHistory(ctx, common.HexToAddress("0x1"), common.Big0, nil)
+	if err != nil {
+		t.Fatalf("StakingHistory: %v", err)
+	}
+	if history.ToBlock != 0 || len(history.Blocks) != 0 {
+		t.Errorf("unexpected staking history: %v", history)
+	}
+}
+
+func TestCallContractOverrides(t *testing.T) {
+	stack, client := newTestNode(t)
+	defer os.RemoveAll(stack.DataDir())
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var (
+		proxy    = common.HexToAddress("0x1000")
+		impl     = common.HexToAddress("0x2000")
+		header   = common.HexToAddress("0x3000")
+		coinbase = common.HexToAddress("0x4000")
+
+		// return(sload(calldataload(0)))
+		sloadCode = common.FromHex("0x6000355460005260206000f3")
+		// return(coinbase, timestamp, number)
+		headerCode = common.FromHex("0x41600052426020524360405260606000f3")
+	)
+
+	slot := func(key common.Hash) ethereum.CallMsg {
+		return ethereum.CallMsg{To: &proxy, Data: key.Bytes()}
+	}
+
+	// Storage diff on top of the existing state
+	res, err := client.CallContract(ctx, slot(common.HexToHash("0x5")), nil,
+		map[common.Address]OverrideAccount{
+			proxy: {
+				Code:      sloadCode,
+				StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x5"): common.HexToHash("0x55")},
+			},
+		}, nil)
+	if err != nil {
+		t.Fatalf("CallContract: %v", err)
+	}
+	if have := common.BytesToHash(res); have != common.HexToHash("0x55") {
+		t.Errorf("unexpected storage: %v", have.Hex())
+	}
+
+	// GovernedProxy implementation slot
+	res, err = client.CallContract(ctx, slot(common.HexToHash("0x1")), nil,
+		map[common.Address]OverrideAccount{
+			proxy: {Code: sloadCode},
+			impl:  {Code: sloadCode},
+		}, nil)
+	if err != nil {
+		t.Fatalf("CallContract: %v", err)
+	}
+	if have := common.BytesToHash(res); have != (common.Hash{}) {
+		t.Errorf("unexpected implementation: %v", have.Hex())
+	}
+
+	res, err = client.CallContract(ctx, slot(common.HexToHash("0x1")), nil,
+		map[common.Address]OverrideAccount{
+			proxy: {Code: sloadCode, ProxyImpl: &impl},
+			impl:  {Code: sloadCode},
+		}, nil)
+	if err != nil {
+		t.Fatalf("CallContract: %v", err)
+	}
+	if have := common.BytesToAddress(res); have != impl {
+		t.Errorf("unexpected implementation: %v", have.Hex())
+	}
+
+	if _, err = client.CallContract(ctx, slot(common.Hash{}), nil,
+		map[common.Address]OverrideAccount{
+			proxy: {Code: sloadCode, ProxyImpl: &impl},
+		}, nil); err == nil {
+		t.Errorf("implementation without code accepted")
+	}
+
+	if _, err = client.CallContract(ctx, slot(common.Hash{}), nil,
+		map[common.Address]OverrideAccount{
+			proxy: {
+				Code:      sloadCode,
+				State:     map[common.Hash]common.Hash{common.HexToHash("0x5"): common.HexToHash("0x55")},
+				StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x6"): common.HexToHash("0x66")},
+			},
+		}, nil); err == nil {
+		t.Errorf("both state and stateDiff accepted")
+	}
+
+	// Header fields
+	timestamp := uint64(1234)
+	res, err = client.CallContract(ctx, ethereum.CallMsg{To: &header}, nil,
+		map[common.Address]OverrideAccount{
+			header: {Code: headerCode},
+		}, &BlockOverrides{
+			Number:    big.NewInt(77),
+			Timestamp: &timestamp,
+			Coinbase:  &coinbase,
+		})
+	if err != nil {
+		t.Fatalf("CallContract: %v", err)
+	}
+	if len(res) != 96 {
+		t.Fatalf("unexpected result: %x", res)
+	}
+	if have := common.BytesToAddress(res[:32]); have != coinbase {
+		t.Errorf("unexpected coinbase: %v", have.Hex())
+	}
+	if have := new(big.Int).SetBytes(res[32:64]).Uint64(); have != timestamp {
+		t.Errorf("unexpected timestamp: %v", have)
+	}
+	if have := new(big.Int).SetBytes(res[64:]).Uint64(); have != 77 {
+		t.Errorf("unexpected number: %v", have)
+	}
+
+	// Gas estimation with the overridden code
+	gas, err := client.EstimateGas(ctx, slot(common.HexToHash("0x5")),
+		map[common.Address]OverrideAccount{
+			proxy: {Code: sloadCode},
+		}, nil)
+	if err != nil {
+		t.Fatalf("EstimateGas: %v", err)
+	}
+	if gas <= 21000 {
+		t.Errorf("unexpected gas: %v", gas)
+	}
+}
+
+func TestSubscribeProposalEvents(t *testing.T) {
+	stack, client := newTestNode(t)
+	defer os.RemoveAll(stack.DataDir())
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	events := make(chan *energi_api.ProposalEvent)
+	sub, err := client.SubscribeProposalEvents(ctx, &energi_api.ProposalEventCriteria{
+		Types: []string{energi_api.ProposalCreated},
+	}, events)
+	if err != nil {
+		t.Fatalf("SubscribeProposalEvents: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected event: %v", ev)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sub.Unsubscribe()
+}
@@ -18,6 +18,7 @@
 package eth
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -26,6 +27,7 @@ import (
 	"math/big"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,6 +38,7 @@ import (
 	"energi.world/core/gen3/core/state"
 	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/internal/ethapi"
+	"energi.world/core/gen3/miner"
 	"energi.world/core/gen3/params"
 	"energi.world/core/gen3/rlp"
 	"energi.world/core/gen3/rpc"
@@ -183,6 +186,84 @@ func (api *PrivateMinerAPI) SetAutocollateralize(mode *uint64) (old uint64, err
 	return
 }
 
+// AutocollateralPolicyArgs is the RPC form of miner.AutocollateralPolicy.
+type AutocollateralPolicyArgs struct {
+	Account   common.Address `json:"account"`
+	Target    *hexutil.Big   `json:"target"`
+	Reserve   *hexutil.Big   `json:"reserve"`
+	Schedule  string         `json:"schedule"`
+	Threshold *hexutil.Big   `json:"threshold"`
+	DryRun    bool           `json:"dryRun"`
+}
+
+// AutocollateralAction is the RPC form of miner.AutocollateralAction.
+type AutocollateralAction struct {
+	Time    uint64         `json:"time"`
+	Block   uint64         `json:"block"`
+	Account common.Address `json:"account"`
+	Amount  *hexutil.Big   `json:"amount"`
+	TxHash  common.Hash    `json:"txHash"`
+	DryRun  bool           `json:"dryRun"`
+}
+
+// SetAutocollateralPolicy adds or replaces per account autocollateral policy
+// which overrides the global mode.
+func (api *PrivateMinerAPI) SetAutocollateralPolicy(args AutocollateralPolicyArgs) error {
+	return api.e.Miner().SetAutocollateralPolicy(miner.AutocollateralPolicy{
+		Account:   args.Account,
+		Target:    args.Target.ToInt(),
+		Reserve:   args.Reserve.ToInt(),
+		Schedule:  args.Schedule,
+		Threshold: args.Threshold.ToInt(),
+		DryRun:    args.DryRun,
+	})
+}
+
+// RemoveAutocollateralPolicy removes per account autocollateral policy.
+func (api *PrivateMinerAPI) RemoveAutocollateralPolicy(account common.Address) bool {
+	return api.e.Miner().RemoveAutocollateralPolicy(account)
+}
+
+// AutocollateralPolicies returns all per account autocollateral policies.
+func (api *PrivateMinerAPI) AutocollateralPolicies() []AutocollateralPolicyArgs {
+	policies := api.e.Miner().AutocollateralPolicies()
+	sort.Slice(policies, func(i, j int) bool {
+		return bytes.Compare(policies[i].Account[:], policies[j].Account[:]) < 0
+	})
+
+	res := make([]AutocollateralPolicyArgs, 0, len(policies))
+	for _, p := range policies {
+		res = append(res, AutocollateralPolicyArgs{
+			Account:   p.Account,
+			Target:    (*hexutil.Big)(p.Target),
+			Reserve:   (*hexutil.Big)(p.Reserve),
+			Schedule:  p.Schedule,
+			Threshold: (*hexutil.Big)(p.Threshold),
+			DryRun:    p.DryRun,
+		})
+	}
+	return res
+}
+
+// AutocollateralHistory returns recent autocollateral deposits and dry-run
+// actions, optionally only of the given account.
+func (api *PrivateMinerAPI) AutocollateralHistory(account *common.Address) []AutocollateralAction {
+	history := api.e.Miner().AutocollateralHistory(account)
+
+	res := make([]AutocollateralAction, 0, len(history))
+	for _, a := range history {
+		res = append(res, AutocollateralAction{
+			Time:    a.Time,
+			Block:   a.Block,
+			Account: a.Account,
+			Amount:  (*hexutil.Big)(a.Amount),
+			TxHash:  a.TxHash,
+			DryRun:  a.DryRun,
+		})
+	}
+	return res
+}
+
 // PrivateAdminAPI is the collection of Ethereum full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
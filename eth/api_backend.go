@@ -20,11 +20,11 @@ package eth
 import (
 	"context"
 	"math/big"
-	"time"
 
 	"energi.world/core/gen3/accounts"
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/common/math"
+	"energi.world/core/gen3/consensus"
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/core/bloombits"
 	"energi.world/core/gen3/core/state"
@@ -34,7 +34,7 @@ import (
 	"energi.world/core/gen3/eth/gasprice"
 	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/event"
-	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/internal/ethapi"
 	"energi.world/core/gen3/params"
 	"energi.world/core/gen3/rpc"
 )
@@ -58,6 +58,10 @@ func (b *EthAPIBackend) BlockChain() *core.BlockChain {
 	return b.eth.blockchain
 }
 
+func (b *EthAPIBackend) Engine() consensus.Engine {
+	return b.eth.engine
+}
+
 func (b *EthAPIBackend) SetHead(number uint64) {
 	b.eth.protocolManager.downloader.Cancel()
 	b.eth.blockchain.SetHead(number)
@@ -275,38 +279,10 @@ func (b *EthAPIBackend) IsPublicService() bool {
 	return b.eth.config.PublicService
 }
 
-const (
-	syncedHeadToleranceDuration = time.Second * 60
-	syncedHeadChanSize          = 16
-)
-
 func (b *EthAPIBackend) OnSyncedHeadUpdates(cb func()) {
 	if !b.IsPublicService() {
 		return
 	}
 
-	go func() {
-		chainHeadCh := make(chan core.ChainHeadEvent, syncedHeadChanSize)
-		headSub := b.SubscribeChainHeadEvent(chainHeadCh)
-		defer headSub.Unsubscribe()
-
-		for {
-			select {
-			case ev := <-chainHeadCh:
-				blockTime := time.Unix(int64(ev.Block.Time()), 0)
-				timeNow := time.Now().UTC()
-
-				if blockTime.After(timeNow.Add(-syncedHeadToleranceDuration)) {
-					log.Debug("Firing OnSyncedHead")
-					go cb()
-				}
-
-				break
-
-			// Shutdown
-			case <-headSub.Err():
-				return
-			}
-		}
-	}()
+	ethapi.OnSyncedHeadUpdates(b, cb)
 }
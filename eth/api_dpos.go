@@ -0,0 +1,184 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"energi.world/core/gen3/accounts"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/log"
+
+	energi "energi.world/core/gen3/energi/consensus"
+)
+
+// DPoSPool is the delegated PoS state of a contract, which allows another
+// address to stake on behalf of it.
+type DPoSPool struct {
+	Contract   common.Address
+	Signer     common.Address  // as reported by signerAddress()
+	Configured *common.Address // signer the node stakes with, if any
+	SignerKey  bool            // signer is unlocked for staking locally
+	Staking    bool            // the node stakes on behalf of the contract
+	Balance    *hexutil.Big
+	Weight     uint64
+	Error      string `json:",omitempty"`
+}
+
+// PublicDPoSAPI provides delegated PoS information of the chain.
+type PublicDPoSAPI struct {
+	e *Ethereum
+}
+
+// NewPublicDPoSAPI creates a new delegated PoS information API.
+func NewPublicDPoSAPI(e *Ethereum) *PublicDPoSAPI {
+	return &PublicDPoSAPI{e}
+}
+
+// DposSigner returns the address allowed to stake on behalf of the contract.
+func (api *PublicDPoSAPI) DposSigner(contract common.Address) (common.Address, error) {
+	engine, ok := api.e.engine.(*energi.Energi)
+	if !ok {
+		return common.Address{}, errors.New("Delegated PoS is not supported")
+	}
+
+	chain := api.e.BlockChain()
+	return engine.DPoSSigner(chain, chain.CurrentHeader(), contract)
+}
+
+// DposPool returns the signer and the stake weight of the contract.
+func (api *PublicDPoSAPI) DposPool(contract common.Address) (*DPoSPool, error) {
+	return api.e.dposPool(contract, nil)
+}
+
+// PrivateDPoSAPI provides management of staking on behalf of delegated PoS
+// contracts.
+type PrivateDPoSAPI struct {
+	e *Ethereum
+}
+
+// NewPrivateDPoSAPI creates a new delegated PoS management API.
+func NewPrivateDPoSAPI(e *Ethereum) *PrivateDPoSAPI {
+	return &PrivateDPoSAPI{e}
+}
+
+// DposPools returns all contracts the node is configured to stake for.
+func (api *PrivateDPoSAPI) DposPools() ([]*DPoSPool, error) {
+	dpos := api.e.DPoS()
+
+	res := make([]*DPoSPool, 0, len(dpos))
+	for contract, signer := range dpos {
+		signer := signer
+		pool, err := api.e.dposPool(contract, &signer)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pool)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i].Contract[:], res[j].Contract[:]) < 0
+	})
+
+	return res, nil
+}
+
+// DposAdd configures the node to stake on behalf of the contract. The signer
+// defaults to the one reported by the contract and must match it otherwise.
+func (api *PrivateDPoSAPI) DposAdd(contract common.Address, signer *common.Address) (*DPoSPool, error) {
+	pool, err := api.e.dposPool(contract, signer)
+	if err != nil {
+		return nil, err
+	}
+	if pool.Error != "" {
+		return nil, errors.New(pool.Error)
+	}
+
+	if signer != nil && *signer != pool.Signer {
+		return nil, errors.New("Signer does not match the contract")
+	}
+
+	if !pool.SignerKey {
+		log.Warn("DPoS signer is not unlocked for staking",
+			"contract", contract, "signer", pool.Signer)
+	}
+
+	api.e.AddDPoS(contract, pool.Signer)
+
+	return api.e.dposPool(contract, &pool.Signer)
+}
+
+// DposRemove stops staking on behalf of the contract.
+func (api *PrivateDPoSAPI) DposRemove(contract common.Address) bool {
+	if _, ok := api.e.DPoS()[contract]; !ok {
+		return false
+	}
+
+	api.e.RemoveDPoS(contract)
+	return true
+}
+
+// dposPool looks up the delegated PoS state of the contract on top of
+// the current block.
+func (s *Ethereum) dposPool(contract common.Address, configured *common.Address) (*DPoSPool, error) {
+	engine, ok := s.engine.(*energi.Energi)
+	if !ok {
+		return nil, errors.New("Delegated PoS is not supported")
+	}
+
+	chain := s.BlockChain()
+	head := chain.CurrentHeader()
+
+	state, err := chain.StateAt(head.Root)
+	if err != nil {
+		log.Error("Failed", "err", err)
+		return nil, err
+	}
+
+	pool := &DPoSPool{
+		Contract:   contract,
+		Configured: configured,
+		Balance:    (*hexutil.Big)(state.GetBalance(contract)),
+	}
+
+	if signer, err := engine.DPoSSigner(chain, head, contract); err == nil {
+		pool.Signer = signer
+	} else {
+		pool.Error = err.Error()
+	}
+
+	if weight, err := engine.StakeWeight(chain, head, contract); err == nil {
+		pool.Weight = weight
+	} else if pool.Error == "" {
+		pool.Error = err.Error()
+	}
+
+	if pool.Signer != (common.Address{}) {
+		account := accounts.Account{Address: pool.Signer}
+		if wallet, err := s.accountManager.Find(account); err == nil {
+			pool.SignerKey = wallet.IsUnlockedForStaking(account)
+		}
+	}
+
+	pool.Staking = pool.Configured != nil && *pool.Configured == pool.Signer &&
+		pool.SignerKey && pool.Weight > 0 && s.IsMining()
+
+	return pool, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/consensus/ethash"
+)
+
+func TestPrivateDPoSAPI(t *testing.T) {
+	contract := common.HexToAddress("0x1234")
+	signer := common.HexToAddress("0x5678")
+
+	s := &Ethereum{
+		engine: ethash.NewFaker(),
+		dpos:   make(DPoSMap),
+	}
+	api := NewPrivateDPoSAPI(s)
+
+	pools, err := api.DposPools()
+	assert.Empty(t, err)
+	assert.Empty(t, pools)
+
+	// Non-Energi engines do not support delegated PoS
+	_, err = api.DposAdd(contract, &signer)
+	assert.Equal(t, "Delegated PoS is not supported", err.Error())
+	assert.Empty(t, s.DPoS())
+
+	s.AddDPoS(contract, signer)
+	_, err = api.DposPools()
+	assert.Equal(t, "Delegated PoS is not supported", err.Error())
+
+	assert.False(t, api.DposRemove(signer))
+	assert.Equal(t, DPoSMap{contract: signer}, s.DPoS())
+
+	assert.True(t, api.DposRemove(contract))
+	assert.Empty(t, s.DPoS())
+	assert.False(t, api.DposRemove(contract))
+}
@@ -61,9 +61,10 @@ const (
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
-	Tracer  *string
-	Timeout *string
-	Reexec  *uint64
+	Tracer   *string
+	Timeout  *string
+	Reexec   *uint64
+	Finalize *bool // trace the finalize steps instead of consensus transactions
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -75,6 +76,7 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
+	Step   string      `json:"step,omitempty"`   // Finalize step of the traced call, if any
 	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
 	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
 }
@@ -207,8 +209,16 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			for task := range tasks {
 				signer := types.MakeSigner(api.config, task.block.Number())
 
+				// Consensus transactions get replaced by the finalize steps
+				txs := task.block.Transactions()
+				if isFinalizeTrace(config) {
+					txs = userTransactions(txs)
+					task.results = task.results[:len(txs)]
+				}
+
 				// Trace all the transactions contained within
-				for i, tx := range task.block.Transactions() {
+				failed := false
+				for i, tx := range txs {
 					// Consensus - always the last
 					signer = api.handleConsensusTx(statedb, tx, signer)
 
@@ -219,12 +229,22 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					if err != nil {
 						task.results[i] = &txTraceResult{Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
+						failed = true
 						break
 					}
 					// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 					task.statedb.Finalise(api.eth.blockchain.Config().IsEIP158(task.block.Number()))
 					task.results[i] = &txTraceResult{Result: res}
 				}
+				if isFinalizeTrace(config) && !failed {
+					steps, err := api.traceFinalize(ctx, task.block, task.statedb, txs, config)
+					if err != nil {
+						task.results = append(task.results, &txTraceResult{Error: err.Error()})
+						log.Warn("Finalize tracing failed", "block", task.block.NumberU64(), "err", err)
+					} else {
+						task.results = append(task.results, steps...)
+					}
+				}
 				// Stream the result back to the user or abort on teardown
 				select {
 				case results <- task:
@@ -470,11 +490,18 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		signer = types.MakeSigner(api.config, block.Number())
 
 		txs     = block.Transactions()
-		results = make([]*txTraceResult, len(txs))
+		results []*txTraceResult
 
 		pend = new(sync.WaitGroup)
-		jobs = make(chan *txTraceTask, len(txs))
+		jobs chan *txTraceTask
 	)
+	// Consensus transactions get replaced by the finalize steps
+	if isFinalizeTrace(config) {
+		txs = userTransactions(txs)
+	}
+	results = make([]*txTraceResult, len(txs))
+	jobs = make(chan *txTraceTask, len(txs))
+
 	threads := runtime.NumCPU()
 	if threads > len(txs) {
 		threads = len(txs)
@@ -527,6 +554,13 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if failed != nil {
 		return nil, failed
 	}
+	if isFinalizeTrace(config) {
+		steps, err := api.traceFinalize(ctx, block, statedb, txs, config)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, steps...)
+	}
 	return results, nil
 }
 
@@ -728,6 +762,32 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
 	// Assemble the structured logger or the JavaScript tracer
+	tracer, cancel, err := newTracer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	// Run the transaction with tracing enabled.
+	vmenv := vm.NewEVM(vmctx, statedb, api.config, vm.Config{Debug: true, Tracer: tracer})
+
+	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		// Prevent memory leak in C code
+		switch tracer := tracer.(type) {
+		case *tracers.Tracer:
+			tracer.GetResult()
+		}
+
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	return traceResult(tracer, ret, gas, failed)
+}
+
+// newTracer assembles the structured logger or the JavaScript tracer according
+// to the provided configuration. The returned function releases the timeout
+// of the tracer.
+func newTracer(ctx context.Context, config *TraceConfig) (vm.Tracer, context.CancelFunc, error) {
 	var (
 		tracer vm.Tracer
 		err    error
@@ -739,12 +799,12 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		timeout := defaultTraceTimeout
 		if config.Timeout != nil {
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 		// Constuct the JavaScript tracer to execute with
 		if tracer, err = tracers.New(*config.Tracer); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -752,7 +812,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			<-deadlineCtx.Done()
 			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
 		}()
-		defer cancel()
+		return tracer, cancel, nil
 
 	case config == nil:
 		tracer = vm.NewStructLogger(&vm.LogConfig{
@@ -764,20 +824,11 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, api.config, vm.Config{Debug: true, Tracer: tracer})
-
-	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
-	if err != nil {
-		// Prevent memory leak in C code
-		switch tracer := tracer.(type) {
-		case *tracers.Tracer:
-			tracer.GetResult()
-		}
+	return tracer, func() {}, nil
+}
 
-		return nil, fmt.Errorf("tracing failed: %v", err)
-	}
-	// Depending on the tracer type, format and return the output
+// traceResult formats the output of the tracer depending on its type.
+func traceResult(tracer vm.Tracer, ret []byte, gas uint64, failed bool) (interface{}, error) {
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
 		return &ethapi.ExecutionResult{
@@ -86,6 +86,12 @@ type Ethereum struct {
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
 
+	stakingRewards *stakingRewards    // Block reward split calculator for the staking history
+	stakingIndexer *core.ChainIndexer // Staking history indexer operating during block imports
+
+	voteParser  *voteParser        // Masternode vote extractor for the vote index
+	voteIndexer *core.ChainIndexer // Masternode vote indexer operating during block imports
+
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
@@ -119,7 +125,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		config.MinerGasPrice = new(big.Int).Set(DefaultConfig.MinerGasPrice)
 	}
 	// Assemble the Ethereum object
-	chainDb, err := CreateDB(ctx, config, "chaindata")
+	chainDb, err := ctx.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +174,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = eth.blockchain.SetCheckpointPolicy(config.CheckpointPolicy, config.CheckpointQuorum); err != nil {
+		return nil, err
+	}
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -176,6 +185,18 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	if eth.stakingRewards, err = newStakingRewards(eth.blockchain); err != nil {
+		return nil, err
+	}
+	eth.stakingIndexer = NewStakingIndexer(chainDb, eth.stakingRewards)
+	eth.stakingIndexer.Start(eth.blockchain)
+
+	if eth.voteParser, err = newVoteParser(eth.chainConfig); err != nil {
+		return nil, err
+	}
+	eth.voteIndexer = NewVoteIndexer(chainDb, eth.voteParser)
+	eth.voteIndexer.Start(eth.blockchain)
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
@@ -206,6 +227,11 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 
 	eth.miner.SetEthAPIBackend(eth.APIBackend)
 	eth.miner.SetMinerAutocollateral(config.MinerAutocollateral)
+	for _, policy := range config.MinerAutocollateralPolicies {
+		if err := eth.miner.SetAutocollateralPolicy(policy); err != nil {
+			return nil, err
+		}
+	}
 
 	if energi, ok := eth.engine.(*energi.Energi); ok {
 		energi.SetMinerCB(
@@ -229,18 +255,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 				return res
 			},
 			func(addr common.Address, hash []byte) ([]byte, error) {
-				// TODO: revise how locking affects performance
-				eth.lock.RLock()
-				if signer, ok := eth.dpos[addr]; ok {
-					addr = signer
-				}
-				eth.lock.RUnlock()
-
-				account := accounts.Account{Address: addr}
-				wallet, err := eth.accountManager.Find(account)
-				if wallet == nil || err != nil {
-					log.Error("Account unavailable locally", "err", err)
-					return nil, fmt.Errorf("signer missing: %v", err)
+				account, wallet, err := eth.stakingWallet(addr)
+				if err != nil {
+					return nil, err
 				}
 				return wallet.SignHash(account, hash)
 			},
@@ -251,11 +268,44 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 				return eth.IsMining()
 			},
 		)
+		energi.SetHeaderSignerCB(
+			func(addr common.Address, header *types.Header) ([]byte, error) {
+				account, wallet, err := eth.stakingWallet(addr)
+				if err != nil {
+					return nil, err
+				}
+
+				// External signers sign only what they are able to verify
+				if signer, ok := wallet.(accounts.BlockSigner); ok {
+					return signer.SignBlock(account, header)
+				}
+				return wallet.SignHash(account, energi.SignatureHash(header).Bytes())
+			},
+		)
 	}
 
 	return eth, nil
 }
 
+// stakingWallet resolves the wallet holding the key of the given staking
+// address, considering delegated PoS.
+func (s *Ethereum) stakingWallet(addr common.Address) (accounts.Account, accounts.Wallet, error) {
+	// TODO: revise how locking affects performance
+	s.lock.RLock()
+	if signer, ok := s.dpos[addr]; ok {
+		addr = signer
+	}
+	s.lock.RUnlock()
+
+	account := accounts.Account{Address: addr}
+	wallet, err := s.accountManager.Find(account)
+	if wallet == nil || err != nil {
+		log.Error("Account unavailable locally", "err", err)
+		return account, nil, fmt.Errorf("signer missing: %v", err)
+	}
+	return account, wallet, nil
+}
+
 func makeExtraData(extra []byte) []byte {
 	if len(extra) == 0 {
 		// create default extradata
@@ -401,6 +451,18 @@ func (s *Ethereum) APIs() []rpc.API {
 			Service:   energi_api.NewGovernanceAPI(s.APIBackend),
 			Public:    true,
 		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewSporkAPI(s.APIBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewStakingAPI(s.APIBackend),
+			Public:    true,
+		},
 		{
 			Namespace: "energi",
 			Version:   "1.0",
@@ -418,6 +480,18 @@ func (s *Ethereum) APIs() []rpc.API {
 			Service:   energi_api.NewMasternodeAPI(s.APIBackend),
 			Public:    true,
 		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   NewPublicDPoSAPI(s),
+			Public:    true,
+		},
+		{
+			// Changes the staking setup of the node, like SetAutocollateralize
+			Namespace: "miner",
+			Version:   "1.0",
+			Service:   NewPrivateDPoSAPI(s),
+		},
 	}...)
 
 	// Rename a copy of eth to nrg
@@ -545,6 +619,18 @@ func (s *Ethereum) RemoveDPoS(contract common.Address) {
 	s.lock.Unlock()
 }
 
+// DPoS returns a copy of contract to signer pairs for delegated PoS
+func (s *Ethereum) DPoS() DPoSMap {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	res := make(DPoSMap, len(s.dpos))
+	for contract, signer := range s.dpos {
+		res[contract] = signer
+	}
+	return res
+}
+
 // StartMining starts the miner with the given number of CPU threads. If mining
 // is already running, this method adjust the number of threads allowed to use
 // and updates the minimum price required by the transaction pool.
@@ -657,6 +743,8 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	s.stakingIndexer.Close()
+	s.voteIndexer.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
 	s.protocolManager.Stop()
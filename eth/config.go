@@ -31,6 +31,7 @@ import (
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/eth/downloader"
 	"energi.world/core/gen3/eth/gasprice"
+	"energi.world/core/gen3/miner"
 	"energi.world/core/gen3/params"
 )
 
@@ -106,6 +107,7 @@ type Config struct {
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	DatabaseFreezer    string
 	TrieCleanCache     int
 	TrieDirtyCache     int
 	TrieTimeout        time.Duration
@@ -125,10 +127,16 @@ type Config struct {
 	MinerMigration string  `toml:",omitempty"`
 	MinerNonceCap  uint64  `toml:"-"`
 
-	MinerAutocollateral uint64 `toml:",omitempty"`
+	MinerAutocollateral         uint64                       `toml:",omitempty"`
+	MinerAutocollateralPolicies []miner.AutocollateralPolicy `toml:",omitempty"`
 
 	PublicService bool `toml:",omitempty"`
 
+	// Remote checkpoint acceptance rule and masternode quorum in percent.
+	// Empty values keep the ones of the chain config.
+	CheckpointPolicy string `toml:",omitempty"`
+	CheckpointQuorum uint64 `toml:",omitempty"`
+
 	// Ethash options
 	Ethash ethash.Config
 
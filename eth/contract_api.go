@@ -23,64 +23,17 @@ import (
 
 	ethereum "energi.world/core/gen3"
 	"energi.world/core/gen3/common"
-	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/core/types"
-	"energi.world/core/gen3/core/vm"
-	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/internal/ethapi"
 	"energi.world/core/gen3/rpc"
-
-	energi_common "energi.world/core/gen3/energi/common"
-	energi_params "energi.world/core/gen3/energi/params"
 )
 
 func (b *EthAPIBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
-	rpcBlockNumber := rpc.LatestBlockNumber
-
-	if blockNumber != nil {
-		rpcBlockNumber = rpc.BlockNumber(blockNumber.Int64())
-	}
-
-	state, _, err := b.StateAndHeaderByNumber(ctx, rpcBlockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	return state.GetCode(contract), nil
+	return ethapi.ContractCodeAt(ctx, b, contract, blockNumber)
 }
 
 func (b *EthAPIBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	rpcBlockNumber := rpc.LatestBlockNumber
-
-	if blockNumber != nil {
-		rpcBlockNumber = rpc.BlockNumber(blockNumber.Int64())
-	}
-
-	state, header, err := b.StateAndHeaderByNumber(ctx, rpcBlockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	if call.Gas == 0 {
-		call.Gas = 100000
-	}
-
-	msg := types.NewMessage(
-		energi_params.Energi_SystemFaucet,
-		call.To,
-		0,
-		common.Big0,
-		call.Gas,
-		common.Big0,
-		call.Data,
-		false,
-	)
-
-	evmctx := core.NewEVMContext(msg, header, b.eth.blockchain, &header.Coinbase)
-	vmenv := vm.NewEVM(evmctx, state, b.eth.chainConfig, *b.eth.blockchain.GetVMConfig())
-	gaspool := new(core.GasPool).AddGas(call.Gas)
-
-	ret, _, _, err := core.NewStateTransition(vmenv, msg, gaspool).TransitionDb()
-	return ret, err
+	return ethapi.CallContract(ctx, b, call, blockNumber)
 }
 
 func (b *EthAPIBackend) PendingCodeAt(
@@ -127,45 +80,7 @@ func (b *EthAPIBackend) FilterLogs(
 	ctx context.Context,
 	query ethereum.FilterQuery,
 ) ([]types.Log, error) {
-	toBlock := rpc.LatestBlockNumber
-	if query.ToBlock != nil {
-		toBlock = rpc.BlockNumber(query.ToBlock.Int64())
-	}
-
-	rpcBlockNumber := toBlock
-	if query.FromBlock != nil {
-		rpcBlockNumber = rpc.BlockNumber(query.FromBlock.Int64())
-	}
-
-	requiredLogs := make([]types.Log, 0, int(toBlock-rpcBlockNumber))
-	for i := rpcBlockNumber; i <= toBlock; i++ {
-		header, err := b.HeaderByNumber(ctx, i)
-		if err != nil {
-			return nil, err
-		}
-
-		// Fetch txs in the block with the provided block hash
-		allLogs, err := b.GetLogs(ctx, header.Hash())
-		if err != nil {
-			return nil, err
-		}
-
-		blockNo := uint64(i)
-		for _, logs := range allLogs {
-			for _, log := range logs {
-				if b.isFilteredLog(ctx, query, log, &blockNo) {
-					requiredLogs = append(requiredLogs, *log)
-				}
-			}
-		}
-
-		// When fetching the latest block, do not loop more than once
-		if i == rpc.LatestBlockNumber {
-			break
-		}
-	}
-
-	return requiredLogs, nil
+	return ethapi.FilterLogs(ctx, b, query)
 }
 
 // SubscribeFilterLogs returns the logs that are created after subscription.
@@ -174,71 +89,5 @@ func (b *EthAPIBackend) SubscribeFilterLogs(
 	query ethereum.FilterQuery,
 	ch chan<- types.Log,
 ) (ethereum.Subscription, error) {
-	// Subscribe to all contract events
-	sinkLogs := make(chan []*types.Log)
-
-	sub := b.SubscribeLogsEvent(sinkLogs)
-	// Since we're getting logs in batches, we need to flatten them into a plain stream
-	return event.NewSubscription(func(quit <-chan struct{}) error {
-		defer sub.Unsubscribe()
-		for {
-			select {
-			case logs := <-sinkLogs:
-				for _, log := range logs {
-					// Select the required logs only.
-					if !b.isFilteredLog(ctx, query, log, nil) {
-						continue
-					}
-
-					select {
-					case ch <- *log:
-					case err := <-sub.Err():
-						if err != nil {
-							return err
-						}
-					case <-quit:
-						return nil
-					}
-				}
-			case err := <-sub.Err():
-				if err != nil {
-					return err
-				}
-			case <-quit:
-				return nil
-			}
-		}
-	}), nil
-}
-
-func (b *EthAPIBackend) isFilteredLog(
-	ctx context.Context,
-	q ethereum.FilterQuery,
-	log *types.Log,
-	blockNo *uint64,
-) bool {
-
-	for _, addr := range q.Addresses {
-		generalProxyHash := energi_common.GeneralProxyHashExtractor(ctx, addr, blockNo)
-		if generalProxyHash != nil && log.Address.Hash() == *generalProxyHash {
-			return true
-		}
-
-		if addr == log.Address {
-			return true
-		}
-	}
-
-	for _, queryTopics := range q.Topics {
-		if len(queryTopics) > 0 {
-			for _, foundTopic := range log.Topics {
-				// Check if missed event name topic was returned.
-				if len(foundTopic) > 0 && queryTopics[0] == foundTopic {
-					return true
-				}
-			}
-		}
-	}
-
-	return false
+	return ethapi.SubscribeFilterLogs(ctx, b, query, ch)
 }
@@ -0,0 +1,189 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/p2p/enode"
+)
+
+var (
+	ErrChallengePeerNotFound = errors.New("challenged peer is not connected")
+	ErrChallengeTimeout      = errors.New("challenge response timeout")
+	ErrChallengeMismatch     = errors.New("challenge response mismatch")
+
+	errChallengeUnknownBlock = errors.New("challenge block is not known")
+	errChallengeBusy         = errors.New("peer is already challenged")
+	errChallengeSyncing      = errors.New("challenge is not possible while syncing")
+)
+
+// blockChallenge is a pending MN-14 block availability challenge.
+type blockChallenge struct {
+	number   uint64
+	root     common.Hash
+	headers  chan *types.Header
+	nodeData chan []byte
+}
+
+// challengeSet tracks pending challenges per peer.
+type challengeSet struct {
+	mtx     sync.Mutex
+	pending map[string]*blockChallenge
+}
+
+func (cs *challengeSet) add(id string, c *blockChallenge) bool {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	if cs.pending == nil {
+		cs.pending = make(map[string]*blockChallenge)
+	}
+
+	if _, ok := cs.pending[id]; ok {
+		return false
+	}
+
+	cs.pending[id] = c
+	return true
+}
+
+func (cs *challengeSet) remove(id string) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	delete(cs.pending, id)
+}
+
+// deliverHeaders routes the requested challenge header. The headers are not
+// consumed as the peer can not tell challenges apart from downloader requests.
+func (cs *challengeSet) deliverHeaders(id string, headers []*types.Header) {
+	if len(headers) != 1 {
+		return
+	}
+
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	c, ok := cs.pending[id]
+	if !ok || headers[0].Number.Uint64() != c.number {
+		return
+	}
+
+	select {
+	case c.headers <- headers[0]:
+	default:
+	}
+}
+
+// deliverNodeData routes the requested challenge state root. The data is not
+// consumed as the downloader may have requested it in parallel.
+func (cs *challengeSet) deliverNodeData(id string, data [][]byte) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	c, ok := cs.pending[id]
+	if !ok {
+		return
+	}
+
+	for _, d := range data {
+		if crypto.Keccak256Hash(d) == c.root {
+			select {
+			case c.nodeData <- d:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// ChallengePeer checks that the peer is able to serve the historical block
+// header and its state root as required by MN-14. The responses are checked
+// against the local chain.
+func (s *Ethereum) ChallengePeer(id enode.ID, number uint64, timeout time.Duration) error {
+	return s.protocolManager.challengePeer(id, number, timeout)
+}
+
+func (pm *ProtocolManager) challengePeer(id enode.ID, number uint64, timeout time.Duration) error {
+	header := pm.blockchain.GetHeaderByNumber(number)
+	if header == nil {
+		return errChallengeUnknownBlock
+	}
+
+	p := pm.peers.Peer(fmt.Sprintf("%x", id.Bytes()[:8]))
+	if p == nil {
+		return ErrChallengePeerNotFound
+	}
+
+	c := &blockChallenge{
+		number:   number,
+		root:     header.Root,
+		headers:  make(chan *types.Header, 1),
+		nodeData: make(chan []byte, 1),
+	}
+
+	// A challenge response could be taken for a reply to the downloader
+	if pm.downloader.Synchronising() {
+		return errChallengeSyncing
+	}
+
+	if !pm.challenges.add(p.id, c) {
+		return errChallengeBusy
+	}
+	defer pm.challenges.remove(p.id)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Block availability
+	if err := p.RequestHeadersByNumber(number, 1, 0, false); err != nil {
+		return err
+	}
+
+	select {
+	case h := <-c.headers:
+		if h.Hash() != header.Hash() {
+			p.Log().Debug("Challenge header mismatch", "number", number,
+				"hash", h.Hash(), "want", header.Hash())
+			return ErrChallengeMismatch
+		}
+	case <-timer.C:
+		return ErrChallengeTimeout
+	}
+
+	// State availability
+	if err := p.RequestNodeData([]common.Hash{header.Root}); err != nil {
+		return err
+	}
+
+	select {
+	case <-c.nodeData:
+		// NOTE: the hash is checked on delivery
+	case <-timer.C:
+		return ErrChallengeTimeout
+	}
+
+	p.Log().Trace("Challenge passed", "number", number)
+	return nil
+}
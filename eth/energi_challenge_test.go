@@ -0,0 +1,102 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/eth/downloader"
+	"energi.world/core/gen3/p2p"
+	"energi.world/core/gen3/p2p/enode"
+)
+
+// Tests that block availability challenges are correctly verified.
+func TestChallengePeer(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 8, nil, nil)
+	defer pm.Stop()
+
+	peer, _ := newTestPeer("peer", nrg70, pm, true)
+	defer peer.close()
+
+	id := peer.Peer.ID()
+	header := pm.blockchain.GetHeaderByNumber(4)
+	root, _ := pm.blockchain.TrieNode(header.Root)
+
+	challenge := func(timeout time.Duration) <-chan error {
+		res := make(chan error, 1)
+		go func() {
+			res <- pm.challengePeer(id, header.Number.Uint64(), timeout)
+		}()
+		return res
+	}
+	respondHeader := func(h *types.Header) {
+		if err := p2p.ExpectMsg(peer.app, GetBlockHeadersMsg, &getBlockHeadersData{
+			Origin: hashOrNumber{Number: h.Number.Uint64()},
+			Amount: 1,
+		}); err != nil {
+			t.Fatalf("header request: %v", err)
+		}
+		if err := p2p.Send(peer.app, BlockHeadersMsg, []*types.Header{h}); err != nil {
+			t.Fatalf("header response: %v", err)
+		}
+	}
+	respondState := func(data []byte) {
+		msg, err := peer.app.ReadMsg()
+		if err != nil {
+			t.Fatalf("state request: %v", err)
+		}
+		if msg.Code != GetNodeDataMsg {
+			t.Fatalf("state request code mismatch: have %x, want %x", msg.Code, GetNodeDataMsg)
+		}
+		msg.Discard()
+		if err := p2p.Send(peer.app, NodeDataMsg, [][]byte{data}); err != nil {
+			t.Fatalf("state response: %v", err)
+		}
+	}
+
+	// Honest peer
+	res := challenge(time.Second)
+	respondHeader(header)
+	respondState(root)
+	if err := <-res; err != nil {
+		t.Fatalf("honest peer failed: %v", err)
+	}
+
+	// Wrong block
+	res = challenge(time.Second)
+	fake := types.CopyHeader(header)
+	fake.Extra = []byte("fake")
+	respondHeader(fake)
+	if err := <-res; err != ErrChallengeMismatch {
+		t.Fatalf("wrong header: have %v, want %v", err, ErrChallengeMismatch)
+	}
+
+	// Missing state
+	res = challenge(100 * time.Millisecond)
+	respondHeader(header)
+	respondState([]byte("garbage"))
+	if err := <-res; err != ErrChallengeTimeout {
+		t.Fatalf("missing state: have %v, want %v", err, ErrChallengeTimeout)
+	}
+
+	// Unknown peer
+	if err := pm.challengePeer(enode.ID{}, 4, time.Second); err != ErrChallengePeerNotFound {
+		t.Fatalf("unknown peer: have %v, want %v", err, ErrChallengePeerNotFound)
+	}
+}
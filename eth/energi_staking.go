@@ -0,0 +1,324 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"energi.world/core/gen3/accounts/abi"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/log"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+const (
+	// stakingHistoryBlocks is the number of blocks a single staking history
+	// section covers.
+	stakingHistoryBlocks uint64 = 4096
+
+	// stakingHistoryConfirms is the number of confirmation blocks before
+	// a staking history section is considered final.
+	stakingHistoryConfirms uint64 = 256
+
+	// stakingThrottling is the time to wait between processing two consecutive
+	// index sections.
+	stakingThrottling = 100 * time.Millisecond
+
+	// stakingRewardCallGas is the gas limit of a single getReward() call.
+	stakingRewardCallGas uint64 = 1000000
+
+	// stakingUnindexedMax is the maximum number of blocks processed on the
+	// fly, i.e. the regular lag of an up to date staking indexer.
+	stakingUnindexedMax = stakingHistoryBlocks + stakingHistoryConfirms
+)
+
+var (
+	errStakingRewardFailed = errors.New("getReward() call has failed")
+	errStakingNotIndexed   = errors.New("staking history index is behind, try again later")
+)
+
+// stakingRewards calculates the block reward split as it is done by
+// the BlockReward contract during block finalization.
+type stakingRewards struct {
+	chain     *core.BlockChain
+	rewardAbi abi.ABI
+}
+
+func newStakingRewards(chain *core.BlockChain) (*stakingRewards, error) {
+	reward_abi, err := abi.JSON(strings.NewReader(energi_abi.IBlockRewardABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &stakingRewards{
+		chain:     chain,
+		rewardAbi: reward_abi,
+	}, nil
+}
+
+func (sr *stakingRewards) getReward(
+	statedb *state.StateDB,
+	header *types.Header,
+	proxy common.Address,
+) (*big.Int, error) {
+	data, err := sr.rewardAbi.Pack("getReward", header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := types.NewMessage(
+		energi_params.Energi_SystemFaucet,
+		&proxy,
+		0,
+		common.Big0,
+		stakingRewardCallGas,
+		common.Big0,
+		data,
+		false,
+	)
+
+	evmctx := core.NewEVMContext(msg, header, sr.chain, &header.Coinbase)
+	vmenv := vm.NewEVM(evmctx, statedb, sr.chain.Config(), *sr.chain.GetVMConfig())
+	gp := new(core.GasPool).AddGas(msg.Gas())
+
+	output, _, failed, err := core.ApplyMessage(vmenv, msg, gp)
+	if err != nil {
+		return nil, err
+	}
+	if failed {
+		return nil, errStakingRewardFailed
+	}
+
+	reward := new(big.Int)
+	if err = sr.rewardAbi.Unpack(&reward, "getReward", output); err != nil {
+		return nil, err
+	}
+
+	return reward, nil
+}
+
+// newStakingRecord builds staking history record for the header without
+// any rewards.
+func newStakingRecord(header *types.Header) rawdb.StakingRecord {
+	return rawdb.StakingRecord{
+		Number:           header.Number.Uint64(),
+		Hash:             header.Hash(),
+		Time:             header.Time,
+		Weight:           header.Nonce.Uint64(),
+		StakerReward:     new(big.Int),
+		MasternodeReward: new(big.Int),
+		BackboneReward:   new(big.Int),
+		TreasuryReward:   new(big.Int),
+	}
+}
+
+// record builds staking history record for the header.
+//
+// NOTE: historical state may be missing on non-archive nodes. The latest
+// state is used as the best effort estimation then.
+func (sr *stakingRewards) record(header *types.Header) rawdb.StakingRecord {
+	number := header.Number.Uint64()
+	record := newStakingRecord(header)
+
+	var statedb *state.StateDB
+	var err error
+
+	if parent := sr.chain.GetHeader(header.ParentHash, number-1); parent != nil {
+		statedb, err = sr.chain.StateAt(parent.Root)
+	}
+
+	if statedb == nil {
+		record.Estimated = true
+
+		if statedb, err = sr.chain.State(); err != nil {
+			log.Warn("Staking history state is missing", "number", number, "err", err)
+			return record
+		}
+	}
+
+	for _, r := range []struct {
+		proxy  common.Address
+		amount *big.Int
+	}{
+		{energi_params.Energi_StakerReward, record.StakerReward},
+		{energi_params.Energi_MasternodeRegistry, record.MasternodeReward},
+		{energi_params.Energi_BackboneReward, record.BackboneReward},
+		{energi_params.Energi_Treasury, record.TreasuryReward},
+	} {
+		amount, err := sr.getReward(statedb, header, r.proxy)
+		if err != nil {
+			log.Debug("Staking history getReward failed",
+				"number", number, "proxy", r.proxy, "err", err)
+			record.Estimated = true
+			continue
+		}
+
+		r.amount.Set(amount)
+	}
+
+	return record
+}
+
+// StakingIndexer implements a core.ChainIndexer, building up a per coinbase
+// ledger of staked blocks and the related block rewards.
+type StakingIndexer struct {
+	db      ethdb.Database // database instance to write index data and metadata into
+	rewards *stakingRewards
+	section uint64
+	head    common.Hash
+	records map[common.Address][]rawdb.StakingRecord
+}
+
+// NewStakingIndexer returns a chain indexer that generates staking history
+// for the canonical chain.
+func NewStakingIndexer(db ethdb.Database, rewards *stakingRewards) *core.ChainIndexer {
+	backend := &StakingIndexer{
+		db:      db,
+		rewards: rewards,
+	}
+	table := ethdb.NewTable(db, string(rawdb.StakingIndexPrefix))
+
+	return core.NewChainIndexer(
+		db, table, backend,
+		stakingHistoryBlocks, stakingHistoryConfirms,
+		stakingThrottling, "staking")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new staking history
+// section.
+func (s *StakingIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	s.section, s.head = section, common.Hash{}
+	s.records = make(map[common.Address][]rawdb.StakingRecord)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding a new staked block
+// into the index.
+func (s *StakingIndexer) Process(ctx context.Context, header *types.Header) error {
+	s.head = header.Hash()
+
+	// Genesis is not staked
+	if header.Number.Cmp(common.Big0) == 0 {
+		return nil
+	}
+
+	// NOTE: estimations depend on the latest state, so only the block itself
+	//       is indexed and the rewards are calculated again on query.
+	record := s.rewards.record(header)
+	if record.Estimated {
+		record = newStakingRecord(header)
+		record.Estimated = true
+	}
+
+	s.records[header.Coinbase] = append(s.records[header.Coinbase], record)
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the staking
+// history section into the database.
+func (s *StakingIndexer) Commit() error {
+	batch := s.db.NewBatch()
+	for addr, records := range s.records {
+		rawdb.WriteStakingRecords(batch, s.section, s.head, addr, records)
+	}
+	return batch.Write()
+}
+
+// StakingHistory returns staking records of the coinbase in the specified
+// block range. Processed sections are served from the index while the rest
+// is calculated on the fly.
+func (b *EthAPIBackend) StakingHistory(
+	ctx context.Context,
+	addr common.Address,
+	from, to uint64,
+) ([]rawdb.StakingRecord, error) {
+	eth := b.eth
+	chain := eth.blockchain
+
+	if head := chain.CurrentHeader().Number.Uint64(); to > head {
+		to = head
+	}
+
+	sections, _, _ := eth.stakingIndexer.Sections()
+	if unindexedBlocks(from, to, sections*stakingHistoryBlocks) > stakingUnindexedMax {
+		return nil, errStakingNotIndexed
+	}
+
+	res := []rawdb.StakingRecord{}
+
+	for section := from / stakingHistoryBlocks; section <= to/stakingHistoryBlocks; section++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		start := section * stakingHistoryBlocks
+		end := start + stakingHistoryBlocks - 1
+
+		if section < sections {
+			head := rawdb.ReadCanonicalHash(eth.chainDb, end)
+
+			for _, r := range rawdb.ReadStakingRecords(eth.chainDb, section, head, addr) {
+				if r.Number < from || r.Number > to {
+					continue
+				}
+
+				// Historical state may be available by now, e.g. after resync
+				if r.Estimated {
+					if header := chain.GetHeader(r.Hash, r.Number); header != nil {
+						r = eth.stakingRewards.record(header)
+					}
+				}
+
+				res = append(res, r)
+			}
+
+			continue
+		}
+
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+
+		for num := start; num <= end; num++ {
+			header := chain.GetHeaderByNumber(num)
+			if header == nil {
+				break
+			}
+
+			if header.Coinbase == addr && num > 0 {
+				res = append(res, eth.stakingRewards.record(header))
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/eth/tracers"
+	"energi.world/core/gen3/log"
+
+	energi_consensus "energi.world/core/gen3/energi/consensus"
+)
+
+// stepTracer records the outcome of the top-level call of a finalize step
+// for the wrapped tracer.
+type stepTracer struct {
+	vm.Tracer
+	output  []byte
+	gasUsed uint64
+	err     error
+	ended   bool
+}
+
+func (t *stepTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.output = common.CopyBytes(output)
+	t.gasUsed = gasUsed
+	t.err = err
+	t.ended = true
+	return t.Tracer.CaptureEnd(output, gasUsed, d, err)
+}
+
+// isFinalizeTrace checks if the finalize steps should be traced.
+func isFinalizeTrace(config *TraceConfig) bool {
+	return config != nil && config.Finalize != nil && *config.Finalize
+}
+
+// userTransactions returns the transactions of the block without the trailing
+// consensus ones, which get recreated by the finalize steps.
+func userTransactions(txs types.Transactions) types.Transactions {
+	for i := len(txs); i > 0; i-- {
+		if !txs[i-1].IsConsensus() {
+			return txs[:i]
+		}
+	}
+	return txs[:0]
+}
+
+// traceFinalize runs the finalize steps of the block on top of the state after
+// its user transactions. Every EVM call of the steps gets traced separately
+// and labelled with its step. Gas of the calls excludes the intrinsic gas.
+func (api *PrivateDebugAPI) traceFinalize(
+	ctx context.Context,
+	block *types.Block,
+	statedb *state.StateDB,
+	txs types.Transactions,
+	config *TraceConfig,
+) ([]*txTraceResult, error) {
+	engine, ok := api.eth.engine.(*energi_consensus.Energi)
+	if !ok {
+		return nil, errors.New("finalize tracing is not supported by the consensus engine")
+	}
+
+	var (
+		results []*txTraceResult
+		current *stepTracer
+		step    string
+		cancels []context.CancelFunc
+		failure error
+	)
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	// Collect the result of the previous call
+	collect := func() {
+		if current == nil {
+			return
+		}
+
+		res := &txTraceResult{Step: step}
+		if current.ended {
+			out, err := traceResult(current.Tracer, current.output, current.gasUsed, current.err != nil)
+			if err != nil {
+				res.Error = err.Error()
+			} else {
+				res.Result = out
+			}
+		} else {
+			// Prevent memory leak in C code
+			if tracer, ok := current.Tracer.(*tracers.Tracer); ok {
+				tracer.GetResult()
+			}
+			res.Error = "no EVM call"
+		}
+
+		results = append(results, res)
+		current = nil
+	}
+
+	configFn := func(name string) *vm.Config {
+		collect()
+
+		tracer, cancel, err := newTracer(ctx, config)
+		if err != nil {
+			if failure == nil {
+				failure = err
+			}
+			return &vm.Config{}
+		}
+		cancels = append(cancels, cancel)
+
+		current, step = &stepTracer{Tracer: tracer}, name
+		return &vm.Config{Debug: true, Tracer: current}
+	}
+
+	header := block.Header()
+	ctxs, err := engine.TraceFinalize(api.eth.blockchain, header, statedb, txs, configFn)
+	collect()
+	if err == nil {
+		err = failure
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The finalize steps are expected to reproduce the block
+	bctxs := block.Transactions()[len(txs):]
+	if len(ctxs) != len(bctxs) {
+		log.Warn("Traced consensus TX count mismatch", "block", block.Hash(),
+			"traced", len(ctxs), "block", len(bctxs))
+	} else {
+		for i, tx := range ctxs {
+			if tx.Hash() != bctxs[i].Hash() {
+				log.Warn("Traced consensus TX mismatch", "block", block.Hash(),
+					"traced", tx.Hash(), "block", bctxs[i].Hash())
+			}
+		}
+	}
+	if header.Root != block.Root() {
+		log.Warn("Traced finalize state mismatch", "block", block.Hash(),
+			"traced", header.Root, "block", block.Root())
+	}
+
+	return results, nil
+}
@@ -0,0 +1,376 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"energi.world/core/gen3/accounts/abi"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/ethdb"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/params"
+
+	energi_abi "energi.world/core/gen3/energi/abi"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+const (
+	// voteIndexBlocks is the number of blocks a single vote index section
+	// covers.
+	voteIndexBlocks uint64 = 4096
+
+	// voteIndexConfirms is the number of confirmation blocks before a vote
+	// index section is considered final.
+	voteIndexConfirms uint64 = 256
+
+	// voteThrottling is the time to wait between processing two consecutive
+	// index sections.
+	voteThrottling = 100 * time.Millisecond
+
+	// voteUnindexedMax is the maximum number of blocks parsed on the fly,
+	// i.e. the regular lag of an up to date vote indexer.
+	voteUnindexedMax = voteIndexBlocks + voteIndexConfirms
+)
+
+var errVotesNotIndexed = errors.New("vote index is behind, try again later")
+
+// voteParser extracts masternode votes out of block transactions.
+//
+// NOTE: IProposal does not emit any vote events, so successful voteAccept()
+// and voteReject() calls are detected by the method selector.
+type voteParser struct {
+	config     *params.ChainConfig
+	voteAccept []byte
+	voteReject []byte
+	cpSign     abi.Method
+	cpEvent    abi.Event
+	cpRegistry abi.ABI
+}
+
+func newVoteParser(config *params.ChainConfig) (*voteParser, error) {
+	proposal_abi, err := abi.JSON(strings.NewReader(energi_abi.IProposalABI))
+	if err != nil {
+		return nil, err
+	}
+
+	registry_abi, err := abi.JSON(strings.NewReader(energi_abi.ICheckpointRegistryABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &voteParser{
+		config:     config,
+		voteAccept: proposal_abi.Methods["voteAccept"].Id(),
+		voteReject: proposal_abi.Methods["voteReject"].Id(),
+		cpSign:     registry_abi.Methods["sign"],
+		cpEvent:    registry_abi.Events["Checkpoint"],
+		cpRegistry: registry_abi,
+	}, nil
+}
+
+// parse returns vote records by sender and all vote targets of the block.
+// Only the first vote of each proposal is reported as a target. Created
+// contracts are reported regardless of votes.
+func (vp *voteParser) parse(
+	block *types.Block,
+	receipts types.Receipts,
+	seen map[common.Address]bool,
+) (map[common.Address][]rawdb.VoteRecord, []rawdb.VoteTarget) {
+	records := make(map[common.Address][]rawdb.VoteRecord)
+	var targets []rawdb.VoteTarget
+
+	number := block.NumberU64()
+	signer := types.MakeSigner(vp.config, block.Number())
+
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) || receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+
+		to := tx.To()
+		data := tx.Data()
+
+		// NOTE: standalone proposals get listed since their creation
+		if to == nil {
+			targets = append(targets, rawdb.VoteTarget{
+				Number: number,
+				Kind:   rawdb.VoteCreate,
+				Target: receipts[i].ContractAddress,
+			})
+			continue
+		}
+
+		if len(data) < 4 {
+			continue
+		}
+
+		record := rawdb.VoteRecord{
+			Number: number,
+			TxHash: tx.Hash(),
+		}
+
+		switch {
+		case bytes.Equal(data, vp.voteAccept):
+			record.Kind = rawdb.VoteAccept
+			record.Target = *to
+		case bytes.Equal(data, vp.voteReject):
+			record.Kind = rawdb.VoteReject
+			record.Target = *to
+		case *to == energi_params.Energi_CheckpointRegistry && bytes.Equal(data[:4], vp.cpSign.Id()):
+			args, err := vp.cpSign.Inputs.UnpackValues(data[4:])
+			if err != nil || len(args) == 0 {
+				log.Debug("Invalid checkpoint sign call", "tx", tx.Hash(), "err", err)
+				continue
+			}
+			cp, ok := args[0].(common.Address)
+			if !ok {
+				continue
+			}
+			record.Kind = rawdb.VoteCheckpoint
+			record.Target = cp
+		default:
+			if *to == energi_params.Energi_CheckpointRegistry {
+				targets = append(targets, vp.checkpoints(number, receipts[i])...)
+			}
+			continue
+		}
+
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			log.Debug("Failed to recover vote sender", "tx", tx.Hash(), "err", err)
+			continue
+		}
+
+		records[sender] = append(records[sender], record)
+
+		if record.Kind != rawdb.VoteCheckpoint && !seen[record.Target] {
+			seen[record.Target] = true
+			targets = append(targets, rawdb.VoteTarget{
+				Number: number,
+				Kind:   rawdb.VoteAccept,
+				Target: record.Target,
+			})
+		}
+	}
+
+	return records, targets
+}
+
+// checkpoints returns all checkpoints proposed by the receipt.
+func (vp *voteParser) checkpoints(number uint64, receipt *types.Receipt) []rawdb.VoteTarget {
+	var res []rawdb.VoteTarget
+
+	for _, l := range receipt.Logs {
+		if len(l.Topics) != 2 || l.Topics[0] != vp.cpEvent.Id() {
+			continue
+		}
+
+		ev := new(energi_abi.ICheckpointRegistryCheckpoint)
+		if err := vp.cpRegistry.Unpack(ev, "Checkpoint", l.Data); err != nil {
+			log.Debug("Invalid checkpoint event", "tx", l.TxHash, "err", err)
+			continue
+		}
+
+		res = append(res, rawdb.VoteTarget{
+			Number: number,
+			Kind:   rawdb.VoteCheckpoint,
+			Target: ev.Checkpoint,
+			CPNum:  l.Topics[1].Big().Uint64(),
+			CPHash: ev.Hash,
+		})
+	}
+
+	return res
+}
+
+// VoteIndexer implements a core.ChainIndexer, building up per sender records
+// of proposal votes and checkpoint signatures.
+type VoteIndexer struct {
+	db      ethdb.Database // database instance to write index data and metadata into
+	parser  *voteParser
+	section uint64
+	head    common.Hash
+	records map[common.Address][]rawdb.VoteRecord
+	targets []rawdb.VoteTarget
+	seen    map[common.Address]bool
+}
+
+// NewVoteIndexer returns a chain indexer that generates masternode voting
+// records for the canonical chain.
+func NewVoteIndexer(db ethdb.Database, parser *voteParser) *core.ChainIndexer {
+	backend := &VoteIndexer{
+		db:     db,
+		parser: parser,
+	}
+	table := ethdb.NewTable(db, string(rawdb.VoteIndexPrefix))
+
+	return core.NewChainIndexer(
+		db, table, backend,
+		voteIndexBlocks, voteIndexConfirms,
+		voteThrottling, "votes")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new vote index
+// section.
+func (v *VoteIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	v.section, v.head = section, common.Hash{}
+	v.records = make(map[common.Address][]rawdb.VoteRecord)
+	v.targets = nil
+	v.seen = make(map[common.Address]bool)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding votes of a new block
+// into the index.
+func (v *VoteIndexer) Process(ctx context.Context, header *types.Header) error {
+	v.head = header.Hash()
+	number := header.Number.Uint64()
+
+	block := rawdb.ReadBlock(v.db, v.head, number)
+	if block == nil || len(block.Transactions()) == 0 {
+		return nil
+	}
+
+	receipts := rawdb.ReadReceipts(v.db, v.head, number)
+	records, targets := v.parser.parse(block, receipts, v.seen)
+
+	for addr, recs := range records {
+		v.records[addr] = append(v.records[addr], recs...)
+	}
+	v.targets = append(v.targets, targets...)
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the vote index
+// section into the database.
+func (v *VoteIndexer) Commit() error {
+	batch := v.db.NewBatch()
+	for addr, records := range v.records {
+		rawdb.WriteVoteRecords(batch, v.section, v.head, addr, records)
+	}
+	rawdb.WriteVoteTargets(batch, v.section, v.head, v.targets)
+	return batch.Write()
+}
+
+// MasternodeVotes returns vote records of any of the senders together with
+// all vote targets in the specified block range. Processed sections are
+// served from the index while the rest is parsed on the fly.
+func (b *EthAPIBackend) MasternodeVotes(
+	ctx context.Context,
+	senders []common.Address,
+	from, to uint64,
+) ([]rawdb.VoteRecord, []rawdb.VoteTarget, error) {
+	eth := b.eth
+	chain := eth.blockchain
+
+	if head := chain.CurrentHeader().Number.Uint64(); to > head {
+		to = head
+	}
+
+	sections, _, _ := eth.voteIndexer.Sections()
+	if unindexedBlocks(from, to, sections*voteIndexBlocks) > voteUnindexedMax {
+		return nil, nil, errVotesNotIndexed
+	}
+
+	records := []rawdb.VoteRecord{}
+	targets := []rawdb.VoteTarget{}
+	seen := make(map[common.Address]bool)
+
+	for section := from / voteIndexBlocks; section <= to/voteIndexBlocks; section++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		start := section * voteIndexBlocks
+		end := start + voteIndexBlocks - 1
+
+		if section < sections {
+			head := rawdb.ReadCanonicalHash(eth.chainDb, end)
+
+			for _, addr := range senders {
+				for _, r := range rawdb.ReadVoteRecords(eth.chainDb, section, head, addr) {
+					if r.Number >= from && r.Number <= to {
+						records = append(records, r)
+					}
+				}
+			}
+
+			for _, t := range rawdb.ReadVoteTargets(eth.chainDb, section, head) {
+				if t.Number < from || t.Number > to {
+					continue
+				}
+
+				// NOTE: the same proposal may be a target in several sections
+				if t.Kind == rawdb.VoteCreate {
+					targets = append(targets, t)
+				} else if !seen[t.Target] {
+					seen[t.Target] = true
+					targets = append(targets, t)
+				}
+			}
+
+			continue
+		}
+
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+
+		for num := start; num <= end; num++ {
+			block := chain.GetBlockByNumber(num)
+			if block == nil {
+				break
+			}
+			if len(block.Transactions()) == 0 {
+				continue
+			}
+
+			receipts := chain.GetReceiptsByHash(block.Hash())
+			blockRecords, blockTargets := eth.voteParser.parse(block, receipts, seen)
+
+			for _, addr := range senders {
+				records = append(records, blockRecords[addr]...)
+			}
+			targets = append(targets, blockTargets...)
+		}
+	}
+
+	return records, targets, nil
+}
+
+// unindexedBlocks returns the number of blocks in the range which are not
+// covered by the index yet.
+func unindexedBlocks(from, to, indexed uint64) uint64 {
+	if from < indexed {
+		from = indexed
+	}
+	if to < from {
+		return 0
+	}
+	return to - from + 1
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/crypto"
+	"energi.world/core/gen3/params"
+
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+// Tests that proposal votes, checkpoint signatures, checkpoint proposals and
+// contract creations are extracted from blocks.
+func TestVoteParser(t *testing.T) {
+	config := params.TestChainConfig
+	parser, err := newVoteParser(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownerKey, _ := crypto.GenerateKey()
+	mnKey, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(ownerKey.PublicKey)
+	mn := crypto.PubkeyToAddress(mnKey.PublicKey)
+
+	proposal := common.HexToAddress("0x1000")
+	checkpoint := common.HexToAddress("0x2000")
+	registry := energi_params.Energi_CheckpointRegistry
+	number := big.NewInt(5)
+	signer := types.MakeSigner(config, number)
+
+	signData, err := parser.cpSign.Inputs.Pack(checkpoint, []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signData = append(append([]byte{}, parser.cpSign.Id()...), signData...)
+
+	cpEventData, err := parser.cpEvent.Inputs.NonIndexed().Pack(
+		common.HexToHash("0x33"), checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTx := func(key *ecdsa.PrivateKey, nonce uint64, to common.Address, data []byte) *types.Transaction {
+		tx := types.NewTransaction(nonce, to, common.Big0, 100000, common.Big1, data)
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	newContractTx := func(key *ecdsa.PrivateKey, nonce uint64, data []byte) *types.Transaction {
+		tx := types.NewContractCreation(nonce, common.Big0, 100000, common.Big1, data)
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	txs := types.Transactions{
+		newTx(ownerKey, 0, proposal, parser.voteAccept),
+		newTx(ownerKey, 1, proposal, parser.voteReject), // failed
+		newTx(mnKey, 0, registry, signData),
+		newTx(mnKey, 1, registry, []byte{0x01, 0x02, 0x03, 0x04}),
+		newTx(mnKey, 2, common.HexToAddress("0x3000"), parser.voteReject),
+		newContractTx(ownerKey, 2, []byte{0x60, 0x00}),
+		newContractTx(ownerKey, 3, []byte{0x60, 0x00}), // failed
+	}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful},
+		{Status: types.ReceiptStatusFailed},
+		{Status: types.ReceiptStatusSuccessful},
+		{
+			Status: types.ReceiptStatusSuccessful,
+			Logs: []*types.Log{{
+				Topics: []common.Hash{parser.cpEvent.Id(), common.BigToHash(big.NewInt(4000))},
+				Data:   cpEventData,
+			}},
+		},
+		{Status: types.ReceiptStatusSuccessful},
+		{Status: types.ReceiptStatusSuccessful, ContractAddress: common.HexToAddress("0x4000")},
+		{Status: types.ReceiptStatusFailed},
+	}
+
+	block := types.NewBlock(&types.Header{Number: number}, txs, nil, receipts)
+	seen := map[common.Address]bool{common.HexToAddress("0x3000"): true}
+	records, targets := parser.parse(block, receipts, seen)
+
+	expRecords := map[common.Address][]rawdb.VoteRecord{
+		owner: {
+			{Number: 5, TxHash: txs[0].Hash(), Kind: rawdb.VoteAccept, Target: proposal},
+		},
+		mn: {
+			{Number: 5, TxHash: txs[2].Hash(), Kind: rawdb.VoteCheckpoint, Target: checkpoint},
+			{Number: 5, TxHash: txs[4].Hash(), Kind: rawdb.VoteReject, Target: common.HexToAddress("0x3000")},
+		},
+	}
+	if !reflect.DeepEqual(records, expRecords) {
+		t.Fatalf("vote records mismatch: have %v, want %v", records, expRecords)
+	}
+
+	expTargets := []rawdb.VoteTarget{
+		{Number: 5, Kind: rawdb.VoteAccept, Target: proposal},
+		{Number: 5, Kind: rawdb.VoteCheckpoint, Target: checkpoint,
+			CPNum: 4000, CPHash: common.HexToHash("0x33")},
+		{Number: 5, Kind: rawdb.VoteCreate, Target: common.HexToAddress("0x4000")},
+	}
+	if !reflect.DeepEqual(targets, expTargets) {
+		t.Fatalf("vote targets mismatch: have %v, want %v", targets, expTargets)
+	}
+	if !seen[proposal] {
+		t.Fatal("proposal is not marked as seen")
+	}
+}
+
+// Tests that only blocks beyond the indexed sections are counted.
+func TestUnindexedBlocks(t *testing.T) {
+	tests := []struct {
+		from, to, indexed, want uint64
+	}{
+		{0, 100, 4096, 0},
+		{0, 4095, 4096, 0},
+		{0, 4096, 4096, 1},
+		{5000, 5100, 4096, 101},
+		{0, 5100, 0, 5101},
+	}
+	for i, tt := range tests {
+		if have := unindexedBlocks(tt.from, tt.to, tt.indexed); have != tt.want {
+			t.Errorf("test %d: unindexed blocks mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
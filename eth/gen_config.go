@@ -12,6 +12,7 @@ import (
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/eth/downloader"
 	"energi.world/core/gen3/eth/gasprice"
+	"energi.world/core/gen3/miner"
 )
 
 var _ = (*configMarshaling)(nil)
@@ -19,42 +20,46 @@ var _ = (*configMarshaling)(nil)
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               uint64
-		SyncMode                downloader.SyncMode
-		NoPruning               bool
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               int                    `toml:",omitempty"`
-		LightPeers              int                    `toml:",omitempty"`
-		SkipBcVersionCheck      bool                   `toml:"-"`
-		DatabaseHandles         int                    `toml:"-"`
-		DatabaseCache           int
-		TrieCleanCache          int
-		TrieDirtyCache          int
-		TrieTimeout             time.Duration
-		TrieRapidTime           time.Duration
-		Etherbase               common.Address `toml:",omitempty"`
-		MinerNotify             []string       `toml:",omitempty"`
-		MinerExtraData          hexutil.Bytes  `toml:",omitempty"`
-		MinerGasFloor           uint64
-		MinerGasCeil            uint64
-		MinerGasPrice           *big.Int
-		MinerRecommit           time.Duration
-		MinerNoverify           bool
-		MinerDPoS               DPoSMap `toml:",omitempty"`
-		MinerMigration          string  `toml:",omitempty"`
-		MinerNonceCap           uint64  `toml:"-"`
-		MinerAutocollateral     uint64  `toml:",omitempty"`
-		PublicService           bool    `toml:",omitempty"`
-		Ethash                  ethash.Config
-		TxPool                  core.TxPoolConfig
-		GPO                     gasprice.Config
-		EnablePreimageRecording bool
-		DocRoot                 string `toml:"-"`
-		EWASMInterpreter        string
-		EVMInterpreter          string
-		ConstantinopleOverride  *big.Int
-		RPCGasCap               *big.Int `toml:",omitempty"`
+		Genesis                     *core.Genesis `toml:",omitempty"`
+		NetworkId                   uint64
+		SyncMode                    downloader.SyncMode
+		NoPruning                   bool
+		Whitelist                   map[uint64]common.Hash `toml:"-"`
+		LightServ                   int                    `toml:",omitempty"`
+		LightPeers                  int                    `toml:",omitempty"`
+		SkipBcVersionCheck          bool                   `toml:"-"`
+		DatabaseHandles             int                    `toml:"-"`
+		DatabaseCache               int
+		DatabaseFreezer             string
+		TrieCleanCache              int
+		TrieDirtyCache              int
+		TrieTimeout                 time.Duration
+		TrieRapidTime               time.Duration
+		Etherbase                   common.Address `toml:",omitempty"`
+		MinerNotify                 []string       `toml:",omitempty"`
+		MinerExtraData              hexutil.Bytes  `toml:",omitempty"`
+		MinerGasFloor               uint64
+		MinerGasCeil                uint64
+		MinerGasPrice               *big.Int
+		MinerRecommit               time.Duration
+		MinerNoverify               bool
+		MinerDPoS                   DPoSMap                      `toml:",omitempty"`
+		MinerMigration              string                       `toml:",omitempty"`
+		MinerNonceCap               uint64                       `toml:"-"`
+		MinerAutocollateral         uint64                       `toml:",omitempty"`
+		MinerAutocollateralPolicies []miner.AutocollateralPolicy `toml:",omitempty"`
+		PublicService               bool                         `toml:",omitempty"`
+		CheckpointPolicy            string                       `toml:",omitempty"`
+		CheckpointQuorum            uint64                       `toml:",omitempty"`
+		Ethash                      ethash.Config
+		TxPool                      core.TxPoolConfig
+		GPO                         gasprice.Config
+		EnablePreimageRecording     bool
+		DocRoot                     string `toml:"-"`
+		EWASMInterpreter            string
+		EVMInterpreter              string
+		ConstantinopleOverride      *big.Int
+		RPCGasCap                   *big.Int `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -67,6 +72,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
@@ -83,7 +89,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.MinerMigration = c.MinerMigration
 	enc.MinerNonceCap = c.MinerNonceCap
 	enc.MinerAutocollateral = c.MinerAutocollateral
+	enc.MinerAutocollateralPolicies = c.MinerAutocollateralPolicies
 	enc.PublicService = c.PublicService
+	enc.CheckpointPolicy = c.CheckpointPolicy
+	enc.CheckpointQuorum = c.CheckpointQuorum
 	enc.Ethash = c.Ethash
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
@@ -99,42 +108,46 @@ func (c Config) MarshalTOML() (interface{}, error) {
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               *uint64
-		SyncMode                *downloader.SyncMode
-		NoPruning               *bool
-		Whitelist               map[uint64]common.Hash `toml:"-"`
-		LightServ               *int                   `toml:",omitempty"`
-		LightPeers              *int                   `toml:",omitempty"`
-		SkipBcVersionCheck      *bool                  `toml:"-"`
-		DatabaseHandles         *int                   `toml:"-"`
-		DatabaseCache           *int
-		TrieCleanCache          *int
-		TrieDirtyCache          *int
-		TrieTimeout             *time.Duration
-		TrieRapidTime           *time.Duration
-		Etherbase               *common.Address `toml:",omitempty"`
-		MinerNotify             []string        `toml:",omitempty"`
-		MinerExtraData          *hexutil.Bytes  `toml:",omitempty"`
-		MinerGasFloor           *uint64
-		MinerGasCeil            *uint64
-		MinerGasPrice           *big.Int
-		MinerRecommit           *time.Duration
-		MinerNoverify           *bool
-		MinerDPoS               *DPoSMap `toml:",omitempty"`
-		MinerMigration          *string  `toml:",omitempty"`
-		MinerNonceCap           *uint64  `toml:"-"`
-		MinerAutocollateral     *uint64  `toml:",omitempty"`
-		PublicService           *bool    `toml:",omitempty"`
-		Ethash                  *ethash.Config
-		TxPool                  *core.TxPoolConfig
-		GPO                     *gasprice.Config
-		EnablePreimageRecording *bool
-		DocRoot                 *string `toml:"-"`
-		EWASMInterpreter        *string
-		EVMInterpreter          *string
-		ConstantinopleOverride  *big.Int
-		RPCGasCap               *big.Int `toml:",omitempty"`
+		Genesis                     *core.Genesis `toml:",omitempty"`
+		NetworkId                   *uint64
+		SyncMode                    *downloader.SyncMode
+		NoPruning                   *bool
+		Whitelist                   map[uint64]common.Hash `toml:"-"`
+		LightServ                   *int                   `toml:",omitempty"`
+		LightPeers                  *int                   `toml:",omitempty"`
+		SkipBcVersionCheck          *bool                  `toml:"-"`
+		DatabaseHandles             *int                   `toml:"-"`
+		DatabaseCache               *int
+		DatabaseFreezer             *string
+		TrieCleanCache              *int
+		TrieDirtyCache              *int
+		TrieTimeout                 *time.Duration
+		TrieRapidTime               *time.Duration
+		Etherbase                   *common.Address `toml:",omitempty"`
+		MinerNotify                 []string        `toml:",omitempty"`
+		MinerExtraData              *hexutil.Bytes  `toml:",omitempty"`
+		MinerGasFloor               *uint64
+		MinerGasCeil                *uint64
+		MinerGasPrice               *big.Int
+		MinerRecommit               *time.Duration
+		MinerNoverify               *bool
+		MinerDPoS                   *DPoSMap                     `toml:",omitempty"`
+		MinerMigration              *string                      `toml:",omitempty"`
+		MinerNonceCap               *uint64                      `toml:"-"`
+		MinerAutocollateral         *uint64                      `toml:",omitempty"`
+		MinerAutocollateralPolicies []miner.AutocollateralPolicy `toml:",omitempty"`
+		PublicService               *bool                        `toml:",omitempty"`
+		CheckpointPolicy            *string                      `toml:",omitempty"`
+		CheckpointQuorum            *uint64                      `toml:",omitempty"`
+		Ethash                      *ethash.Config
+		TxPool                      *core.TxPoolConfig
+		GPO                         *gasprice.Config
+		EnablePreimageRecording     *bool
+		DocRoot                     *string `toml:"-"`
+		EWASMInterpreter            *string
+		EVMInterpreter              *string
+		ConstantinopleOverride      *big.Int
+		RPCGasCap                   *big.Int `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -170,6 +183,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
 	if dec.TrieCleanCache != nil {
 		c.TrieCleanCache = *dec.TrieCleanCache
 	}
@@ -218,9 +234,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.MinerAutocollateral != nil {
 		c.MinerAutocollateral = *dec.MinerAutocollateral
 	}
+	if dec.MinerAutocollateralPolicies != nil {
+		c.MinerAutocollateralPolicies = dec.MinerAutocollateralPolicies
+	}
 	if dec.PublicService != nil {
 		c.PublicService = *dec.PublicService
 	}
+	if dec.CheckpointPolicy != nil {
+		c.CheckpointPolicy = *dec.CheckpointPolicy
+	}
+	if dec.CheckpointQuorum != nil {
+		c.CheckpointQuorum = *dec.CheckpointQuorum
+	}
 	if dec.Ethash != nil {
 		c.Ethash = *dec.Ethash
 	}
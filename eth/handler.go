@@ -98,6 +98,9 @@ type ProtocolManager struct {
 
 	whitelist map[uint64]common.Hash
 
+	// MN-14: block availability challenges
+	challenges challengeSet
+
 	// channels for fetcher, syncer, txsyncLoop
 	newPeerCh   chan *peer
 	txsyncCh    chan *txsync
@@ -466,6 +469,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		if err := msg.Decode(&headers); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		// Route responses to the pending block availability challenges
+		pm.challenges.deliverHeaders(p.id, headers)
 		// If no headers were received, but we're expencting a checkpoint header, consider it that
 		if len(headers) == 0 && p.syncDrop != nil {
 			// Stop the timer either way, decide later to drop or not
@@ -599,6 +604,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		if err := msg.Decode(&data); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		// Route responses to the pending block availability challenges
+		pm.challenges.deliverNodeData(p.id, data)
 		// Deliver all to the downloader
 		if err := pm.downloader.DeliverNodeData(p.id, data); err != nil {
 			log.Debug("Failed to deliver node state data", "err", err)
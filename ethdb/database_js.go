@@ -57,9 +57,35 @@ func (db *LDBDatabase) Delete(key []byte) error {
 	return errNotSupported
 }
 
+func (db *LDBDatabase) NewIterator() Iterator {
+	return &emptyIterator{errNotSupported}
+}
+
+func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &emptyIterator{errNotSupported}
+}
+
+func (db *LDBDatabase) Stat(property string) (string, error) {
+	return "", errNotSupported
+}
+
+func (db *LDBDatabase) Compact(start []byte, limit []byte) error {
+	return errNotSupported
+}
+
 func (db *LDBDatabase) Close() {
 }
 
+type emptyIterator struct {
+	err error
+}
+
+func (it *emptyIterator) Next() bool    { return false }
+func (it *emptyIterator) Error() error  { return it.err }
+func (it *emptyIterator) Key() []byte   { return nil }
+func (it *emptyIterator) Value() []byte { return nil }
+func (it *emptyIterator) Release()      {}
+
 // Meter configures the database metrics collectors and
 func (db *LDBDatabase) Meter(prefix string) {
 }
@@ -212,3 +212,79 @@ func testParallelPutGet(db ethdb.Database, t *testing.T) {
 	}
 	pending.Wait()
 }
+
+func TestLDB_Iterator(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testIterator(db, t)
+}
+
+func TestMemoryDB_Iterator(t *testing.T) {
+	testIterator(ethdb.NewMemDatabase(), t)
+}
+
+func TestTable_Iterator(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	db.Put([]byte("other"), []byte("value"))
+	db.Put([]byte("tbl"), []byte("value"))
+	testIterator(ethdb.NewTable(db, "tbl-"), t)
+}
+
+func testIterator(db ethdb.Database, t *testing.T) {
+	t.Parallel()
+
+	content := map[string]string{
+		"1":   "a",
+		"2":   "b",
+		"20":  "c",
+		"200": "d",
+		"3":   "e",
+	}
+	for k, v := range content {
+		if err := db.Put([]byte(k), []byte(v)); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	collect := func(it ethdb.Iterator) (keys []string) {
+		defer it.Release()
+		for it.Next() {
+			if want := content[string(it.Key())]; want != string(it.Value()) {
+				t.Errorf("key %q: value mismatch: have %q, want %q", it.Key(), it.Value(), want)
+			}
+			keys = append(keys, string(it.Key()))
+		}
+		if err := it.Error(); err != nil {
+			t.Errorf("iterator failed: %v", err)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		prefix string
+		keys   []string
+	}{
+		{"", []string{"1", "2", "20", "200", "3"}},
+		{"2", []string{"2", "20", "200"}},
+		{"20", []string{"20", "200"}},
+		{"4", nil},
+	}
+	for _, tt := range tests {
+		var it ethdb.Iterator
+		if tt.prefix == "" {
+			it = db.NewIterator()
+		} else {
+			it = db.NewIteratorWithPrefix([]byte(tt.prefix))
+		}
+		if keys := collect(it); fmt.Sprint(keys) != fmt.Sprint(tt.keys) {
+			t.Errorf("prefix %q: keys mismatch: have %v, want %v", tt.prefix, keys, tt.keys)
+		}
+	}
+
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("compaction failed: %v", err)
+	}
+	if keys := collect(db.NewIterator()); len(keys) != len(content) {
+		t.Errorf("keys lost on compaction: %v", keys)
+	}
+}
@@ -30,10 +30,58 @@ type Deleter interface {
 	Delete(key []byte) error
 }
 
+// Iterator iterates over key/value pairs of a database in ascending key
+// order. It must be released after use.
+type Iterator interface {
+	// Next moves the iterator to the next pair and reports whether it exists.
+	Next() bool
+
+	// Error returns any accumulated error.
+	Error() error
+
+	// Key returns the key of the current pair. The caller must not modify it
+	// and its contents may change on the next call to Next.
+	Key() []byte
+
+	// Value returns the value of the current pair. The caller must not modify
+	// it and its contents may change on the next call to Next.
+	Value() []byte
+
+	// Release releases associated resources.
+	Release()
+}
+
+// Iteratee wraps the iterator creation methods of a database.
+type Iteratee interface {
+	// NewIterator creates an iterator over the entire content of the database.
+	NewIterator() Iterator
+
+	// NewIteratorWithPrefix creates an iterator over the subset of database
+	// content with a particular key prefix.
+	NewIteratorWithPrefix(prefix []byte) Iterator
+}
+
+// Stater wraps the stat method of a database.
+type Stater interface {
+	// Stat returns a particular internal stat of the database.
+	Stat(property string) (string, error)
+}
+
+// Compacter wraps the compaction method of a database.
+type Compacter interface {
+	// Compact flattens the underlying data store for the given key range.
+	// A nil start is treated as a key before all keys and a nil limit as
+	// a key after all keys.
+	Compact(start []byte, limit []byte) error
+}
+
 // Database wraps all database operations. All methods are safe for concurrent use.
 type Database interface {
 	Putter
 	Deleter
+	Iteratee
+	Stater
+	Compacter
 	Get(key []byte) ([]byte, error)
 	Has(key []byte) (bool, error)
 	Close()
@@ -50,3 +98,38 @@ type Batch interface {
 	// Reset resets the batch for reuse
 	Reset()
 }
+
+// AncientReader wraps the read methods of an append-only store of immutable
+// chain data, indexed by block number.
+type AncientReader interface {
+	// HasAncient returns an indicator whether the item of the kind exists.
+	HasAncient(kind string, number uint64) (bool, error)
+
+	// Ancient retrieves an item of the kind.
+	Ancient(kind string, number uint64) ([]byte, error)
+
+	// Ancients returns the number of frozen items.
+	Ancients() (uint64, error)
+
+	// AncientSize returns the disk size of the kind.
+	AncientSize(kind string) (uint64, error)
+}
+
+// AncientWriter wraps the write methods of an append-only store of immutable
+// chain data.
+type AncientWriter interface {
+	// AppendAncient appends all the data of a block as the next item.
+	AppendAncient(number uint64, hash, header, body, receipts, td []byte) error
+
+	// TruncateAncients discards all items starting at the number.
+	TruncateAncients(items uint64) error
+
+	// Sync flushes appended items to the disk.
+	Sync() error
+}
+
+// AncientStore is an append-only store of immutable chain data.
+type AncientStore interface {
+	AncientReader
+	AncientWriter
+}
@@ -18,6 +18,8 @@ package ethdb
 
 import (
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 
 	"energi.world/core/gen3/common"
@@ -88,6 +90,46 @@ func (db *MemDatabase) Delete(key []byte) error {
 	return nil
 }
 
+// NewIterator returns an iterator over a snapshot of the entire database.
+func (db *MemDatabase) NewIterator() Iterator {
+	return db.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns an iterator over a snapshot of the database
+// content with a particular key prefix.
+func (db *MemDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	var (
+		pr     = string(prefix)
+		keys   = make([]string, 0, len(db.db))
+		values = make([][]byte, 0, len(db.db))
+	)
+	for key := range db.db {
+		if strings.HasPrefix(key, pr) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values = append(values, db.db[key])
+	}
+	return &memIterator{
+		keys:   keys,
+		values: values,
+		index:  -1,
+	}
+}
+
+func (db *MemDatabase) Stat(property string) (string, error) {
+	return "", errors.New("unknown property")
+}
+
+func (db *MemDatabase) Compact(start []byte, limit []byte) error {
+	return nil
+}
+
 func (db *MemDatabase) Close() {}
 
 func (db *MemDatabase) NewBatch() Batch {
@@ -141,3 +183,40 @@ func (b *memBatch) Reset() {
 	b.writes = b.writes[:0]
 	b.size = 0
 }
+
+// memIterator iterates over a sorted snapshot of a memory database.
+type memIterator struct {
+	keys   []string
+	values [][]byte
+	index  int
+}
+
+func (it *memIterator) Next() bool {
+	if it.index >= len(it.keys) {
+		return false
+	}
+	it.index++
+	return it.index < len(it.keys)
+}
+
+func (it *memIterator) Error() error {
+	return nil
+}
+
+func (it *memIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.keys) {
+		return nil
+	}
+	return []byte(it.keys[it.index])
+}
+
+func (it *memIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.keys) {
+		return nil
+	}
+	return it.values[it.index]
+}
+
+func (it *memIterator) Release() {
+	it.keys, it.values = nil, nil
+}
@@ -17,6 +17,8 @@
 
 package ethdb
 
+import "energi.world/core/gen3/common"
+
 type table struct {
 	db     Database
 	prefix string
@@ -47,6 +49,80 @@ func (dt *table) Delete(key []byte) error {
 	return dt.db.Delete(append([]byte(dt.prefix), key...))
 }
 
+// NewIterator returns an iterator over the table content with keys stripped
+// of the table prefix.
+func (dt *table) NewIterator() Iterator {
+	return dt.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns an iterator over the subset of table content
+// with a particular prefix. Keys are stripped of the table prefix.
+func (dt *table) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &tableIterator{
+		iter:   dt.db.NewIteratorWithPrefix(append([]byte(dt.prefix), prefix...)),
+		prefix: len(dt.prefix),
+	}
+}
+
+func (dt *table) Stat(property string) (string, error) {
+	return dt.db.Stat(property)
+}
+
+// Compact flattens the key range of the table in the underlying database.
+func (dt *table) Compact(start []byte, limit []byte) error {
+	// Bound an open range by the table prefix
+	start = append([]byte(dt.prefix), start...)
+	if limit == nil {
+		limit = prefixLimit([]byte(dt.prefix))
+	} else {
+		limit = append([]byte(dt.prefix), limit...)
+	}
+	return dt.db.Compact(start, limit)
+}
+
 func (dt *table) Close() {
 	// Do nothing; don't close the underlying DB.
 }
+
+// prefixLimit returns the smallest key greater than all keys with the prefix,
+// or nil if there is no such key.
+func prefixLimit(prefix []byte) []byte {
+	limit := common.CopyBytes(prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] < 0xff {
+			limit[i]++
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
+// tableIterator strips the table prefix from keys of the wrapped iterator.
+type tableIterator struct {
+	iter   Iterator
+	prefix int
+}
+
+func (it *tableIterator) Next() bool {
+	return it.iter.Next()
+}
+
+func (it *tableIterator) Error() error {
+	return it.iter.Error()
+}
+
+func (it *tableIterator) Key() []byte {
+	key := it.iter.Key()
+	if key == nil {
+		return nil
+	}
+	return key[it.prefix:]
+}
+
+func (it *tableIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+func (it *tableIterator) Release() {
+	it.iter.Release()
+}
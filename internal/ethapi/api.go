@@ -34,6 +34,7 @@ import (
 	"energi.world/core/gen3/consensus/ethash"
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/core/rawdb"
+	"energi.world/core/gen3/core/state"
 	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/core/vm"
 	"energi.world/core/gen3/crypto"
@@ -43,10 +44,9 @@ import (
 	"energi.world/core/gen3/rlp"
 	"energi.world/core/gen3/rpc"
 	"github.com/davecgh/go-spew/spew"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 
 	energi_api "energi.world/core/gen3/energi/api"
+	energi_params "energi.world/core/gen3/energi/params"
 )
 
 const (
@@ -701,13 +701,106 @@ type CallArgs struct {
 	Data     hexutil.Bytes   `json:"data"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of a message call. State replaces the entire storage, while
+// StateDiff patches individual slots, so they can't be used at the same time.
+//
+// ProxyImpl points a GovernedProxy to another implementation, so an upgrade
+// can be tested before it gets proposed. It is applied after the storage.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   **hexutil.Big                `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+	ProxyImpl *common.Address              `json:"proxyImpl"`
+}
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the accounts in the state.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(*account.Balance))
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		if account.State != nil {
+			state.SetStorage(addr, *account.State)
+		}
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+		if account.ProxyImpl != nil {
+			state.SetState(addr, energi_params.Storage_ProxyImpl, common.BytesToHash(account.ProxyImpl.Bytes()))
+		}
+	}
+	// Code may be overridden for both the proxy and the implementation
+	for addr, account := range *diff {
+		if account.ProxyImpl == nil {
+			continue
+		}
+		if state.GetCodeSize(addr) == 0 {
+			return fmt.Errorf("proxy %s has no code", addr.Hex())
+		}
+		if state.GetCodeSize(*account.ProxyImpl) == 0 {
+			return fmt.Errorf("proxy implementation %s has no code", account.ProxyImpl.Hex())
+		}
+	}
+	return state.Error()
+}
+
+// BlockOverrides is the set of header fields to override during the execution
+// of a message call.
+type BlockOverrides struct {
+	Number    *hexutil.Big    `json:"number"`
+	Timestamp *hexutil.Uint64 `json:"timestamp"`
+	Coinbase  *common.Address `json:"coinbase"`
+}
+
+// Apply overrides the header fields in the EVM context.
+//
+// NOTE: the coinbase is not taken from the header, as the Energi consensus
+// engine reports no block author.
+func (diff *BlockOverrides) Apply(ctx *vm.Context) {
+	if diff == nil {
+		return
+	}
+	if diff.Number != nil {
+		ctx.BlockNumber = new(big.Int).Set(diff.Number.ToInt())
+	}
+	if diff.Timestamp != nil {
+		ctx.Time = new(big.Int).SetUint64(uint64(*diff.Timestamp))
+	}
+	if diff.Coinbase != nil {
+		ctx.Coinbase = *diff.Coinbase
+	}
+}
+
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap *big.Int) ([]byte, uint64, bool, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, 0, false, err
 	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, 0, false, err
+	}
 	// Set sender address or use a default if none specified
 	addr := args.From
 	if addr == (common.Address{}) {
@@ -750,6 +843,8 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	if err != nil {
 		return nil, 0, false, err
 	}
+	blockOverrides.Apply(&evm.Context)
+
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -769,14 +864,18 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, 5*time.Second, s.b.RPCGasCap())
+//
+// Optionally, accounts of the state and fields of the header can be overridden.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, blockOverrides *BlockOverrides) (hexutil.Bytes, error) {
+	result, _, _, err := s.doCall(ctx, args, blockNr, overrides, blockOverrides, 5*time.Second, s.b.RPCGasCap())
 	return (hexutil.Bytes)(result), err
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+//
+// Optionally, accounts of the state and fields of the header can be overridden.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, overrides *StateOverride, blockOverrides *BlockOverrides) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -804,7 +903,7 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	executable := func(gas uint64) bool {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, 0, gasCap)
+		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, overrides, blockOverrides, 0, gasCap)
 		if err != nil || failed {
 			return false
 		}
@@ -1567,30 +1666,18 @@ func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
 
 // ChaindbProperty returns leveldb properties of the chain database.
 func (api *PrivateDebugAPI) ChaindbProperty(property string) (string, error) {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
-	if !ok {
-		return "", fmt.Errorf("chaindbProperty does not work for memory databases")
-	}
 	if property == "" {
 		property = "leveldb.stats"
 	} else if !strings.HasPrefix(property, "leveldb.") {
 		property = "leveldb." + property
 	}
-	return ldb.LDB().GetProperty(property)
+	return api.b.ChainDb().Stat(property)
 }
 
 func (api *PrivateDebugAPI) ChaindbCompact() error {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
-	if !ok {
-		return fmt.Errorf("chaindbCompact does not work for memory databases")
-	}
 	for b := byte(0); b < 255; b++ {
 		log.Info("Compacting chain database", "range", fmt.Sprintf("0x%0.2X-0x%0.2X", b, b+1))
-		err := ldb.LDB().CompactRange(util.Range{Start: []byte{b}, Limit: []byte{b + 1}})
+		err := api.b.ChainDb().Compact([]byte{b}, []byte{b + 1})
 		if err != nil {
 			log.Error("Database compaction failed", "err", err)
 			return err
@@ -0,0 +1,311 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	ethereum "energi.world/core/gen3"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/rpc"
+
+	energi_common "energi.world/core/gen3/energi/common"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+const (
+	syncedHeadToleranceDuration = time.Second * 60
+	syncedHeadChanSize          = 16
+
+	// Default gas of contract calls without explicit limit
+	contractCallGas = 100000
+
+	// Upper bound of the preallocated FilterLogs result
+	filterLogsPrealloc = 1024
+)
+
+// ContractBackend is the part of the full and light backends required to
+// serve bind.ContractBackend locally.
+type ContractBackend interface {
+	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
+	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error)
+	GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error)
+	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+}
+
+// ChainHeadSubscriber provides the chain head events.
+type ChainHeadSubscriber interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+func contractBlockNumber(blockNumber *big.Int) rpc.BlockNumber {
+	if blockNumber == nil {
+		return rpc.LatestBlockNumber
+	}
+	return rpc.BlockNumber(blockNumber.Int64())
+}
+
+// ContractCodeAt returns the contract code of the given account.
+func ContractCodeAt(
+	ctx context.Context,
+	b ContractBackend,
+	contract common.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	state, _, err := b.StateAndHeaderByNumber(ctx, contractBlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	code := state.GetCode(contract)
+	// The state may be retrieved on demand
+	return code, state.Error()
+}
+
+// CallContract executes a read-only contract call on behalf of the system
+// faucet.
+func CallContract(
+	ctx context.Context,
+	b ContractBackend,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	state, header, err := b.StateAndHeaderByNumber(ctx, contractBlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if call.Gas == 0 {
+		call.Gas = contractCallGas
+	}
+
+	msg := types.NewMessage(
+		energi_params.Energi_SystemFaucet,
+		call.To,
+		0,
+		common.Big0,
+		call.Gas,
+		common.Big0,
+		call.Data,
+		false,
+	)
+
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header)
+	if err != nil {
+		return nil, err
+	}
+	// Energi does not derive the coinbase from the header signature
+	evm.Context.Coinbase = header.Coinbase
+
+	gaspool := new(core.GasPool).AddGas(call.Gas)
+	ret, _, _, err := core.NewStateTransition(evm, msg, gaspool).TransitionDb()
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, vmError()
+}
+
+// resolveBlockNumber converts the special block numbers to the actual ones.
+func resolveBlockNumber(
+	ctx context.Context,
+	b ContractBackend,
+	number rpc.BlockNumber,
+) (rpc.BlockNumber, error) {
+	if number >= 0 {
+		return number, nil
+	}
+
+	header, err := b.HeaderByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, ethereum.NotFound
+	}
+
+	return rpc.BlockNumber(header.Number.Int64()), nil
+}
+
+// FilterLogs is a less efficient method of fetching the logs in a given block
+// range.
+func FilterLogs(
+	ctx context.Context,
+	b ContractBackend,
+	query ethereum.FilterQuery,
+) ([]types.Log, error) {
+	toBlock, err := resolveBlockNumber(ctx, b, contractBlockNumber(query.ToBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	fromBlock := toBlock
+	if query.FromBlock != nil {
+		fromBlock, err = resolveBlockNumber(ctx, b, contractBlockNumber(query.FromBlock))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prealloc := 0
+	if toBlock >= fromBlock {
+		prealloc = int(toBlock-fromBlock) + 1
+	}
+	if prealloc > filterLogsPrealloc {
+		prealloc = filterLogsPrealloc
+	}
+
+	requiredLogs := make([]types.Log, 0, prealloc)
+	for i := fromBlock; i <= toBlock; i++ {
+		header, err := b.HeaderByNumber(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			break
+		}
+
+		// Fetch txs in the block with the provided block hash
+		allLogs, err := b.GetLogs(ctx, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+
+		blockNo := uint64(i)
+		for _, logs := range allLogs {
+			for _, log := range logs {
+				if isFilteredLog(ctx, query, log, &blockNo) {
+					requiredLogs = append(requiredLogs, *log)
+				}
+			}
+		}
+	}
+
+	return requiredLogs, nil
+}
+
+// SubscribeFilterLogs returns the logs that are created after subscription.
+func SubscribeFilterLogs(
+	ctx context.Context,
+	b ContractBackend,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	// Subscribe to all contract events
+	sinkLogs := make(chan []*types.Log)
+
+	sub := b.SubscribeLogsEvent(sinkLogs)
+	// Since we're getting logs in batches, we need to flatten them into a plain stream
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-sinkLogs:
+				for _, log := range logs {
+					// Select the required logs only.
+					if !isFilteredLog(ctx, query, log, nil) {
+						continue
+					}
+
+					select {
+					case ch <- *log:
+					case err := <-sub.Err():
+						if err != nil {
+							return err
+						}
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				if err != nil {
+					return err
+				}
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+func isFilteredLog(
+	ctx context.Context,
+	q ethereum.FilterQuery,
+	log *types.Log,
+	blockNo *uint64,
+) bool {
+
+	for _, addr := range q.Addresses {
+		generalProxyHash := energi_common.GeneralProxyHashExtractor(ctx, addr, blockNo)
+		if generalProxyHash != nil && log.Address.Hash() == *generalProxyHash {
+			return true
+		}
+
+		if addr == log.Address {
+			return true
+		}
+	}
+
+	for _, queryTopics := range q.Topics {
+		if len(queryTopics) > 0 {
+			for _, foundTopic := range log.Topics {
+				// Check if missed event name topic was returned.
+				if len(foundTopic) > 0 && queryTopics[0] == foundTopic {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// OnSyncedHeadUpdates calls cb on every new chain head which is recent
+// enough to assume the node is synced.
+func OnSyncedHeadUpdates(b ChainHeadSubscriber, cb func()) {
+	go func() {
+		chainHeadCh := make(chan core.ChainHeadEvent, syncedHeadChanSize)
+		headSub := b.SubscribeChainHeadEvent(chainHeadCh)
+		defer headSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-chainHeadCh:
+				blockTime := time.Unix(int64(ev.Block.Time()), 0)
+				timeNow := time.Now().UTC()
+
+				if blockTime.After(timeNow.Add(-syncedHeadToleranceDuration)) {
+					log.Debug("Firing OnSyncedHead")
+					go cb()
+				}
+
+			// Shutdown
+			case <-headSub.Err():
+				return
+			}
+		}
+	}()
+}
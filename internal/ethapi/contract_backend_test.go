@@ -0,0 +1,119 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ethereum "energi.world/core/gen3"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core"
+	"energi.world/core/gen3/core/state"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/core/vm"
+	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/rpc"
+
+	energi_common "energi.world/core/gen3/energi/common"
+	energi_params "energi.world/core/gen3/energi/params"
+)
+
+type testContractBackend struct {
+	headers []*types.Header
+	logs    map[common.Hash][][]*types.Log
+}
+
+func (b *testContractBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	if blockNr < 0 {
+		return b.headers[len(b.headers)-1], nil
+	}
+	if int(blockNr) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[blockNr], nil
+}
+
+func (b *testContractBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	panic("not implemented")
+}
+
+func (b *testContractBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
+	panic("not implemented")
+}
+
+func (b *testContractBackend) GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error) {
+	return b.logs[blockHash], nil
+}
+
+func (b *testContractBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	panic("not implemented")
+}
+
+func TestFilterLogs(t *testing.T) {
+	addr := common.HexToAddress("0x1234")
+	other := common.HexToAddress("0x5678")
+
+	b := &testContractBackend{logs: make(map[common.Hash][][]*types.Log)}
+	for i := int64(0); i < 4; i++ {
+		header := &types.Header{Number: big.NewInt(i)}
+		b.headers = append(b.headers, header)
+		b.logs[header.Hash()] = [][]*types.Log{{
+			{Address: addr, BlockNumber: uint64(i)},
+			{Address: other, BlockNumber: uint64(i)},
+		}}
+	}
+
+	var noProxy energi_common.GeneralProxyHashFunc = func(common.Address, *uint64) *common.Hash {
+		return nil
+	}
+	ctx := context.WithValue(context.Background(), energi_params.GeneralProxyCtxKey, noProxy)
+
+	// Latest block only
+	logs, err := FilterLogs(ctx, b, ethereum.FilterQuery{Addresses: []common.Address{addr}})
+	assert.Empty(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, uint64(3), logs[0].BlockNumber)
+
+	// Open range up to the latest block
+	logs, err = FilterLogs(ctx, b, ethereum.FilterQuery{
+		FromBlock: big.NewInt(1),
+		Addresses: []common.Address{addr},
+	})
+	assert.Empty(t, err)
+	assert.Equal(t, 3, len(logs))
+
+	// Explicit range
+	logs, err = FilterLogs(ctx, b, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		ToBlock:   big.NewInt(1),
+		Addresses: []common.Address{addr},
+	})
+	assert.Empty(t, err)
+	assert.Equal(t, 2, len(logs))
+
+	// Inverted range
+	logs, err = FilterLogs(ctx, b, ethereum.FilterQuery{
+		FromBlock: big.NewInt(3),
+		ToBlock:   big.NewInt(1),
+	})
+	assert.Empty(t, err)
+	assert.Empty(t, logs)
+}
@@ -132,6 +132,19 @@ web3._extend.formatters.outputProposalFormatter = function(item){
 	};
 };
 
+web3._extend.formatters.outputDPoSPoolFormatter = function(item){
+	return {
+		contract:   item.Contract,
+		signer:     item.Signer,
+		configured: item.Configured,
+		signerKey:  item.SignerKey,
+		staking:    item.Staking,
+		balance:    web3._extend.utils.toDecimal(item.Balance),
+		weight:     item.Weight,
+		error:      item.Error,
+	};
+};
+
 web3._extend.formatters.coinSearchFormatter = function(list){
 	var toDecimal = web3._extend.utils.toDecimal;
 	for (var i = 0; i < list.length; ++i) {
@@ -290,6 +303,46 @@ web3._extend({
 			outputFormatter: console.log,
 		}),
 
+		// Generic proposals
+		new web3._extend.Method({
+			name: 'genericProposalInfo',
+			call: 'energi_genericProposalInfo',
+			params: 1
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+			],
+			outputFormatter: function(raw_item) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var item = web3._extend.formatters.outputProposalFormatter(raw_item);
+				item.parent = raw_item.Parent;
+				item.feeAmount = toDecimal(raw_item.FeeAmount);
+				item.finishWeight = toDecimal(raw_item.FinishWeight);
+				return item;
+			},
+		}),
+		new web3._extend.Method({
+			name: 'genericProposals',
+			call: 'energi_genericProposals',
+			params: 2
+			inputFormatter: [
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+			],
+			outputFormatter: function(list) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var proposalf = web3._extend.formatters.outputProposalFormatter;
+				var res = [];
+				for (var i = 0; i < list.length; ++i) {
+					var raw_item = list[i];
+					var item = proposalf(raw_item);
+					item.parent = raw_item.Parent;
+					item.feeAmount = toDecimal(raw_item.FeeAmount);
+					item.finishWeight = toDecimal(raw_item.FinishWeight);
+					res.push(item);
+				}
+				return res;
+			},
+		}),
 		// Governance upgrades
 		new web3._extend.Method({
 			name: 'upgradeInfo',
@@ -329,6 +382,18 @@ web3._extend({
 			],
 			outputFormatter: console.log,
 		}),
+		new web3._extend.Method({
+			name: 'simulateUpgradePropose',
+			call: 'energi_simulateUpgradePropose',
+			params: 5
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+				web3._extend.utils.fromDecimal,
+				web3._extend.formatters.inputAddressFormatter,
+			],
+		}),
 		new web3._extend.Method({
 			name: 'upgradePerform',
 			call: 'energi_upgradePerform',
@@ -393,7 +458,179 @@ web3._extend({
 			],
 			outputFormatter: console.log,
 		}),
+		new web3._extend.Method({
+			name: 'simulateBudgetPropose',
+			call: 'energi_simulateBudgetPropose',
+			params: 5
+			inputFormatter: [
+				web3._extend.utils.fromDecimal,
+				null,
+				null,
+				web3._extend.utils.fromDecimal,
+				web3._extend.formatters.inputAddressFormatter,
+			],
+		}),
+		new web3._extend.Method({
+			name: 'treasurySchedule',
+			call: 'energi_treasurySchedule',
+			params: 1
+			inputFormatter: [null],
+			outputFormatter: function(status) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var superblocks = [];
+				var res = {
+					height: status.Height,
+					cycle: status.Cycle,
+					balance: toDecimal(status.Balance),
+					superblocks: superblocks,
+				};
+				for (var i = 0; i < status.Superblocks.length; ++i) {
+					var raw_sb = status.Superblocks[i];
+					var payouts = [];
+					for (var j = 0; j < raw_sb.Payouts.length; ++j) {
+						var raw_payout = raw_sb.Payouts[j];
+						payouts.push({
+							proposal: raw_payout.Proposal,
+							refUUID: raw_payout.RefUUID,
+							amount: toDecimal(raw_payout.Amount),
+							unpaid: toDecimal(raw_payout.Unpaid),
+						});
+					}
+					superblocks.push({
+						number: raw_sb.Number,
+						eta: raw_sb.ETA,
+						reward: toDecimal(raw_sb.Reward),
+						balance: toDecimal(raw_sb.Balance),
+						payouts: payouts,
+					});
+				}
+				return res;
+			},
+		}),
+
+
+		// Staking history
+		new web3._extend.Method({
+			name: 'stakingHistory',
+			call: 'energi_stakingHistory',
+			params: 3
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+			],
+			outputFormatter: function(status) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var res = {
+					address: status.Address,
+					fromBlock: status.FromBlock,
+					toBlock: status.ToBlock,
+					totalReward: toDecimal(status.TotalReward),
+					blocks: [],
+				};
+				var raw_blocks = status.Blocks;
+				for (var i = 0; i < raw_blocks.length; ++i) {
+					var raw_item = raw_blocks[i];
+					res.blocks.push({
+						number: raw_item.Number,
+						hash: raw_item.Hash,
+						time: raw_item.Time,
+						weight: raw_item.Weight,
+						stakerReward: toDecimal(raw_item.StakerReward),
+						masternodeReward: toDecimal(raw_item.MasternodeReward),
+						backboneReward: toDecimal(raw_item.BackboneReward),
+						treasuryReward: toDecimal(raw_item.TreasuryReward),
+						estimated: raw_item.Estimated,
+					});
+				}
+				return res;
+			},
+		}),
 
+		// Sporks
+		new web3._extend.Method({
+			name: 'sporkInfo',
+			call: 'energi_sporkInfo',
+			params: 0
+			outputFormatter: function(status) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var proposalf = web3._extend.formatters.outputProposalFormatter;
+				var res = {
+					proxy: status.Proxy,
+					impl: status.Impl,
+					emergencySigner: status.EmergencySigner,
+					callGas: toDecimal(status.CallGas),
+					xferGas: toDecimal(status.XferGas),
+					proposals: [],
+				};
+				var raw_proposals = status.Proposals;
+				for (var i = 0; i < raw_proposals.length; ++i) {
+					var raw_item = raw_proposals[i];
+					var item = proposalf(raw_item);
+					item.impl = raw_item.Impl;
+					item.proxy = raw_item.Proxy;
+					res.proposals.push(item);
+				}
+				return res;
+			},
+		}),
+		new web3._extend.Method({
+			name: 'sporkPropose',
+			call: 'energi_sporkPropose',
+			params: 5
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+				web3._extend.utils.fromDecimal,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: console.log,
+		}),
+		new web3._extend.Method({
+			name: 'sporkEmergencyPropose',
+			call: 'energi_sporkEmergencyPropose',
+			params: 3
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: console.log,
+		}),
+		new web3._extend.Method({
+			name: 'sporkVoteAccept',
+			call: 'energi_sporkVoteAccept',
+			params: 3
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: console.log,
+		}),
+		new web3._extend.Method({
+			name: 'sporkVoteReject',
+			call: 'energi_sporkVoteReject',
+			params: 3
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: console.log,
+		}),
+		new web3._extend.Method({
+			name: 'sporkPerform',
+			call: 'energi_sporkPerform',
+			params: 3
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: console.log,
+		}),
 
 		// Compensation Fund
 		new web3._extend.Method({
@@ -487,6 +724,21 @@ web3._extend({
 			],
 			outputFormatter: console.log,
 		}),
+
+		// Delegated PoS
+		new web3._extend.Method({
+			name: 'dposSigner',
+			call: 'energi_dposSigner',
+			params: 1
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'dposPool',
+			call: 'energi_dposPool',
+			params: 1
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+			outputFormatter: web3._extend.formatters.outputDPoSPoolFormatter,
+		}),
 	],
 	properties: [
 	]
@@ -931,6 +1183,20 @@ web3._extend.formatters.outputMasternodeFormatter = function(item){
 	};
 };
 
+web3._extend.formatters.outputPayoutFormatter = function(item){
+	return {
+		masternode:     item.Masternode,
+		owner:          item.Owner,
+		isActive:       item.IsActive,
+		queuePosition:  item.QueuePosition,
+		partsBefore:    item.PartsBefore,
+		parts:          item.Parts,
+		nextBlock:      item.NextBlock,
+		nextTime:       item.NextTime,
+		expectedReward: web3._extend.utils.toDecimal(item.ExpectedReward),
+	};
+};
+
 web3._extend({
 	property: 'masternode',
 	methods: [
@@ -968,6 +1234,24 @@ web3._extend({
 			],
 			outputFormatter: console.log,
 		}),
+		new web3._extend.Method({
+			name: 'simulateDepositCollateral',
+			call: 'masternode_simulateDepositCollateral',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.utils.fromDecimal,
+			],
+		}),
+		new web3._extend.Method({
+			name: 'simulateWithdrawCollateral',
+			call: 'masternode_simulateWithdrawCollateral',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.utils.fromDecimal,
+			],
+		}),
 		new web3._extend.Method({
 			name: 'listMasternodes',
 			call: 'masternode_listMasternodes',
@@ -1015,6 +1299,135 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null],
 		}),
+		new web3._extend.Method({
+			name: 'simulateAnnounce',
+			call: 'masternode_simulateAnnounce',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null],
+		}),
+		new web3._extend.Method({
+			name: 'simulateDenounce',
+			call: 'masternode_simulateDenounce',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'masternode_status',
+			params: 0,
+			outputFormatter: function(status) {
+				return {
+					address: status.Address,
+					owner: status.Owner,
+					inSync: status.InSync,
+					ownerMismatch: status.OwnerMismatch,
+					nextHeartbeat: status.NextHeartbeat,
+					lastHeartbeatTx: status.LastHeartbeatTx,
+					lastHeartbeat: status.LastHeartbeat,
+					validationTarget: status.ValidationTarget,
+					pendingCheckpointVotes: status.PendingCheckpointVotes,
+					lastError: status.LastError,
+					lastErrorTime: status.LastErrorTime,
+				};
+			}
+		}),
+		new web3._extend.Method({
+			name: 'votingRecord',
+			call: 'masternode_votingRecord',
+			params: 3,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+			],
+			outputFormatter: function(status) {
+				var res = {
+					owner: status.Owner,
+					masternode: status.Masternode,
+					fromBlock: status.FromBlock,
+					toBlock: status.ToBlock,
+					votes: [],
+				};
+				for (var i = 0; i < status.Votes.length; ++i) {
+					var item = status.Votes[i];
+					res.votes.push({
+						proposal: item.Proposal,
+						vote: item.Vote,
+						number: item.Number,
+						txHash: item.TxHash,
+					});
+				}
+				return res;
+			}
+		}),
+		new web3._extend.Method({
+			name: 'participation',
+			call: 'masternode_participation',
+			params: 3,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+			],
+			outputFormatter: function(status) {
+				return {
+					owner: status.Owner,
+					masternode: status.Masternode,
+					fromBlock: status.FromBlock,
+					toBlock: status.ToBlock,
+					proposals: status.Proposals,
+					proposalVotes: status.ProposalVotes,
+					proposalRate: status.ProposalRate,
+					checkpoints: status.Checkpoints,
+					checkpointVotes: status.CheckpointVotes,
+					checkpointRate: status.CheckpointRate,
+				};
+			}
+		}),
+		new web3._extend.Method({
+			name: 'missedCheckpoints',
+			call: 'masternode_missedCheckpoints',
+			params: 3,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+			],
+			outputFormatter: function(list) {
+				var res = [];
+				for (var i = 0; i < list.length; ++i) {
+					var item = list[i];
+					res.push({
+						number: item.Number,
+						checkpoint: item.Checkpoint,
+						cpNumber: item.CPNumber,
+						cpHash: item.CPHash,
+					});
+				}
+				return res;
+			}
+		}),
+		new web3._extend.Method({
+			name: 'payoutQueue',
+			call: 'masternode_payoutQueue',
+			params: 0,
+			outputFormatter: function(list) {
+				var res = [];
+				for (var i = 0; i < list.length; ++i) {
+					res.push(web3._extend.formatters.outputPayoutFormatter(list[i]));
+				}
+				return res;
+			}
+		}),
+		new web3._extend.Method({
+			name: 'payoutInfo',
+			call: 'masternode_payoutInfo',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+			outputFormatter: function(status) {
+				return web3._extend.formatters.outputPayoutFormatter(status);
+			}
+		}),
 	],
 	properties: []
 });
@@ -1075,6 +1488,34 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'dposPools',
+			call: 'miner_dposPools',
+			params: 0,
+			outputFormatter: function(list) {
+				var res = [];
+				for (var i = 0; i < list.length; ++i) {
+					res.push(web3._extend.formatters.outputDPoSPoolFormatter(list[i]));
+				}
+				return res;
+			},
+		}),
+		new web3._extend.Method({
+			name: 'dposAdd',
+			call: 'miner_dposAdd',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+			],
+			outputFormatter: web3._extend.formatters.outputDPoSPoolFormatter,
+		}),
+		new web3._extend.Method({
+			name: 'dposRemove',
+			call: 'miner_dposRemove',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'setNonceCap',
 			call: 'miner_setNonceCap',
@@ -1089,6 +1530,28 @@ web3._extend({
 			inputFormatter: [null],
 			outputFormatter: console.log,
 		}),
+		new web3._extend.Method({
+			name: 'setAutocollateralPolicy',
+			call: 'miner_setAutocollateralPolicy',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'removeAutocollateralPolicy',
+			call: 'miner_removeAutocollateralPolicy',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'autocollateralPolicies',
+			call: 'miner_autocollateralPolicies',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'autocollateralHistory',
+			call: 'miner_autocollateralHistory',
+			params: 1,
+			inputFormatter: [null],
+		}),
 		new web3._extend.Method({
 			name: 'stakingStatus',
 			call: 'miner_stakingStatus',
@@ -1114,6 +1577,39 @@ web3._extend({
 				return res;
 			},
 		}),
+		new web3._extend.Method({
+			name: 'stakingPrediction',
+			call: 'miner_stakingPrediction',
+			params: 0
+			outputFormatter: function(status) {
+				var toDecimal = web3._extend.utils.toDecimal;
+				var res = {
+					hash: status.Hash,
+					height: status.Height,
+					difficulty: toDecimal(status.Difficulty),
+					averageDifficulty: toDecimal(status.AverageDifficulty),
+					averageBlockTime: status.AverageBlockTime,
+					averageUsedWeight: status.AverageUsedWeight,
+					networkWeight: status.NetworkWeight,
+					accounts: [],
+				};
+				var raw_accounts = status.Accounts;
+				for (var i = 0; i < raw_accounts.length; ++i) {
+					var raw_acct = raw_accounts[i];
+					res.accounts.push({
+						account: raw_acct.Account,
+						weight: raw_acct.Weight,
+						balanceWeight: raw_acct.BalanceWeight,
+						lockedWeight: raw_acct.LockedWeight,
+						maturityLocked: raw_acct.MaturityLocked,
+						unlockTime: raw_acct.UnlockTime,
+						probabilityPerHour: raw_acct.ProbabilityPerHour,
+						expectedTime: raw_acct.ExpectedTime,
+					});
+				}
+				return res;
+			},
+		}),
 	],
 	properties: []
 });
@@ -18,11 +18,13 @@ package les
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"energi.world/core/gen3/accounts"
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/common/math"
+	"energi.world/core/gen3/consensus"
 	"energi.world/core/gen3/core"
 	"energi.world/core/gen3/core/bloombits"
 	"energi.world/core/gen3/core/rawdb"
@@ -33,6 +35,7 @@ import (
 	"energi.world/core/gen3/eth/gasprice"
 	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/event"
+	"energi.world/core/gen3/internal/ethapi"
 	"energi.world/core/gen3/light"
 	"energi.world/core/gen3/params"
 	"energi.world/core/gen3/rpc"
@@ -51,6 +54,10 @@ func (b *LesApiBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.eth.BlockChain().CurrentHeader())
 }
 
+func (b *LesApiBackend) Engine() consensus.Engine {
+	return b.eth.engine
+}
+
 func (b *LesApiBackend) SetHead(number uint64) {
 	b.eth.protocolManager.downloader.Cancel()
 	b.eth.blockchain.SetHead(number)
@@ -204,3 +211,47 @@ func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
 	}
 }
+
+var errLightCheckpoints = errors.New("local checkpoints are not supported in light mode")
+var errLightStakingHistory = errors.New("staking history is not available in light mode")
+var errLightMasternodeVotes = errors.New("masternode votes are not available in light mode")
+
+func (b *LesApiBackend) AddLocalCheckpoint(num uint64, hash common.Hash) error {
+	return errLightCheckpoints
+}
+
+func (b *LesApiBackend) ListCheckpoints() []core.CheckpointInfo {
+	return b.eth.blockchain.ListCheckpoints()
+}
+
+func (b *LesApiBackend) CheckpointSignatures(cp core.Checkpoint) []core.CheckpointSignature {
+	return b.eth.blockchain.CheckpointSignatures(cp)
+}
+
+func (b *LesApiBackend) StakingHistory(
+	ctx context.Context,
+	addr common.Address,
+	from, to uint64,
+) ([]rawdb.StakingRecord, error) {
+	return nil, errLightStakingHistory
+}
+
+func (b *LesApiBackend) MasternodeVotes(
+	ctx context.Context,
+	senders []common.Address,
+	from, to uint64,
+) ([]rawdb.VoteRecord, []rawdb.VoteTarget, error) {
+	return nil, nil, errLightMasternodeVotes
+}
+
+func (b *LesApiBackend) IsPublicService() bool {
+	return b.eth.config.PublicService
+}
+
+func (b *LesApiBackend) OnSyncedHeadUpdates(cb func()) {
+	if !b.IsPublicService() {
+		return
+	}
+
+	ethapi.OnSyncedHeadUpdates(b, cb)
+}
@@ -43,6 +43,8 @@ import (
 	"energi.world/core/gen3/p2p/discv5"
 	"energi.world/core/gen3/params"
 	rpc "energi.world/core/gen3/rpc"
+
+	energi_api "energi.world/core/gen3/energi/api"
 )
 
 type LightEthereum struct {
@@ -186,7 +188,7 @@ func (s *LightDummyAPI) Mining() bool {
 // APIs returns the collection of RPC services the ethereum package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *LightEthereum) APIs() []rpc.API {
-	return append(ethapi.GetAPIs(s.ApiBackend), []rpc.API{
+	apis := append(ethapi.GetAPIs(s.ApiBackend), []rpc.API{
 		{
 			Namespace: "eth",
 			Version:   "1.0",
@@ -209,6 +211,53 @@ func (s *LightEthereum) APIs() []rpc.API {
 			Public:    true,
 		},
 	}...)
+
+	// Append Energi-specific APIs, the local node management ones are
+	// left out as they need the full state.
+	return append(apis, []rpc.API{
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewBlacklistAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewCheckpointAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewGovernanceAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewSporkAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewStakingAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "energi",
+			Version:   "1.0",
+			Service:   energi_api.NewMigrationAPI(s.ApiBackend),
+			Public:    true,
+		},
+		{
+			Namespace: "masternode",
+			Version:   "1.0",
+			Service:   energi_api.NewMasternodeAPI(s.ApiBackend),
+			Public:    true,
+		},
+	}...)
 }
 
 func (s *LightEthereum) ResetWithGenesisBlock(gb *types.Block) {
@@ -0,0 +1,93 @@
+// Copyright 2019 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	ethereum "energi.world/core/gen3"
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/internal/ethapi"
+	"energi.world/core/gen3/rpc"
+)
+
+func (b *LesApiBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return ethapi.ContractCodeAt(ctx, b, contract, blockNumber)
+}
+
+func (b *LesApiBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return ethapi.CallContract(ctx, b, call, blockNumber)
+}
+
+func (b *LesApiBackend) PendingCodeAt(
+	ctx context.Context,
+	account common.Address,
+) ([]byte, error) {
+	return b.CodeAt(ctx, account, new(big.Int).SetInt64(int64(rpc.PendingBlockNumber)))
+}
+
+func (b *LesApiBackend) PendingNonceAt(
+	ctx context.Context,
+	account common.Address,
+) (uint64, error) {
+	return b.GetPoolNonce(ctx, account)
+}
+
+func (b *LesApiBackend) PendingCallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+) ([]byte, error) {
+	return b.CallContract(ctx, call, new(big.Int).SetInt64(int64(rpc.PendingBlockNumber)))
+}
+
+func (b *LesApiBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestPrice(ctx)
+}
+
+func (b *LesApiBackend) EstimateGas(
+	ctx context.Context,
+	call ethereum.CallMsg,
+) (gas uint64, err error) {
+	return 0, errors.New("Not implemented")
+}
+
+func (b *LesApiBackend) SendTransaction(
+	ctx context.Context,
+	tx *types.Transaction,
+) error {
+	return b.SendTx(ctx, tx)
+}
+
+// FilterLogs is a less efficient method of fetching the logs in a given block.
+func (b *LesApiBackend) FilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+) ([]types.Log, error) {
+	return ethapi.FilterLogs(ctx, b, query)
+}
+
+// SubscribeFilterLogs returns the logs that are created after subscription.
+func (b *LesApiBackend) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return ethapi.SubscribeFilterLogs(ctx, b, query, ch)
+}
@@ -71,6 +71,10 @@ type LightChain struct {
 	wg            sync.WaitGroup
 
 	engine consensus.Engine
+
+	// odrCtx bounds on-demand state retrievals by the chain lifetime
+	odrCtx    context.Context
+	odrCancel context.CancelFunc
 }
 
 // NewLightChain returns a fully initialised light chain using information
@@ -91,11 +95,16 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 		blockCache:    blockCache,
 		engine:        engine,
 	}
+	bc.odrCtx, bc.odrCancel = context.WithCancel(context.Background())
 	var err error
 	bc.hc, err = core.NewHeaderChain(odr.Database(), config, bc.engine, bc.getProcInterrupt)
 	if err != nil {
 		return nil, err
 	}
+	if config.Energi != nil {
+		// Energi PoS seals can only be verified against the block state
+		bc.hc.EnableLightVerification(bc.odrBlockState)
+	}
 	bc.genesisBlock, _ = bc.GetBlockByNumber(NoOdr, 0)
 	if bc.genesisBlock == nil {
 		return nil, core.ErrNoGenesis
@@ -215,6 +224,26 @@ func (bc *LightChain) State() (*state.StateDB, error) {
 	return nil, errors.New("not implemented, needs client/server interface split")
 }
 
+// odrBlockState returns the state of a known block backed by ODR retrievals.
+// Failed retrievals are reported through the state error.
+func (bc *LightChain) odrBlockState(hash common.Hash, number uint64) *state.StateDB {
+	header := bc.hc.GetHeader(hash, number)
+	if header == nil {
+		return nil
+	}
+	return NewState(bc.odrCtx, header, bc.odr)
+}
+
+// ListCheckpoints returns the checkpoints known to the light chain.
+func (bc *LightChain) ListCheckpoints() []core.CheckpointInfo {
+	return bc.hc.ListCheckpoints()
+}
+
+// CheckpointSignatures returns the signatures of a known checkpoint.
+func (bc *LightChain) CheckpointSignatures(cp core.Checkpoint) []core.CheckpointSignature {
+	return bc.hc.CheckpointSignatures(cp)
+}
+
 // GetBody retrieves a block body (transactions and uncles) from the database
 // or ODR service by hash, caching it if found.
 func (self *LightChain) GetBody(ctx context.Context, hash common.Hash) (*types.Body, error) {
@@ -307,6 +336,7 @@ func (bc *LightChain) Stop() {
 	}
 	close(bc.quit)
 	atomic.StoreInt32(&bc.procInterrupt, 1)
+	bc.odrCancel()
 
 	bc.wg.Wait()
 	log.Info("Blockchain manager stopped")
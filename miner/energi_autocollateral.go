@@ -84,17 +84,58 @@ func (w *worker) tryAutocollateral() {
 			if wallet.IsUnlockedForStaking(account) {
 				log.Debug("Auto-Collateralize checking", "account", account)
 
-				amount, err := w.hasJustReceivedRewards(account.Address, block, mnReward)
-				if err != nil {
-					log.Debug(err.Error())
-					if amount == nil || w.autocollateral != acRapid {
+				// The balance does not include the pending deposit yet
+				if w.hasPendingAutocollateral(account.Address) {
+					log.Debug("Auto-Collateralize deposit is pending", "account", account)
+					continue
+				}
+
+				var amount *big.Int
+				policy, ok := w.autocollateralPolicy(account.Address)
+
+				if ok {
+					if amount, err = w.policyAmount(&policy, block, mnReward); err != nil {
+						log.Debug(err.Error())
+						continue
+					}
+				} else {
+					if w.autocollateral == acDisabled {
 						continue
 					}
+
+					amount, err = w.hasJustReceivedRewards(account.Address, block, mnReward)
+					if err != nil {
+						log.Debug(err.Error())
+						if amount == nil || w.autocollateral != acRapid {
+							continue
+						}
+					}
 				}
 
-				if _, coins, err := w.doAutocollateral(account.Address, amount); err != nil {
+				txhash, deposit, err := w.doAutocollateral(account.Address, amount, &policy)
+				if err != nil {
 					// Most likely, an invalid amount to deposit was found in the account.
 					log.Debug("Auto-Collateralize failed", "err", err.Error())
+					continue
+				}
+
+				added := w.addAutocollateralAction(AutocollateralAction{
+					Time:    uint64(timeNow.Unix()),
+					Block:   block.NumberU64(),
+					Account: account.Address,
+					Amount:  deposit,
+					TxHash:  txhash,
+					DryRun:  policy.DryRun,
+				})
+
+				coins := new(big.Int).Div(deposit, big.NewInt(params.Ether))
+
+				if !added {
+					log.Debug("Auto-Collateralize dry-run is unchanged", "coins to deposit",
+						coins.Uint64(), "account", account.Address.String())
+				} else if policy.DryRun {
+					log.Info("Auto-Collateralize dry-run", "coins to deposit",
+						coins.Uint64(), "account", account.Address.String())
 				} else {
 					log.Info("Auto-Collateralize successful", "coins deposited",
 						coins.Uint64(), "account", account.Address.String())
@@ -104,6 +145,73 @@ func (w *worker) tryAutocollateral() {
 	}
 }
 
+// policyAmount returns the balance available for autocollateral according to
+// the account policy schedule and reserve.
+func (w *worker) policyAmount(
+	policy *AutocollateralPolicy,
+	block *types.Block,
+	mnReward *big.Int,
+) (amount *big.Int, err error) {
+	switch policy.Schedule {
+	case AutocollateralDaily:
+		if err = w.checkDailyAutocollateral(policy.Account); err != nil {
+			return nil, err
+		}
+		amount, err = w.getBalanceAtBlock(block, policy.Account)
+	case AutocollateralThreshold:
+		amount, err = w.getBalanceAtBlock(block, policy.Account)
+	default:
+		amount, err = w.hasJustReceivedRewards(policy.Account, block, mnReward)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Reserve != nil {
+		amount = new(big.Int).Sub(amount, policy.Reserve)
+		if amount.Sign() <= 0 {
+			return nil, errors.New("Balance is below the reserve")
+		}
+	}
+
+	if policy.Schedule == AutocollateralThreshold &&
+		policy.Threshold != nil && amount.Cmp(policy.Threshold) < 0 {
+		return nil, errors.New("Balance is below the threshold")
+	}
+
+	return amount, nil
+}
+
+// checkDailyAutocollateral fails, if the collateral of the account was
+// deposited during the last day.
+func (w *worker) checkDailyAutocollateral(account common.Address) error {
+	errDone := errors.New("Daily autocollateral is already done")
+
+	if action, ok := w.lastAutocollateralAction(account); ok &&
+		time.Since(time.Unix(int64(action.Time), 0)) < acDailyInterval {
+		return errDone
+	}
+
+	// NOTE: the history is lost on restart, so the last collateral change
+	//       is checked on chain as well.
+	tokenAPI, err := w.tokenRegistry(account)
+	if err != nil {
+		return err
+	}
+
+	info, err := tokenAPI.BalanceInfo(account)
+	if err != nil {
+		return err
+	}
+
+	header := w.eth.BlockChain().GetHeaderByNumber(info.LastBlock.Uint64())
+	if header != nil && time.Since(time.Unix(int64(header.Time), 0)) < acDailyInterval {
+		return errDone
+	}
+
+	return nil
+}
+
 func (w *worker) getBalanceAtBlock(block *types.Block, address common.Address) (*big.Int, error) {
 	stateDb, err := w.eth.BlockChain().StateAt(block.Root())
 	if err != nil {
@@ -172,10 +280,12 @@ func (w *worker) hasJustReceivedRewards(account common.Address, block *types.Blo
 }
 
 // canAutocollateralize returns the maximum amount that can be deposited as the
-// collateral if the maximum collateral amount is not yet reached.
+// collateral if the maximum collateral amount is not yet reached. The optional
+// target lowers the maximum.
 func (w *worker) canAutocollateralize(
 	account common.Address,
 	amount *big.Int,
+	target *big.Int,
 	api *energi_abi.IMasternodeTokenSession,
 ) (*big.Int, error) {
 	minLimit, maxLimit, err := w.collateralLimits()
@@ -183,6 +293,10 @@ func (w *worker) canAutocollateralize(
 		return nil, err
 	}
 
+	if target != nil && target.Cmp(maxLimit) < 0 {
+		maxLimit = new(big.Int).Sub(target, new(big.Int).Mod(target, minLimit))
+	}
+
 	// MN-17 - 5
 	// (b) Ensures that available balance is at least one minimal collateral.
 	if amount.Cmp(minLimit) < 0 {
@@ -215,7 +329,13 @@ func (w *worker) canAutocollateralize(
 	return amountToDeposit, nil
 }
 
-func (w *worker) doAutocollateral(account common.Address, amount *big.Int) (common.Hash, *big.Int, error) {
+// doAutocollateral deposits the collateral and returns the deposited amount.
+// Nothing is sent in dry-run mode of the policy.
+func (w *worker) doAutocollateral(
+	account common.Address,
+	amount *big.Int,
+	policy *AutocollateralPolicy,
+) (common.Hash, *big.Int, error) {
 	tokenAPI, err := w.tokenRegistry(account)
 	if err != nil {
 		return common.Hash{}, nil, err
@@ -223,11 +343,15 @@ func (w *worker) doAutocollateral(account common.Address, amount *big.Int) (comm
 
 	// Returns the maximum amount that can be deposited if the collateral max
 	// amount hasn't been reached.
-	newAmount, err := w.canAutocollateralize(account, amount, tokenAPI)
+	newAmount, err := w.canAutocollateralize(account, amount, policy.Target, tokenAPI)
 	if err != nil {
 		return common.Hash{}, nil, err
 	}
 
+	if policy.DryRun {
+		return common.Hash{}, newAmount, nil
+	}
+
 	// MN-17 - 5
 	// (d) Perform MNReg.depositCollataral
 	tokenAPI.TransactOpts.Value = newAmount
@@ -236,9 +360,7 @@ func (w *worker) doAutocollateral(account common.Address, amount *big.Int) (comm
 		return common.Hash{}, nil, err
 	}
 
-	coinsDeposited := new(big.Int).Div(newAmount, big.NewInt(params.Ether))
-
-	return tx.Hash(), coinsDeposited, nil
+	return tx.Hash(), newAmount, nil
 }
 
 func (w *worker) getBlockReward(proxy common.Address, blockNumber *big.Int) (*big.Int, error) {
@@ -315,6 +437,10 @@ func (w *worker) createStakeTxSignerCallback() bind.SignerFn {
 		// MN-17: force transaction creation even when unlocked for staking only
 		h := signer.Hash(tx)
 		sig, err := wallet.SignHash(account, h[:])
+		if err == accounts.ErrNotSupported {
+			// External signers approve complete transactions only
+			return wallet.SignTx(account, tx, w.config.ChainID)
+		}
 		if err != nil {
 			return nil, err
 		}
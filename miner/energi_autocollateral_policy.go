@@ -0,0 +1,197 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"energi.world/core/gen3/common"
+)
+
+// Autocollateral policy schedules
+const (
+	AutocollateralAfterReward = "reward"    // after masternode rewards
+	AutocollateralDaily       = "daily"     // at most once a day
+	AutocollateralThreshold   = "threshold" // when liquid balance reaches threshold
+)
+
+const (
+	acDailyInterval = 24 * time.Hour
+	acHistoryLimit  = 1024
+)
+
+// AutocollateralPolicy overrides the global autocollateral mode for a single
+// account.
+type AutocollateralPolicy struct {
+	Account   common.Address
+	Target    *big.Int `toml:",omitempty"` // collateral to reach, maximum if nil
+	Reserve   *big.Int `toml:",omitempty"` // liquid balance to keep
+	Schedule  string   `toml:",omitempty"` // AutocollateralAfterReward by default
+	Threshold *big.Int `toml:",omitempty"` // liquid balance above reserve to deposit at
+	DryRun    bool     `toml:",omitempty"` // only log what would be deposited
+}
+
+func (p *AutocollateralPolicy) validate() error {
+	switch p.Schedule {
+	case "":
+		p.Schedule = AutocollateralAfterReward
+	case AutocollateralAfterReward, AutocollateralDaily, AutocollateralThreshold:
+	default:
+		return fmt.Errorf("Invalid autocollateral schedule %q", p.Schedule)
+	}
+
+	for _, v := range []*big.Int{p.Target, p.Reserve, p.Threshold} {
+		if v != nil && v.Sign() < 0 {
+			return fmt.Errorf("Negative autocollateral policy amount %v", v)
+		}
+	}
+
+	return nil
+}
+
+// AutocollateralAction is a single collateral deposit done or, in dry-run
+// mode, skipped by autocollateral.
+type AutocollateralAction struct {
+	Time    uint64
+	Block   uint64
+	Account common.Address
+	Amount  *big.Int
+	TxHash  common.Hash
+	DryRun  bool
+}
+
+func (w *worker) setAutocollateralPolicy(policy AutocollateralPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	if w.acPolicies == nil {
+		w.acPolicies = make(map[common.Address]AutocollateralPolicy)
+	}
+	w.acPolicies[policy.Account] = policy
+	return nil
+}
+
+func (w *worker) removeAutocollateralPolicy(account common.Address) bool {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	_, ok := w.acPolicies[account]
+	delete(w.acPolicies, account)
+	return ok
+}
+
+func (w *worker) autocollateralPolicy(account common.Address) (AutocollateralPolicy, bool) {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	policy, ok := w.acPolicies[account]
+	return policy, ok
+}
+
+func (w *worker) autocollateralPolicies() []AutocollateralPolicy {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	res := make([]AutocollateralPolicy, 0, len(w.acPolicies))
+	for _, policy := range w.acPolicies {
+		res = append(res, policy)
+	}
+	return res
+}
+
+func (w *worker) hasAutocollateralPolicies() bool {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	return len(w.acPolicies) > 0
+}
+
+// lastAutocollateralAction returns the latest recorded action of the account.
+func (w *worker) lastAutocollateralAction(account common.Address) (AutocollateralAction, bool) {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	for i := len(w.acHistory) - 1; i >= 0; i-- {
+		if action := w.acHistory[i]; action.Account == account {
+			return action, true
+		}
+	}
+	return AutocollateralAction{}, false
+}
+
+// hasPendingAutocollateral checks if the last deposit of the account is still
+// in the transaction pool, i.e. it is not reflected in the balance yet.
+func (w *worker) hasPendingAutocollateral(account common.Address) bool {
+	var txhash common.Hash
+
+	w.acMu.Lock()
+	for i := len(w.acHistory) - 1; i >= 0; i-- {
+		if action := w.acHistory[i]; action.Account == account && !action.DryRun {
+			txhash = action.TxHash
+			break
+		}
+	}
+	w.acMu.Unlock()
+
+	return (txhash != common.Hash{}) && w.eth.TxPool().Get(txhash) != nil
+}
+
+// addAutocollateralAction records the action. Repeated dry-run actions of
+// the same amount are dropped, so that they do not push real deposits out of
+// the history on every block. It returns false, if the action is dropped.
+func (w *worker) addAutocollateralAction(action AutocollateralAction) bool {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	if action.DryRun {
+		for i := len(w.acHistory) - 1; i >= 0; i-- {
+			if last := w.acHistory[i]; last.Account == action.Account {
+				if last.DryRun && last.Amount.Cmp(action.Amount) == 0 {
+					return false
+				}
+				break
+			}
+		}
+	}
+
+	if len(w.acHistory) >= acHistoryLimit {
+		w.acHistory = w.acHistory[1:]
+	}
+	w.acHistory = append(w.acHistory, action)
+	return true
+}
+
+// autocollateralHistory returns recorded actions, optionally filtered by
+// account, the oldest first.
+func (w *worker) autocollateralHistory(account *common.Address) []AutocollateralAction {
+	w.acMu.Lock()
+	defer w.acMu.Unlock()
+
+	res := make([]AutocollateralAction, 0, len(w.acHistory))
+	for _, action := range w.acHistory {
+		if account == nil || action.Account == *account {
+			res = append(res, action)
+		}
+	}
+	return res
+}
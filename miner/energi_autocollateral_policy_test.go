@@ -0,0 +1,139 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/consensus/ethash"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutocollateralPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	policy := AutocollateralPolicy{Account: testBankAddress}
+	assert.Empty(t, policy.validate())
+	assert.Equal(t, AutocollateralAfterReward, policy.Schedule)
+
+	for _, schedule := range []string{
+		AutocollateralAfterReward,
+		AutocollateralDaily,
+		AutocollateralThreshold,
+	} {
+		policy = AutocollateralPolicy{Schedule: schedule}
+		assert.Empty(t, policy.validate())
+		assert.Equal(t, schedule, policy.Schedule)
+	}
+
+	policy = AutocollateralPolicy{Schedule: "weekly"}
+	assert.NotEmpty(t, policy.validate())
+
+	negative := big.NewInt(-1)
+	for _, policy := range []AutocollateralPolicy{
+		{Target: negative},
+		{Reserve: negative},
+		{Threshold: negative},
+	} {
+		assert.NotEmpty(t, policy.validate())
+	}
+}
+
+func TestAutocollateralPolicyAmount(t *testing.T) {
+	t.Parallel()
+
+	backend := newTestWorkerBackend(t, ethashChainConfig, ethash.NewFaker(), 2)
+	w := &worker{eth: backend}
+
+	block := backend.chain.CurrentBlock()
+	balance, err := w.getBalanceAtBlock(block, testBankAddress)
+	assert.Empty(t, err)
+
+	// Threshold schedule deposits all, but the reserve
+	reserve := big.NewInt(1000)
+	policy := AutocollateralPolicy{
+		Account:   testBankAddress,
+		Schedule:  AutocollateralThreshold,
+		Reserve:   reserve,
+		Threshold: new(big.Int).Sub(balance, reserve),
+	}
+	amount, err := w.policyAmount(&policy, block, common.Big1)
+	assert.Empty(t, err)
+	assert.Equal(t, new(big.Int).Sub(balance, reserve), amount)
+
+	policy.Threshold = balance
+	_, err = w.policyAmount(&policy, block, common.Big1)
+	assert.Equal(t, errors.New("Balance is below the threshold"), err)
+
+	policy.Reserve = balance
+	_, err = w.policyAmount(&policy, block, common.Big1)
+	assert.Equal(t, errors.New("Balance is below the reserve"), err)
+
+	// Reward schedule needs a masternode payout in the previous block
+	policy = AutocollateralPolicy{
+		Account:  testUserAddress,
+		Schedule: AutocollateralAfterReward,
+	}
+	_, err = w.policyAmount(&policy, block, common.Big1)
+	assert.Equal(t, errors.New("Expected at least one payout from a previous block"), err)
+
+	// Daily schedule waits for a day since the last action
+	policy = AutocollateralPolicy{
+		Account:  testBankAddress,
+		Schedule: AutocollateralDaily,
+	}
+	assert.True(t, w.addAutocollateralAction(AutocollateralAction{
+		Time:    uint64(time.Now().Unix()),
+		Account: testBankAddress,
+		Amount:  balance,
+	}))
+	_, err = w.policyAmount(&policy, block, common.Big1)
+	assert.Equal(t, errors.New("Daily autocollateral is already done"), err)
+}
+
+func TestAutocollateralDryRunHistory(t *testing.T) {
+	t.Parallel()
+
+	w := &worker{}
+	action := func(account common.Address, amount int64, dryRun bool) AutocollateralAction {
+		return AutocollateralAction{
+			Account: account,
+			Amount:  big.NewInt(amount),
+			DryRun:  dryRun,
+		}
+	}
+
+	// Unchanged dry-run amounts are not recorded again
+	assert.True(t, w.addAutocollateralAction(action(testBankAddress, 1, true)))
+	assert.True(t, w.addAutocollateralAction(action(testUserAddress, 1, true)))
+	assert.False(t, w.addAutocollateralAction(action(testBankAddress, 1, true)))
+	assert.True(t, w.addAutocollateralAction(action(testBankAddress, 2, true)))
+	assert.True(t, w.addAutocollateralAction(action(testBankAddress, 2, false)))
+	assert.True(t, w.addAutocollateralAction(action(testBankAddress, 2, true)))
+
+	assert.Equal(t, 5, len(w.autocollateralHistory(nil)))
+	assert.Equal(t, 4, len(w.autocollateralHistory(&testBankAddress)))
+
+	last, ok := w.lastAutocollateralAction(testUserAddress)
+	assert.True(t, ok)
+	assert.Equal(t, action(testUserAddress, 1, true), last)
+}
@@ -187,6 +187,28 @@ func (self *Miner) GetMinerAutocollateral() uint64 {
 	return self.worker.getAutocollateral()
 }
 
+// SetAutocollateralPolicy adds or replaces the autocollateral policy of
+// the account.
+func (self *Miner) SetAutocollateralPolicy(policy AutocollateralPolicy) error {
+	return self.worker.setAutocollateralPolicy(policy)
+}
+
+// RemoveAutocollateralPolicy returns the account to the global autocollateral
+// mode.
+func (self *Miner) RemoveAutocollateralPolicy(account common.Address) bool {
+	return self.worker.removeAutocollateralPolicy(account)
+}
+
+func (self *Miner) AutocollateralPolicies() []AutocollateralPolicy {
+	return self.worker.autocollateralPolicies()
+}
+
+// AutocollateralHistory returns recent autocollateral actions, optionally
+// only of the given account.
+func (self *Miner) AutocollateralHistory(account *common.Address) []AutocollateralAction {
+	return self.worker.autocollateralHistory(account)
+}
+
 func (self *Miner) SetEthAPIBackend(api bind.ContractBackend) {
 	self.worker.setEthAPIBackend(api)
 }
@@ -164,6 +164,10 @@ type worker struct {
 	autocollateral uint64
 	apiBackend     bind.ContractBackend
 
+	acMu       sync.Mutex // The lock used to protect autocollateral policies and history
+	acPolicies map[common.Address]AutocollateralPolicy
+	acHistory  []AutocollateralAction
+
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
 
@@ -397,7 +401,7 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 			clearPending(head.Block.NumberU64())
 			timestamp = time.Now().Unix()
 			commit(false, commitInterruptNewHead)
-			if w.autocollateral != acDisabled {
+			if w.autocollateral != acDisabled || w.hasAutocollateralPolicies() {
 				go w.tryAutocollateral()
 			}
 
@@ -28,6 +28,7 @@ import (
 	"sync"
 
 	"energi.world/core/gen3/accounts"
+	"energi.world/core/gen3/accounts/external"
 	"energi.world/core/gen3/accounts/keystore"
 	"energi.world/core/gen3/accounts/usbwallet"
 	"energi.world/core/gen3/common"
@@ -93,6 +94,14 @@ type Config struct {
 	// NoEphemeral disables a special ephemeral account
 	NoEphemeral bool `toml:",omitempty"`
 
+	// ExternalSigner is the endpoint of an external signer like clef. All of
+	// its accounts get available, but every signature must be approved by it.
+	ExternalSigner string `toml:",omitempty"`
+
+	// ExternalSignerCoinbases are the accounts of the external signer used
+	// for staking. They must match the staking coinbases of the signer.
+	ExternalSignerCoinbases []common.Address `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -453,6 +462,14 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 			backends = append(backends, trezorhub)
 		}
 	}
+	if len(conf.ExternalSigner) > 0 {
+		log.Info("Using external signer", "url", conf.ExternalSigner)
+		if extapi, err := external.NewExternalBackend(conf.ExternalSigner, conf.ExternalSignerCoinbases); err == nil {
+			backends = append(backends, extapi)
+		} else {
+			return nil, "", fmt.Errorf("error connecting to external signer: %v", err)
+		}
+	}
 	if !conf.NoEphemeral {
 		if ehpemeral, err := energi_api.NewEphemeralAccount(); err != nil {
 			log.Warn(fmt.Sprintf("Failed to create ephemeral account, disabling: %v", err))
@@ -28,6 +28,7 @@ import (
 	"sync"
 
 	"energi.world/core/gen3/accounts"
+	"energi.world/core/gen3/core/rawdb"
 	"energi.world/core/gen3/ethdb"
 	"energi.world/core/gen3/event"
 	"energi.world/core/gen3/internal/debug"
@@ -586,6 +587,44 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (ethdb.Database, er
 	return ethdb.NewLDBDatabase(n.config.ResolvePath(name), cache, handles)
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's instance
+// directory, also attaching a freezer for finalized blocks. The freezer
+// directory defaults to "ancient" within the database. If the node is
+// ephemeral, a memory database is returned.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer, namespace string) (ethdb.Database, error) {
+	if n.config.DataDir == "" {
+		return ethdb.NewMemDatabase(), nil
+	}
+	return openDatabaseWithFreezer(n.config, name, cache, handles, freezer, namespace)
+}
+
+func openDatabaseWithFreezer(config *Config, name string, cache, handles int, freezer, namespace string) (ethdb.Database, error) {
+	root := config.ResolvePath(name)
+
+	switch {
+	case freezer == "":
+		freezer = filepath.Join(root, "ancient")
+	case !filepath.IsAbs(freezer):
+		freezer = config.ResolvePath(freezer)
+	}
+
+	kvdb, err := ethdb.NewLDBDatabase(root, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		kvdb.Meter(namespace)
+	}
+
+	db, err := rawdb.NewDatabaseWithFreezer(kvdb, freezer, namespace)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.ResolvePath(x)
@@ -50,6 +50,18 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (et
 	return db, nil
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data
+// directory, also attaching a freezer for finalized blocks. The freezer
+// directory defaults to "ancient" within the database. If the node is
+// an ephemeral one, a memory database is returned.
+func (ctx *ServiceContext) OpenDatabaseWithFreezer(name string, cache int, handles int, freezer string, namespace string) (ethdb.Database, error) {
+	if ctx.config.DataDir == "" {
+		return ethdb.NewMemDatabase(), nil
+	}
+	return openDatabaseWithFreezer(ctx.config, name, cache, handles, freezer, namespace)
+}
+
 // ResolvePath resolves a user path into the data directory if that was relative
 // and if the user actually uses persistent storage. It will return an empty string
 // for emphemeral storage and the user's own input for absolute paths.
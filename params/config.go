@@ -235,8 +235,28 @@ type EnergiConfig struct {
 	MigrationSigner common.Address `json:"migrationSigner"`
 	EBISigner       common.Address `json:"ebiSigner"`
 	CPPSigner       common.Address `json:"cppSigner"`
+
+	// Remote checkpoint acceptance rule, see CheckpointPolicy* constants.
+	CheckpointPolicy string `json:"checkpointPolicy,omitempty"`
+	// Percent of the active masternode collateral required to sign
+	// a checkpoint. Zero means DefaultCheckpointQuorum.
+	CheckpointQuorum uint64 `json:"checkpointQuorum,omitempty"`
 }
 
+const (
+	// CheckpointPolicyCPP accepts checkpoints signed by CPPSigner.
+	CheckpointPolicyCPP = "cpp"
+	// CheckpointPolicyCPPAndMN accepts checkpoints signed by CPPSigner,
+	// if they are also signed by a masternode quorum.
+	CheckpointPolicyCPPAndMN = "cpp+mn"
+	// CheckpointPolicyMN accepts checkpoints signed by a masternode quorum.
+	CheckpointPolicyMN = "mn"
+
+	// DefaultCheckpointQuorum is the default masternode collateral quorum
+	// in percent.
+	DefaultCheckpointQuorum uint64 = 51
+)
+
 // String implements the stringer interface, returning the consensus engine details.
 func (*EnergiConfig) String() string {
 	return "energi"
@@ -31,10 +31,13 @@ import (
 	"energi.world/core/gen3/accounts/usbwallet"
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/common/hexutil"
+	"energi.world/core/gen3/core/types"
 	"energi.world/core/gen3/crypto"
 	"energi.world/core/gen3/internal/ethapi"
 	"energi.world/core/gen3/log"
 	"energi.world/core/gen3/rlp"
+
+	energi_consensus "energi.world/core/gen3/energi/consensus"
 )
 
 // numberOfAccountsToDerive For hardware wallets, the number of accounts to derive
@@ -50,6 +53,8 @@ type ExternalAPI interface {
 	SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
 	// Sign - request to sign the given data (plus prefix)
 	Sign(ctx context.Context, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error)
+	// SignEnergiBlock - request to sign the given RLP-encoded Energi PoS block header
+	SignEnergiBlock(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error)
 	// Export - request to export an account
 	Export(ctx context.Context, addr common.Address) (json.RawMessage, error)
 	// Import - request to import an account
@@ -64,6 +69,8 @@ type SignerUI interface {
 	ApproveTx(request *SignTxRequest) (SignTxResponse, error)
 	// ApproveSignData prompt the user for confirmation to request to sign data
 	ApproveSignData(request *SignDataRequest) (SignDataResponse, error)
+	// ApproveSignBlock prompt the user for confirmation to request to sign an Energi PoS block
+	ApproveSignBlock(request *SignBlockRequest) (SignBlockResponse, error)
 	// ApproveExport prompt the user for confirmation to export encrypted Account json
 	ApproveExport(request *ExportRequest) (ExportResponse, error)
 	// ApproveImport prompt the user for confirmation to import Account json
@@ -181,6 +188,18 @@ type (
 		Approved bool `json:"approved"`
 		Password string
 	}
+	SignBlockRequest struct {
+		Address  common.MixedcaseAddress `json:"address"`
+		Coinbase common.Address          `json:"coinbase"`
+		Number   *hexutil.Big            `json:"number"`
+		Header   *types.Header           `json:"header"`
+		Hash     hexutil.Bytes           `json:"hash"`
+		Meta     Metadata                `json:"meta"`
+	}
+	SignBlockResponse struct {
+		Approved bool `json:"approved"`
+		Password string
+	}
 	NewAccountRequest struct {
 		Meta Metadata `json:"meta"`
 	}
@@ -549,6 +568,49 @@ func (api *SignerAPI) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 	return signature, nil
 }
 
+// SignEnergiBlock signs the signature hash of an Energi PoS block header. The hash
+// is calculated by the signer itself from the RLP-encoded header, so the rules
+// are able to verify what exactly gets signed, e.g. the block coinbase.
+//
+// Note, the produced signature conforms to the secp256k1 curve R, S and V values,
+// where the V value is 0 or 1 as expected by the consensus engine.
+func (api *SignerAPI) SignEnergiBlock(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error) {
+	var block types.Header
+	if err := rlp.DecodeBytes(header, &block); err != nil {
+		return nil, fmt.Errorf("invalid block header: %v", err)
+	}
+	sighash := energi_consensus.SignatureHash(&block)
+
+	req := &SignBlockRequest{
+		Address:  addr,
+		Coinbase: block.Coinbase,
+		Number:   (*hexutil.Big)(block.Number),
+		Header:   &block,
+		Hash:     sighash.Bytes(),
+		Meta:     MetadataFromContext(ctx),
+	}
+	res, err := api.UI.ApproveSignBlock(req)
+
+	if err != nil {
+		return nil, err
+	}
+	if !res.Approved {
+		return nil, ErrRequestDenied
+	}
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr.Address()}
+	wallet, err := api.am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignHashWithPassphrase(account, res.Password, sighash.Bytes())
+	if err != nil {
+		api.UI.ShowError(err.Error())
+		return nil, err
+	}
+	return signature, nil
+}
+
 // SignHash is a helper function that calculates a hash for the given message that can be
 // safely used to calculate a signature from.
 //
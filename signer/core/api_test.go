@@ -34,8 +34,11 @@ import (
 	"energi.world/core/gen3/common"
 	"energi.world/core/gen3/common/hexutil"
 	"energi.world/core/gen3/core/types"
+	"energi.world/core/gen3/crypto"
 	"energi.world/core/gen3/internal/ethapi"
 	"energi.world/core/gen3/rlp"
+
+	energi_consensus "energi.world/core/gen3/energi/consensus"
 )
 
 //Used for testing
@@ -76,6 +79,13 @@ func (ui *HeadlessUI) ApproveSignData(request *SignDataRequest) (SignDataRespons
 	return SignDataResponse{false, ""}, nil
 }
 
+func (ui *HeadlessUI) ApproveSignBlock(request *SignBlockRequest) (SignBlockResponse, error) {
+	if "Y" == <-ui.controller {
+		return SignBlockResponse{true, <-ui.controller}, nil
+	}
+	return SignBlockResponse{false, ""}, nil
+}
+
 func (ui *HeadlessUI) ApproveExport(request *ExportRequest) (ExportResponse, error) {
 	return ExportResponse{<-ui.controller == "Y"}, nil
 
@@ -285,6 +295,61 @@ func TestSignData(t *testing.T) {
 		t.Errorf("Expected 65 byte signature (got %d bytes)", len(h))
 	}
 }
+
+func TestSignEnergiBlock(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0])
+
+	header := &types.Header{
+		Coinbase:   list[0],
+		Number:     big.NewInt(100),
+		Difficulty: big.NewInt(1),
+		Time:       1234567,
+	}
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	control <- "No way"
+	sig, err := api.SignEnergiBlock(context.Background(), a, enc)
+	if sig != nil {
+		t.Errorf("Expected nil-data, got %x", sig)
+	}
+	if err != ErrRequestDenied {
+		t.Errorf("Expected ErrRequestDenied! %v", err)
+	}
+
+	_, err = api.SignEnergiBlock(context.Background(), a, []byte{0x01, 0x02})
+	if err == nil {
+		t.Errorf("Expected error for invalid header")
+	}
+
+	control <- "Y"
+	control <- "a_long_password"
+	sig, err = api.SignEnergiBlock(context.Background(), a, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The signature must be accepted by the consensus engine as is
+	sighash := energi_consensus.SignatureHash(header)
+	pubkey, err := crypto.Ecrecover(sighash.Bytes(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	if signer != list[0] {
+		t.Errorf("Expected signer %x, got %x", list[0], signer)
+	}
+}
+
 func mkTestTx(from common.MixedcaseAddress) SendTxArgs {
 	to := common.NewMixedcaseAddress(common.HexToAddress("0x1337"))
 	gas := hexutil.Uint64(21000)
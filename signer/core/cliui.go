@@ -177,6 +177,25 @@ func (ui *CommandlineUI) ApproveSignData(request *SignDataRequest) (SignDataResp
 	return SignDataResponse{true, ui.readPassword()}, nil
 }
 
+// ApproveSignBlock prompt the user for confirmation to request to sign an Energi PoS block
+func (ui *CommandlineUI) ApproveSignBlock(request *SignBlockRequest) (SignBlockResponse, error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	fmt.Printf("-------- Sign block request--------------\n")
+	fmt.Printf("Account:  %s\n", request.Address.String())
+	fmt.Printf("coinbase: %s\n", request.Coinbase.String())
+	fmt.Printf("number:   %v\n", request.Number)
+	fmt.Printf("parent:   %s\n", request.Header.ParentHash.String())
+	fmt.Printf("block hash to sign:  %v\n", request.Hash)
+	fmt.Printf("-------------------------------------------\n")
+	showMetadata(request.Meta)
+	if !ui.confirm() {
+		return SignBlockResponse{false, ""}, nil
+	}
+	return SignBlockResponse{true, ui.readPassword()}, nil
+}
+
 // ApproveExport prompt the user for confirmation to export encrypted Account json
 func (ui *CommandlineUI) ApproveExport(request *ExportRequest) (ExportResponse, error) {
 	ui.mu.Lock()
@@ -61,6 +61,12 @@ func (ui *StdIOUI) ApproveSignData(request *SignDataRequest) (SignDataResponse,
 	return result, err
 }
 
+func (ui *StdIOUI) ApproveSignBlock(request *SignBlockRequest) (SignBlockResponse, error) {
+	var result SignBlockResponse
+	err := ui.dispatch("ApproveSignBlock", request, &result)
+	return result, err
+}
+
 func (ui *StdIOUI) ApproveExport(request *ExportRequest) (ExportResponse, error) {
 	var result ExportResponse
 	err := ui.dispatch("ApproveExport", request, &result)
@@ -176,6 +176,19 @@ func (r *rulesetUI) ApproveSignData(request *core.SignDataRequest) (core.SignDat
 	return core.SignDataResponse{Approved: false, Password: ""}, err
 }
 
+func (r *rulesetUI) ApproveSignBlock(request *core.SignBlockRequest) (core.SignBlockResponse, error) {
+	jsonreq, err := json.Marshal(request)
+	approved, err := r.checkApproval("ApproveSignBlock", jsonreq, err)
+	if err != nil {
+		log.Info("Rule-based approval error, going to manual", "error", err)
+		return r.next.ApproveSignBlock(request)
+	}
+	if approved {
+		return core.SignBlockResponse{Approved: true, Password: r.lookupPassword(request.Address.Address())}, nil
+	}
+	return core.SignBlockResponse{Approved: false, Password: ""}, err
+}
+
 func (r *rulesetUI) ApproveExport(request *core.ExportRequest) (core.ExportResponse, error) {
 	jsonreq, err := json.Marshal(request)
 	approved, err := r.checkApproval("ApproveExport", jsonreq, err)
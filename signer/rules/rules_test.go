@@ -94,6 +94,10 @@ func (alwaysDenyUI) ApproveSignData(request *core.SignDataRequest) (core.SignDat
 	return core.SignDataResponse{Approved: false, Password: ""}, nil
 }
 
+func (alwaysDenyUI) ApproveSignBlock(request *core.SignBlockRequest) (core.SignBlockResponse, error) {
+	return core.SignBlockResponse{Approved: false, Password: ""}, nil
+}
+
 func (alwaysDenyUI) ApproveExport(request *core.ExportRequest) (core.ExportResponse, error) {
 	return core.ExportResponse{Approved: false}, nil
 }
@@ -224,6 +228,11 @@ func (d *dummyUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataR
 	return core.SignDataResponse{}, core.ErrRequestDenied
 }
 
+func (d *dummyUI) ApproveSignBlock(request *core.SignBlockRequest) (core.SignBlockResponse, error) {
+	d.calls = append(d.calls, "ApproveSignBlock")
+	return core.SignBlockResponse{}, core.ErrRequestDenied
+}
+
 func (d *dummyUI) ApproveExport(request *core.ExportRequest) (core.ExportResponse, error) {
 	d.calls = append(d.calls, "ApproveExport")
 	return core.ExportResponse{}, core.ErrRequestDenied
@@ -550,6 +559,11 @@ func (d *dontCallMe) ApproveSignData(request *core.SignDataRequest) (core.SignDa
 	return core.SignDataResponse{}, core.ErrRequestDenied
 }
 
+func (d *dontCallMe) ApproveSignBlock(request *core.SignBlockRequest) (core.SignBlockResponse, error) {
+	d.t.Fatalf("Did not expect next-handler to be called")
+	return core.SignBlockResponse{}, core.ErrRequestDenied
+}
+
 func (d *dontCallMe) ApproveExport(request *core.ExportRequest) (core.ExportResponse, error) {
 	d.t.Fatalf("Did not expect next-handler to be called")
 	return core.ExportResponse{}, core.ErrRequestDenied
@@ -657,3 +671,44 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+func TestStakingRules(t *testing.T) {
+	var (
+		staker  = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		other   = common.HexToAddress("0x000000000000000000000000000000000000beef")
+		creds   = storage.NewEphemeralStorage()
+		next    = &dummyUI{}
+		signReq = func(coinbase common.Address) *core.SignBlockRequest {
+			return &core.SignBlockRequest{
+				Address:  common.NewMixedcaseAddress(staker),
+				Coinbase: coinbase,
+				Number:   (*hexutil.Big)(big.NewInt(1)),
+				Header:   &types.Header{Coinbase: coinbase, Number: big.NewInt(1)},
+			}
+		}
+	)
+	creds.Put(strings.ToLower(staker.String()), "secret")
+
+	r := NewStakingRules(next, creds, []common.Address{staker})
+
+	resp, err := r.ApproveSignBlock(signReq(staker))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !resp.Approved || resp.Password != "secret" {
+		t.Errorf("Expected approval with stored password, got %v", resp)
+	}
+
+	resp, err = r.ApproveSignBlock(signReq(other))
+	if err != core.ErrRequestDenied || resp.Approved {
+		t.Errorf("Expected rejection of non-whitelisted coinbase, got %v %v", resp, err)
+	}
+
+	// Everything else goes to the next handler
+	r.ApproveTx(&core.SignTxRequest{})
+	r.ApproveSignData(&core.SignDataRequest{})
+	expected := "ApproveTx,ApproveSignData"
+	if got := strings.Join(next.calls, ","); got != expected {
+		t.Errorf("Expected calls %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Energi Core Authors
+// This file is part of the Energi Core library.
+//
+// The Energi Core library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Energi Core library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Energi Core library. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"strings"
+
+	"energi.world/core/gen3/common"
+	"energi.world/core/gen3/log"
+	"energi.world/core/gen3/signer/core"
+	"energi.world/core/gen3/signer/storage"
+)
+
+// stakingUI provides an implementation of SignerUI which approves Energi PoS
+// block signatures for whitelisted coinbases without user interaction. Block
+// signatures for any other coinbase are rejected. All other requests are
+// passed to the next handler.
+type stakingUI struct {
+	core.SignerUI // The next handler, for everything except block signatures
+
+	credentials storage.Storage
+	coinbases   map[common.Address]bool
+}
+
+func NewStakingRules(next core.SignerUI, credentialsBackend storage.Storage, coinbases []common.Address) *stakingUI {
+	s := &stakingUI{
+		SignerUI:    next,
+		credentials: credentialsBackend,
+		coinbases:   make(map[common.Address]bool, len(coinbases)),
+	}
+
+	for _, addr := range coinbases {
+		s.coinbases[addr] = true
+	}
+
+	return s
+}
+
+func (s *stakingUI) ApproveSignBlock(request *core.SignBlockRequest) (core.SignBlockResponse, error) {
+	if !s.coinbases[request.Coinbase] {
+		log.Warn("Rejected block signature for non-whitelisted coinbase",
+			"coinbase", request.Coinbase, "signer", request.Address.Address())
+		return core.SignBlockResponse{Approved: false}, core.ErrRequestDenied
+	}
+
+	log.Info("Approved block signature", "coinbase", request.Coinbase, "number", request.Number)
+
+	signer := strings.ToLower(request.Address.Address().String())
+
+	return core.SignBlockResponse{
+		Approved: true,
+		Password: s.credentials.Get(signer),
+	}, nil
+}